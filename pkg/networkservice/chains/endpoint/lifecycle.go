@@ -0,0 +1,210 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/networkservicemesh/api/pkg/api"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+const (
+	defaultRegisterAttempts  = 5
+	defaultRegisterBackoff   = 100 * time.Millisecond
+	defaultRegisterMaxDelay  = 5 * time.Second
+	defaultUnregisterTimeout = 5 * time.Second
+)
+
+type lifecycleOptions struct {
+	serverOptions     []grpc.ServerOption
+	registerAttempts  int
+	registerBackoff   time.Duration
+	registerMaxDelay  time.Duration
+	unregisterTimeout time.Duration
+	withoutUnregister bool
+}
+
+// LifecycleOption is an option pattern for ServeAndRegister
+type LifecycleOption func(o *lifecycleOptions)
+
+// WithServerOptions sets the options for the grpc.Server serving the endpoint
+func WithServerOptions(serverOptions ...grpc.ServerOption) LifecycleOption {
+	return func(o *lifecycleOptions) {
+		o.serverOptions = serverOptions
+	}
+}
+
+// WithRegisterRetries sets the max count of the NSE registration attempts including the first one and the backoff
+// before the first retry, the backoff is doubled after every retry up to maxBackoff. 0 attempts means no limit: the
+// registration is retried until the ctx is done.
+func WithRegisterRetries(attempts int, backoff, maxBackoff time.Duration) LifecycleOption {
+	return func(o *lifecycleOptions) {
+		o.registerAttempts = attempts
+		o.registerBackoff = backoff
+		o.registerMaxDelay = maxBackoff
+	}
+}
+
+// WithUnregisterTimeout sets the timeout for the NSE unregistration on shutdown
+func WithUnregisterTimeout(unregisterTimeout time.Duration) LifecycleOption {
+	return func(o *lifecycleOptions) {
+		o.unregisterTimeout = unregisterTimeout
+	}
+}
+
+// WithoutUnregister disables the NSE unregistration on shutdown, the registration expires after the refreshes stop
+func WithoutUnregister() LifecycleOption {
+	return func(o *lifecycleOptions) {
+		o.withoutUnregister = true
+	}
+}
+
+// ServeAndRegister serves the endpoint on listenOn and registers nse with the actual listen URL using
+// registryClient. registryClient is expected to keep the registration refreshed, e.g. the one created with
+// registry/chains/client.NewNetworkServiceEndpointRegistryClient. The registration is retried on failure.
+// nse is updated with the registered URL, name and expiration time.
+//
+// The endpoint health services report NOT_SERVING until the NSE is registered. When ctx is done, the health services
+// switch to NOT_SERVING, the NSE is unregistered and the endpoint stops serving.
+//
+// Returned channel receives the serving errors, ServeAndRegister returns an error if the NSE registration fails.
+func ServeAndRegister(
+	ctx context.Context,
+	listenOn *url.URL,
+	endpoint Endpoint,
+	nse *registry.NetworkServiceEndpoint,
+	registryClient registry.NetworkServiceEndpointRegistryClient,
+	options ...LifecycleOption,
+) (<-chan error, error) {
+	o := &lifecycleOptions{
+		registerAttempts:  defaultRegisterAttempts,
+		registerBackoff:   defaultRegisterBackoff,
+		registerMaxDelay:  defaultRegisterMaxDelay,
+		unregisterTimeout: defaultUnregisterTimeout,
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+
+	logger := log.FromContext(ctx).WithField("endpoint", "ServeAndRegister")
+	clockTime := clock.FromContext(ctx)
+
+	// Health server is registered before the endpoint, so the endpoint doesn't register its own one
+	healthServer := health.NewServer()
+	serviceNames := append([]string{""}, api.ServiceNames(endpoint)...)
+	for _, serviceName := range serviceNames {
+		healthServer.SetServingStatus(serviceName, grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	}
+
+	server := grpc.NewServer(o.serverOptions...)
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+	endpoint.Register(server)
+
+	// Listen before registering, so the registered URL is already accepting connections
+	ln, err := grpcutils.Listen(ctx, listenOn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to listen on %s", listenOn.String())
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(errCh)
+		if err := server.Serve(ln); err != nil {
+			errCh <- err
+		}
+	}()
+
+	nse.Url = listenOn.String()
+	reg, err := register(ctx, nse, registryClient, o)
+	if err != nil {
+		server.Stop()
+		return nil, err
+	}
+	nse.Name = reg.Name
+	nse.ExpirationTime = reg.ExpirationTime
+
+	for _, serviceName := range serviceNames {
+		healthServer.SetServingStatus(serviceName, grpc_health_v1.HealthCheckResponse_SERVING)
+	}
+	logger.Infof("NSE %s is registered and serving on %s", nse.Name, nse.Url)
+
+	go func() {
+		defer server.Stop()
+
+		<-ctx.Done()
+		healthServer.Shutdown()
+
+		if o.withoutUnregister {
+			return
+		}
+
+		unregisterCtx, cancelUnregister := clockTime.WithTimeout(log.WithLog(context.Background(), logger), o.unregisterTimeout)
+		defer cancelUnregister()
+
+		if _, err := registryClient.Unregister(unregisterCtx, reg.Clone()); err != nil {
+			logger.Warnf("failed to unregister NSE %s: %s", reg.Name, err.Error())
+		}
+	}()
+
+	return errCh, nil
+}
+
+func register(
+	ctx context.Context,
+	nse *registry.NetworkServiceEndpoint,
+	registryClient registry.NetworkServiceEndpointRegistryClient,
+	o *lifecycleOptions,
+) (*registry.NetworkServiceEndpoint, error) {
+	logger := log.FromContext(ctx).WithField("endpoint", "register")
+	clockTime := clock.FromContext(ctx)
+
+	backoff := o.registerBackoff
+	for attempt := 1; ; attempt++ {
+		reg, err := registryClient.Register(ctx, nse.Clone())
+		if err == nil {
+			return reg, nil
+		}
+		if o.registerAttempts > 0 && attempt >= o.registerAttempts {
+			return nil, errors.Wrapf(err, "failed to register NSE %s after %d attempts", nse.Name, attempt)
+		}
+		logger.Warnf("retrying in %s after the attempt %d: %s", backoff, attempt, err.Error())
+
+		timer := clockTime.Timer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, errors.Wrapf(err, "failed to register NSE %s", nse.Name)
+		case <-timer.C():
+		}
+
+		if backoff *= 2; backoff > o.registerMaxDelay {
+			backoff = o.registerMaxDelay
+		}
+	}
+}
@@ -0,0 +1,241 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint_test
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/chains/endpoint"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+	"github.com/networkservicemesh/sdk/pkg/tools/sandbox"
+)
+
+type failRegisterClient struct {
+	calls    int32
+	failures int32
+
+	registry.NetworkServiceEndpointRegistryClient
+}
+
+func (c *failRegisterClient) Register(ctx context.Context, nse *registry.NetworkServiceEndpoint, opts ...grpc.CallOption) (*registry.NetworkServiceEndpoint, error) {
+	if atomic.AddInt32(&c.calls, 1) <= c.failures {
+		return nil, errors.New("registry is not ready")
+	}
+	return next.NetworkServiceEndpointRegistryClient(ctx).Register(ctx, nse, opts...)
+}
+
+func (c *failRegisterClient) Find(ctx context.Context, query *registry.NetworkServiceEndpointQuery, opts ...grpc.CallOption) (registry.NetworkServiceEndpointRegistry_FindClient, error) {
+	return next.NetworkServiceEndpointRegistryClient(ctx).Find(ctx, query, opts...)
+}
+
+func (c *failRegisterClient) Unregister(ctx context.Context, nse *registry.NetworkServiceEndpoint, opts ...grpc.CallOption) (*empty.Empty, error) {
+	return next.NetworkServiceEndpointRegistryClient(ctx).Unregister(ctx, nse, opts...)
+}
+
+func findNSEs(ctx context.Context, t *testing.T, registryClient registry.NetworkServiceEndpointRegistryClient) []*registry.NetworkServiceEndpoint {
+	stream, err := registryClient.Find(ctx, &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: new(registry.NetworkServiceEndpoint),
+	})
+	require.NoError(t, err)
+	return registry.ReadNetworkServiceEndpointList(stream)
+}
+
+func TestServeAndRegister(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	registryClient := next.NewNetworkServiceEndpointRegistryClient(
+		&failRegisterClient{failures: 2},
+		adapters.NetworkServiceEndpointServerToClient(memory.NewNetworkServiceEndpointRegistryServer()),
+	)
+
+	nse := &registry.NetworkServiceEndpoint{
+		Name:                "nse",
+		NetworkServiceNames: []string{"ns"},
+	}
+
+	serveCtx, cancelServe := context.WithCancel(ctx)
+	defer cancelServe()
+
+	ep := endpoint.NewServer(serveCtx, sandbox.GenerateTestToken, endpoint.WithName(nse.Name))
+	listenOn := &url.URL{Scheme: "tcp", Host: "127.0.0.1:0"}
+
+	errCh, err := endpoint.ServeAndRegister(serveCtx, listenOn, ep, nse, registryClient,
+		endpoint.WithRegisterRetries(3, time.Millisecond, time.Millisecond))
+	require.NoError(t, err)
+	require.Equal(t, listenOn.String(), nse.Url)
+
+	nses := findNSEs(ctx, t, registryClient)
+	require.Len(t, nses, 1)
+	require.Equal(t, nse.Url, nses[0].Url)
+
+	cc, err := grpc.DialContext(ctx, listenOn.Host, grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	defer func() { _ = cc.Close() }()
+
+	resp, err := grpc_health_v1.NewHealthClient(cc).Check(ctx, new(grpc_health_v1.HealthCheckRequest))
+	require.NoError(t, err)
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, resp.Status)
+
+	cancelServe()
+
+	require.Eventually(t, func() bool {
+		return len(findNSEs(ctx, t, registryClient)) == 0
+	}, time.Second, 10*time.Millisecond)
+
+	for range errCh {
+	}
+}
+
+func TestServeAndRegister_RegisterFailed(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	registryClient := next.NewNetworkServiceEndpointRegistryClient(
+		&failRegisterClient{failures: 3},
+		adapters.NetworkServiceEndpointServerToClient(memory.NewNetworkServiceEndpointRegistryServer()),
+	)
+
+	nse := &registry.NetworkServiceEndpoint{
+		Name:                "nse",
+		NetworkServiceNames: []string{"ns"},
+	}
+
+	ep := endpoint.NewServer(ctx, sandbox.GenerateTestToken, endpoint.WithName(nse.Name))
+	listenOn := &url.URL{Scheme: "tcp", Host: "127.0.0.1:0"}
+
+	_, err := endpoint.ServeAndRegister(ctx, listenOn, ep, nse, registryClient,
+		endpoint.WithRegisterRetries(3, time.Millisecond, time.Millisecond))
+	require.Error(t, err)
+
+	require.Empty(t, findNSEs(ctx, t, registryClient))
+}
+
+func TestServeAndRegister_ListenFailed(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = ln.Close() }()
+
+	registryClient := next.NewNetworkServiceEndpointRegistryClient(
+		new(failRegisterClient),
+		adapters.NetworkServiceEndpointServerToClient(memory.NewNetworkServiceEndpointRegistryServer()),
+	)
+
+	nse := &registry.NetworkServiceEndpoint{
+		Name:                "nse",
+		NetworkServiceNames: []string{"ns"},
+	}
+
+	ep := endpoint.NewServer(ctx, sandbox.GenerateTestToken, endpoint.WithName(nse.Name))
+	listenOn := &url.URL{Scheme: "tcp", Host: ln.Addr().String()}
+
+	_, err = endpoint.ServeAndRegister(ctx, listenOn, ep, nse, registryClient)
+	require.Error(t, err)
+
+	require.Empty(t, findNSEs(ctx, t, registryClient))
+}
+
+func TestServeAndRegister_RegisterBackoff(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	clockMock := clockmock.NewMock()
+	ctx, cancel := context.WithCancel(clock.WithClock(context.Background(), clockMock))
+	defer cancel()
+
+	failClient := &failRegisterClient{failures: 2}
+	registryClient := next.NewNetworkServiceEndpointRegistryClient(
+		failClient,
+		adapters.NetworkServiceEndpointServerToClient(memory.NewNetworkServiceEndpointRegistryServer()),
+	)
+
+	nse := &registry.NetworkServiceEndpoint{
+		Name:                "nse",
+		NetworkServiceNames: []string{"ns"},
+	}
+
+	serveCtx, cancelServe := context.WithCancel(ctx)
+	defer cancelServe()
+
+	ep := endpoint.NewServer(serveCtx, sandbox.GenerateTestToken, endpoint.WithName(nse.Name))
+	listenOn := &url.URL{Scheme: "tcp", Host: "127.0.0.1:0"}
+
+	type result struct {
+		errCh <-chan error
+		err   error
+	}
+	resultCh := make(chan result, 1)
+	go func() {
+		errCh, err := endpoint.ServeAndRegister(serveCtx, listenOn, ep, nse, registryClient,
+			endpoint.WithRegisterRetries(3, time.Second, time.Minute))
+		resultCh <- result{errCh: errCh, err: err}
+	}()
+
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&failClient.calls) == 1 }, time.Second, 10*time.Millisecond)
+
+	clockMock.Add(time.Second - 1)
+	require.Never(t, func() bool { return atomic.LoadInt32(&failClient.calls) > 1 }, 100*time.Millisecond, 10*time.Millisecond)
+
+	clockMock.Add(1)
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&failClient.calls) == 2 }, time.Second, 10*time.Millisecond)
+
+	// The backoff is doubled after the retry
+	clockMock.Add(2*time.Second - 1)
+	require.Never(t, func() bool { return atomic.LoadInt32(&failClient.calls) > 2 }, 100*time.Millisecond, 10*time.Millisecond)
+
+	clockMock.Add(1)
+
+	var r result
+	select {
+	case r = <-resultCh:
+	case <-time.After(time.Second):
+		require.FailNow(t, "NSE is not registered")
+	}
+	require.NoError(t, r.err)
+	require.Len(t, findNSEs(ctx, t, registryClient), 1)
+
+	cancelServe()
+
+	for range r.errCh {
+	}
+}
@@ -22,6 +22,7 @@ package endpoint
 import (
 	"context"
 	"net/url"
+	"time"
 
 	"google.golang.org/grpc"
 
@@ -29,6 +30,9 @@ import (
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/authorize"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/drain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/null"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/quota"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/serialize"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/updatetoken"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
@@ -49,16 +53,22 @@ type Endpoint interface {
 	networkservice.MonitorConnectionServer
 	// Register - register the endpoint with *grpc.Server s
 	Register(s *grpc.Server)
+	// Drain - rejects the new connections, keeps serving the existing ones for the gracePeriod and then closes them,
+	// so the clients heal to the other endpoints. See drain.Server for details.
+	Drain(ctx context.Context, gracePeriod time.Duration) error
 }
 
 type endpoint struct {
 	networkservice.NetworkServiceServer
 	networkservice.MonitorConnectionServer
+	drainServer drain.Server
 }
 
 type serverOptions struct {
 	name                    string
 	authorizeServer         networkservice.NetworkServiceServer
+	quotaOptions            []quota.Option
+	drainOptions            []drain.Option
 	additionalFunctionality []networkservice.NetworkServiceServer
 }
 
@@ -82,6 +92,21 @@ func WithAuthorizeServer(authorizeServer networkservice.NetworkServiceServer) Op
 	}
 }
 
+// WithQuotaOptions enables the connection quotas for the Network Services, the client identities and the endpoints
+// with the options. Quotas are disabled by default.
+func WithQuotaOptions(options ...quota.Option) Option {
+	return func(o *serverOptions) {
+		o.quotaOptions = options
+	}
+}
+
+// WithDrainOptions sets the options for the endpoint Drain, e.g. the registry client to mark the NSE as draining
+func WithDrainOptions(options ...drain.Option) Option {
+	return func(o *serverOptions) {
+		o.drainOptions = options
+	}
+}
+
 // WithAdditionalFunctionality sets additional NetworkServiceServer chain elements to be included in the chain
 func WithAdditionalFunctionality(additionalFunctionality ...networkservice.NetworkServiceServer) Option {
 	return func(o *serverOptions) {
@@ -99,7 +124,13 @@ func NewServer(ctx context.Context, tokenGenerator token.GeneratorFunc, options
 		opt(opts)
 	}
 
+	var quotaServer networkservice.NetworkServiceServer = null.NewServer()
+	if opts.quotaOptions != nil {
+		quotaServer = quota.NewServer(ctx, opts.quotaOptions...)
+	}
+
 	rv := &endpoint{}
+	rv.drainServer = drain.NewServer(ctx, &rv.NetworkServiceServer, opts.drainOptions...)
 	rv.NetworkServiceServer = chain.NewNamedNetworkServiceServer(
 		opts.name,
 		append([]networkservice.NetworkServiceServer{
@@ -110,6 +141,8 @@ func NewServer(ctx context.Context, tokenGenerator token.GeneratorFunc, options
 			// chain elements before the `timeout` in chain shouldn't make any updates to the Close context and
 			// shouldn't be closed on Connection Close.
 			timeout.NewServer(ctx),
+			rv.drainServer,
+			quotaServer,
 			metadata.NewServer(),
 			monitor.NewServer(ctx, &rv.MonitorConnectionServer),
 			updatetoken.NewServer(tokenGenerator),
@@ -123,6 +156,10 @@ func (e *endpoint) Register(s *grpc.Server) {
 	networkservice.RegisterMonitorConnectionServer(s, e)
 }
 
+func (e *endpoint) Drain(ctx context.Context, gracePeriod time.Duration) error {
+	return e.drainServer.Drain(ctx, gracePeriod)
+}
+
 // Serve  - serves passed Endpoint on grpc
 func Serve(ctx context.Context, listenOn *url.URL, endpoint Endpoint, opt ...grpc.ServerOption) <-chan error {
 	server := grpc.NewServer(opt...)
@@ -30,6 +30,8 @@ import (
 
 	"github.com/networkservicemesh/sdk/pkg/networkservice/chains/client"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/chains/endpoint"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/checkpoint"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/circuitbreaker"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/connect"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discover"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/excludedprefixes"
@@ -38,22 +40,30 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/interpose"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/recvfd"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/mechanisms/sendfd"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/null"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/quota"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/roundrobin"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/selectendpoint"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/adapters"
 	"github.com/networkservicemesh/sdk/pkg/registry"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/expire"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/filestore"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/localbypass"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	registrynull "github.com/networkservicemesh/sdk/pkg/registry/common/null"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/querycache"
 	registryrecvfd "github.com/networkservicemesh/sdk/pkg/registry/common/recvfd"
 	registryserialize "github.com/networkservicemesh/sdk/pkg/registry/common/serialize"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/setid"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/validatematches"
 	registryadapter "github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
 	registrychain "github.com/networkservicemesh/sdk/pkg/registry/core/chain"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/nextwrap"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/admin"
 	"github.com/networkservicemesh/sdk/pkg/tools/addressof"
 	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
 	"github.com/networkservicemesh/sdk/pkg/tools/token"
 )
 
@@ -67,46 +77,118 @@ type Nsmgr interface {
 type nsmgrServer struct {
 	endpoint.Endpoint
 	registry.Registry
+	adminServer admin.AdminServer
 }
 
 var _ Nsmgr = (*nsmgrServer)(nil)
 
+type serverOptions struct {
+	dialOptions     []grpc.DialOption
+	selectorOptions []selectendpoint.Option
+	breaker         *circuitbreaker.Breaker
+	withAdmin       bool
+	adminOptions    []admin.Option
+	quotaOptions    []quota.Option
+	connStore       checkpoint.Server
+	nseStore        filestore.NetworkServiceEndpointRegistryServer
+}
+
+// Option modifies server option value
+type Option func(o *serverOptions)
+
+// WithDialOptions sets gRPC Dial Options to be passed to GRPC connections
+func WithDialOptions(dialOptions ...grpc.DialOption) Option {
+	return func(o *serverOptions) {
+		o.dialOptions = dialOptions
+	}
+}
+
+// WithSelectorOptions sets options for the endpoint selection. By default endpoints are selected with
+// roundrobin.NewSelector() for all Network Services.
+func WithSelectorOptions(selectorOptions ...selectendpoint.Option) Option {
+	return func(o *serverOptions) {
+		o.selectorOptions = selectorOptions
+	}
+}
+
+// WithCircuitBreaker sets circuit breaker for the selected endpoints. Default selector is wrapped with
+// circuitbreaker.NewSelector, selectors set with WithSelectorOptions should be wrapped by the caller.
+func WithCircuitBreaker(breaker *circuitbreaker.Breaker) Option {
+	return func(o *serverOptions) {
+		o.breaker = breaker
+	}
+}
+
+// WithAdmin enables the registry admin gRPC service with the options, e.g. the policies authorizing its calls. By
+// default the admin service is not registered.
+func WithAdmin(options ...admin.Option) Option {
+	return func(o *serverOptions) {
+		o.withAdmin = true
+		o.adminOptions = options
+	}
+}
+
+// WithQuotaOptions enables the connection quotas for the Network Services, the client identities and the endpoints
+// with the options. Quotas are disabled by default.
+func WithQuotaOptions(options ...quota.Option) Option {
+	return func(o *serverOptions) {
+		o.quotaOptions = options
+	}
+}
+
+// WithCheckpoint sets stores persisting the active connections and the local NSE and Forwarder registrations on
+// the local disk. State loaded by the stores is restored in background after NewServer: registrations are restored
+// first, so the restored connections are reconnected to the same Forwarders and NSEs without requesting them again.
+func WithCheckpoint(connStore checkpoint.Server, nseStore filestore.NetworkServiceEndpointRegistryServer) Option {
+	return func(o *serverOptions) {
+		o.connStore = connStore
+		o.nseStore = nseStore
+	}
+}
+
 // NewServer - Creates a new Nsmgr
 //           nsmRegistration - Nsmgr registration
 //           authzServer - authorization server chain element
 //           tokenGenerator - authorization token generator
 //           registryCC - client connection to reach the upstream registry, could be nil, in this case only in memory storage will be used.
-// 			 clientDialOptions -  a grpc.DialOption's to be passed to GRPC connections.
-func NewServer(ctx context.Context, nsmRegistration *registryapi.NetworkServiceEndpoint, authzServer networkservice.NetworkServiceServer, tokenGenerator token.GeneratorFunc, registryCC grpc.ClientConnInterface, clientDialOptions ...grpc.DialOption) Nsmgr {
-	rv := &nsmgrServer{}
+//           options - a set of Nsmgr options.
+// NewServer used to take the gRPC Dial Options as the variadic argument: `NewServer(..., registryCC, dialOptions...)`
+// should be changed to `NewServer(..., registryCC, WithDialOptions(dialOptions...))`.
+func NewServer(ctx context.Context, nsmRegistration *registryapi.NetworkServiceEndpoint, authzServer networkservice.NetworkServiceServer, tokenGenerator token.GeneratorFunc, registryCC grpc.ClientConnInterface, options ...Option) Nsmgr {
+	opts := new(serverOptions)
+	for _, opt := range options {
+		opt(opts)
+	}
 
-	var urlsRegistryServer, interposeRegistryServer registryapi.NetworkServiceEndpointRegistryServer
+	rv := &nsmgrServer{}
 
-	nsRegistry := newRemoteNSServer(registryCC)
-	if nsRegistry == nil {
-		// Use memory registry if no registry is passed
-		nsRegistry = registrychain.NewNetworkServiceRegistryServer(
-			registryserialize.NewNetworkServiceRegistryServer(),
-			memory.NewNetworkServiceRegistryServer(),
-		)
+	var defaultSelector selectendpoint.Selector = roundrobin.NewSelector()
+	var circuitBreakerServer networkservice.NetworkServiceServer = null.NewServer()
+	if opts.breaker != nil {
+		defaultSelector = circuitbreaker.NewSelector(opts.breaker, defaultSelector)
+		circuitBreakerServer = circuitbreaker.NewServer(opts.breaker)
 	}
 
-	nseRegistry := newRemoteNSEServer(registryCC)
-	if nseRegistry == nil {
-		// Use memory registry if no registry is passed
-		nseRegistry = registrychain.NewNetworkServiceEndpointRegistryServer(
-			registryserialize.NewNetworkServiceEndpointRegistryServer(),
-			memory.NewNetworkServiceEndpointRegistryServer(),
-			setid.NewNetworkServiceEndpointRegistryServer(),
-		)
+	var quotaServer networkservice.NetworkServiceServer = null.NewServer()
+	if opts.quotaOptions != nil {
+		quotaServer = quota.NewServer(ctx, opts.quotaOptions...)
 	}
 
+	connStore, nseStore := opts.stores()
+
+	var urlsRegistryServer, interposeRegistryServer registryapi.NetworkServiceEndpointRegistryServer
+
+	nsRegistry, nseRegistry, adminOptions := newRegistryServers(registryCC)
+
 	localBypassRegistryServer := localbypass.NewNetworkServiceEndpointRegistryServer(nsmRegistration.Url)
 
+	queryCacheClient := querycache.NewClient(ctx)
+	adminOptions = append(adminOptions, admin.WithQueryCaches(queryCacheClient.(querycache.ContentsProvider)))
+
 	nseClient := next.NewNetworkServiceEndpointRegistryClient(
 		registryserialize.NewNetworkServiceEndpointRegistryClient(),
 		registryadapter.NetworkServiceEndpointServerToClient(localBypassRegistryServer),
-		querycache.NewClient(ctx),
+		queryCacheClient,
 		registryadapter.NetworkServiceEndpointServerToClient(nseRegistry),
 	)
 
@@ -118,12 +200,17 @@ func NewServer(ctx context.Context, nsmRegistration *registryapi.NetworkServiceE
 		endpoint.WithAuthorizeServer(authzServer),
 		endpoint.WithAdditionalFunctionality(
 			discover.NewServer(nsClient, nseClient),
-			roundrobin.NewServer(),
+			selectendpoint.NewServer(append([]selectendpoint.Option{
+				selectendpoint.WithSelector(defaultSelector),
+			}, opts.selectorOptions...)...),
+			circuitBreakerServer,
+			quotaServer,
 			excludedprefixes.NewServer(ctx),
 			recvfd.NewServer(), // Receive any files passed
 			interpose.NewServer(&interposeRegistryServer),
 			filtermechanisms.NewServer(&urlsRegistryServer),
 			heal.NewServer(ctx, addressof.NetworkServiceClient(adapters.NewServerToClient(rv))),
+			connStore,
 			connect.NewServer(ctx,
 				client.NewClientFactory(
 					client.WithName(nsmRegistration.Name),
@@ -132,27 +219,97 @@ func NewServer(ctx context.Context, nsmRegistration *registryapi.NetworkServiceE
 						sendfd.NewClient(),
 					),
 				),
-				connect.WithDialOptions(clientDialOptions...)),
+				connect.WithDialOptions(opts.dialOptions...)),
 			sendfd.NewServer()),
 	)
 
 	nsChain := registrychain.NewNamedNetworkServiceRegistryServer(nsmRegistration.Name+".NetworkServiceRegistry", nsRegistry)
 
+	nseExpire := expire.NewNetworkServiceEndpointRegistryServer(ctx, time.Minute)
+
 	nseChain := registrychain.NewNamedNetworkServiceEndpointRegistryServer(
 		nsmRegistration.Name+".NetworkServiceEndpointRegistry",
 		registryserialize.NewNetworkServiceEndpointRegistryServer(),
-		expire.NewNetworkServiceEndpointRegistryServer(ctx, time.Minute),
+		nseStore,
+		nseExpire,
 		registryrecvfd.NewNetworkServiceEndpointRegistryServer(), // Allow to receive a passed files
 		urlsRegistryServer,        // Store endpoints URLs
 		interposeRegistryServer,   // Store cross connect NSEs
 		localBypassRegistryServer, // Perform URL transformations
 		nseRegistry,               // Register NSE inside Remote registry
 	)
-	rv.Registry = registry.NewServer(nsChain, nseChain)
+
+	var registryOptions []registry.Option
+	if opts.withAdmin {
+		rv.adminServer = admin.NewServer(ctx, append(append(adminOptions,
+			admin.WithTimers(nseExpire.(expire.TimersProvider)),
+			admin.WithUnregisterServer(nseChain),
+		), opts.adminOptions...)...)
+		registryOptions = append(registryOptions, registry.WithAdminServer(rv.adminServer))
+	}
+	rv.Registry = registry.NewServer(nsChain, nseChain, registryOptions...)
+
+	if opts.connStore != nil || opts.nseStore != nil {
+		go rv.restore(ctx, opts.connStore, opts.nseStore)
+	}
 
 	return rv
 }
 
+func (n *nsmgrServer) restore(ctx context.Context, connStore checkpoint.Server, nseStore filestore.NetworkServiceEndpointRegistryServer) {
+	logger := log.FromContext(ctx).WithField("nsmgrServer", "restore")
+	if nseStore != nil {
+		if err := nseStore.Restore(ctx, n.NetworkServiceEndpointRegistryServer()); err != nil {
+			logger.Errorf("failed to restore NSEs: %s", err.Error())
+		}
+	}
+	if connStore != nil {
+		if err := connStore.Restore(ctx, n); err != nil {
+			logger.Errorf("failed to restore connections: %s", err.Error())
+		}
+	}
+}
+
+// stores returns the checkpoint stores, or the null chain elements if no stores are set
+func (o *serverOptions) stores() (networkservice.NetworkServiceServer, registryapi.NetworkServiceEndpointRegistryServer) {
+	var connStore networkservice.NetworkServiceServer = null.NewServer()
+	if o.connStore != nil {
+		connStore = o.connStore
+	}
+	var nseStore registryapi.NetworkServiceEndpointRegistryServer = registrynull.NewNetworkServiceEndpointRegistryServer()
+	if o.nseStore != nil {
+		nseStore = o.nseStore
+	}
+	return connStore, nseStore
+}
+
+// newRegistryServers returns the remote registry servers, or the memory registry servers if no registryCC is passed
+func newRegistryServers(registryCC grpc.ClientConnInterface) (
+	nsRegistry registryapi.NetworkServiceRegistryServer,
+	nseRegistry registryapi.NetworkServiceEndpointRegistryServer,
+	adminOptions []admin.Option,
+) {
+	if registryCC != nil {
+		return newRemoteNSServer(registryCC), newRemoteNSEServer(registryCC), nil
+	}
+
+	nsMemory := memory.NewNetworkServiceRegistryServer()
+	nseMemory := memory.NewNetworkServiceEndpointRegistryServer()
+
+	nsRegistry = registrychain.NewNetworkServiceRegistryServer(
+		registryserialize.NewNetworkServiceRegistryServer(),
+		validatematches.NewNetworkServiceRegistryServer(),
+		nsMemory,
+	)
+	nseRegistry = registrychain.NewNetworkServiceEndpointRegistryServer(
+		registryserialize.NewNetworkServiceEndpointRegistryServer(),
+		nseMemory,
+		setid.NewNetworkServiceEndpointRegistryServer(),
+	)
+
+	return nsRegistry, nseRegistry, []admin.Option{admin.WithMemory(nsMemory, nseMemory)}
+}
+
 func newRemoteNSServer(cc grpc.ClientConnInterface) registryapi.NetworkServiceRegistryServer {
 	if cc != nil {
 		return registryadapter.NetworkServiceClientToServer(
@@ -176,6 +333,9 @@ func (n *nsmgrServer) Register(s *grpc.Server) {
 	networkservice.RegisterMonitorConnectionServer(s, n)
 	registryapi.RegisterNetworkServiceRegistryServer(s, n.Registry.NetworkServiceRegistryServer())
 	registryapi.RegisterNetworkServiceEndpointRegistryServer(s, n.Registry.NetworkServiceEndpointRegistryServer())
+	if n.adminServer != nil {
+		admin.RegisterAdminServer(s, n.adminServer)
+	}
 }
 
 var _ Nsmgr = &nsmgrServer{}
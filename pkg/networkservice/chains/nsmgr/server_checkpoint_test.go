@@ -0,0 +1,147 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsmgr_test
+
+import (
+	"context"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/cls"
+	kernelmech "github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/chains/nsmgr"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/checkpoint"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/filestore"
+	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
+	"github.com/networkservicemesh/sdk/pkg/tools/sandbox"
+	"github.com/networkservicemesh/sdk/pkg/tools/token"
+)
+
+func TestNSMGR_CheckpointRestore(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	dir := t.TempDir()
+	supplyNSMgr := func(ctx context.Context, nsmgrReg *registry.NetworkServiceEndpoint, authzServer networkservice.NetworkServiceServer,
+		tokenGenerator token.GeneratorFunc, registryCC grpc.ClientConnInterface, options ...nsmgr.Option) nsmgr.Nsmgr {
+		// Restarted NSMgr should have the same name
+		nsmgrReg.Name = "nsmgr"
+
+		connStore, err := checkpoint.NewServer(ctx, filepath.Join(dir, "connections"))
+		require.NoError(t, err)
+		nseStore, err := filestore.NewNetworkServiceEndpointRegistryServer(ctx, filepath.Join(dir, "endpoints"))
+		require.NoError(t, err)
+
+		return nsmgr.NewServer(ctx, nsmgrReg, authzServer, tokenGenerator, registryCC,
+			append(options, nsmgr.WithCheckpoint(connStore, nseStore))...)
+	}
+
+	nsmgrCtx, nsmgrCtxCancel := context.WithCancel(ctx)
+	defer nsmgrCtxCancel()
+
+	builder := sandbox.NewBuilder(t)
+	domain := builder.
+		SetNodesCount(1).
+		SetRegistryProxySupplier(nil).
+		SetContext(ctx).
+		SetCustomConfig([]*sandbox.NodeConfig{{NsmgrCtx: nsmgrCtx}}).
+		SetNSMgrSupplier(supplyNSMgr).
+		Build()
+	defer domain.Cleanup()
+
+	nseReg := &registry.NetworkServiceEndpoint{
+		Name:                "final-endpoint",
+		NetworkServiceNames: []string{"my-service"},
+	}
+
+	counter := &counterServer{}
+	_, err := domain.Nodes[0].NewEndpoint(ctx, nseReg, sandbox.GenerateTestToken, counter)
+	require.NoError(t, err)
+
+	request := &networkservice.NetworkServiceRequest{
+		MechanismPreferences: []*networkservice.Mechanism{
+			{Cls: cls.LOCAL, Type: kernelmech.MECHANISM},
+		},
+		Connection: &networkservice.Connection{
+			Id:             "1",
+			NetworkService: "my-service",
+			Context:        &networkservice.ConnectionContext{},
+		},
+	}
+
+	// Client is stopped before the restart, so it doesn't heal the connection
+	nscCtx, nscCtxCancel := context.WithCancel(ctx)
+	conn, err := domain.Nodes[0].NewClient(nscCtx, sandbox.GenerateTestToken).Request(nscCtx, request.Clone())
+	require.NoError(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&counter.Requests))
+	nscCtxCancel()
+
+	// Restart NSMgr
+	nsmgrCtxCancel()
+	require.Eventually(t, checkURLFree(domain.Nodes[0].NSMgr.URL.Host), timeout, tick)
+
+	restoredNSMgrEntry, restoredNSMgrResources := builder.NewNSMgr(ctx, domain.Nodes[0], domain.Nodes[0].NSMgr.URL.Host, domain.Registry.URL, sandbox.GenerateTestToken)
+	domain.Nodes[0].NSMgr = restoredNSMgrEntry
+	domain.AddResources(restoredNSMgrResources)
+
+	// Connection is refreshed down to the NSE without the client
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&counter.Requests) >= 2
+	}, timeout, tick)
+	require.Equal(t, 1, counter.UniqueRequests())
+	require.Equal(t, int32(0), atomic.LoadInt32(&counter.Closes))
+
+	// Restored NSMgr monitors the connection
+	cc, err := grpc.DialContext(ctx, grpcutils.URLToTarget(restoredNSMgrEntry.URL), sandbox.DefaultDialOptions(sandbox.GenerateTestToken)...)
+	require.NoError(t, err)
+	defer func() { _ = cc.Close() }()
+
+	nsmgrSegment := conn.GetPath().GetPathSegments()[1]
+	require.Eventually(t, func() bool {
+		monitorCtx, monitorCancel := context.WithCancel(ctx)
+		defer monitorCancel()
+
+		recv, err := networkservice.NewMonitorConnectionClient(cc).MonitorConnections(monitorCtx, &networkservice.MonitorScopeSelector{
+			PathSegments: []*networkservice.PathSegment{nsmgrSegment},
+		})
+		if err != nil {
+			return false
+		}
+		event, err := recv.Recv()
+		if err != nil {
+			return false
+		}
+		_, ok := event.GetConnections()[nsmgrSegment.GetId()]
+		return ok
+	}, timeout, tick)
+
+	// Restored connection is closed as usual
+	_, err = restoredNSMgrEntry.Nsmgr.Close(ctx, conn.Clone())
+	require.NoError(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&counter.Closes))
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsmgr_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/cls"
+	kernelmech "github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/sandbox"
+)
+
+func TestNSMGR_DrainEndpoint(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	domain := sandbox.NewBuilder(t).
+		SetNodesCount(1).
+		SetRegistryProxySupplier(nil).
+		SetContext(ctx).
+		Build()
+	defer domain.Cleanup()
+
+	nseReg1 := &registry.NetworkServiceEndpoint{
+		Name:                "final-endpoint-1",
+		NetworkServiceNames: []string{"my-service"},
+	}
+
+	counter1 := new(counterServer)
+	nse1, err := domain.Nodes[0].NewEndpoint(ctx, nseReg1, sandbox.GenerateTestToken, counter1)
+	require.NoError(t, err)
+
+	request := &networkservice.NetworkServiceRequest{
+		MechanismPreferences: []*networkservice.Mechanism{
+			{Cls: cls.LOCAL, Type: kernelmech.MECHANISM},
+		},
+		Connection: &networkservice.Connection{
+			Id:             "1",
+			NetworkService: "my-service",
+			Context:        &networkservice.ConnectionContext{},
+		},
+	}
+
+	nsc := domain.Nodes[0].NewClient(ctx, sandbox.GenerateTestToken)
+
+	_, err = nsc.Request(ctx, request.Clone())
+	require.NoError(t, err)
+	require.Equal(t, 1, counter1.UniqueRequests())
+
+	nseReg2 := &registry.NetworkServiceEndpoint{
+		Name:                "final-endpoint-2",
+		NetworkServiceNames: []string{"my-service"},
+	}
+
+	counter2 := new(counterServer)
+	_, err = domain.Nodes[0].NewEndpoint(ctx, nseReg2, sandbox.GenerateTestToken, counter2)
+	require.NoError(t, err)
+
+	require.NoError(t, nse1.Drain(ctx, 100*time.Millisecond))
+	require.Equal(t, 1, counter1.UniqueCloses())
+
+	// Connection is healed with the other endpoint
+	require.Eventually(t, func() bool { return counter2.UniqueRequests() == 1 }, timeout, tick)
+
+	// New connections are not selecting the drained endpoint
+	request.Connection.Id = "2"
+	_, err = nsc.Request(ctx, request.Clone())
+	require.NoError(t, err)
+	require.Equal(t, 1, counter1.UniqueRequests())
+	require.Equal(t, 2, counter2.UniqueRequests())
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsmgr_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/cls"
+	kernelmech "github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/chains/nsmgr"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/circuitbreaker"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/quota"
+	"github.com/networkservicemesh/sdk/pkg/tools/sandbox"
+	"github.com/networkservicemesh/sdk/pkg/tools/token"
+)
+
+func TestNSMGR_QuotaDoesNotTripCircuitBreaker(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	breaker := circuitbreaker.NewBreaker(ctx, circuitbreaker.WithFailureThreshold(1), circuitbreaker.WithCooldown(time.Hour))
+
+	supplyNSMgr := func(ctx context.Context, nsmgrReg *registry.NetworkServiceEndpoint, authzServer networkservice.NetworkServiceServer,
+		tokenGenerator token.GeneratorFunc, registryCC grpc.ClientConnInterface, options ...nsmgr.Option) nsmgr.Nsmgr {
+		return nsmgr.NewServer(ctx, nsmgrReg, authzServer, tokenGenerator, registryCC, append(options,
+			nsmgr.WithCircuitBreaker(breaker),
+			nsmgr.WithQuotaOptions(quota.WithConfig(&quota.Config{NetworkService: 1})),
+		)...)
+	}
+
+	domain := sandbox.NewBuilder(t).
+		SetNodesCount(1).
+		SetRegistryProxySupplier(nil).
+		SetContext(ctx).
+		SetNSMgrSupplier(supplyNSMgr).
+		Build()
+	defer domain.Cleanup()
+
+	nseReg := &registry.NetworkServiceEndpoint{
+		Name:                "final-endpoint",
+		NetworkServiceNames: []string{"my-service"},
+	}
+
+	counter := new(counterServer)
+	_, err := domain.Nodes[0].NewEndpoint(ctx, nseReg, sandbox.GenerateTestToken, counter)
+	require.NoError(t, err)
+
+	request := &networkservice.NetworkServiceRequest{
+		MechanismPreferences: []*networkservice.Mechanism{
+			{Cls: cls.LOCAL, Type: kernelmech.MECHANISM},
+		},
+		Connection: &networkservice.Connection{
+			Id:             "1",
+			NetworkService: "my-service",
+			Context:        &networkservice.ConnectionContext{},
+		},
+	}
+
+	nsc := domain.Nodes[0].NewClient(ctx, sandbox.GenerateTestToken)
+
+	conn, err := nsc.Request(ctx, request.Clone())
+	require.NoError(t, err)
+	require.Equal(t, 1, counter.UniqueRequests())
+
+	// The second connection is rejected by the Network Service quota
+	request.Connection.Id = "2"
+	requestCtx, cancelRequest := context.WithTimeout(ctx, time.Second)
+	defer cancelRequest()
+
+	_, err = nsc.Request(requestCtx, request.Clone())
+	require.Error(t, err)
+	require.Equal(t, 1, counter.UniqueRequests())
+
+	// The endpoint is healthy, its circuit stays closed
+	require.Equal(t, circuitbreaker.Closed, breaker.State(conn.GetNetworkServiceEndpointName()))
+
+	_, err = nsc.Close(ctx, conn)
+	require.NoError(t, err)
+
+	request.Connection.Id = "3"
+	_, err = nsc.Request(ctx, request.Clone())
+	require.NoError(t, err)
+	require.Equal(t, 2, counter.UniqueRequests())
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkpoint
+
+import (
+	"time"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/walstore"
+)
+
+const defaultMaxConcurrentRestores = 10
+
+type checkpointOptions struct {
+	storeOptions          []walstore.Option
+	maxConcurrentRestores int
+}
+
+// Option is an option pattern for NewServer
+type Option func(o *checkpointOptions)
+
+// WithSnapshotInterval sets how often the write-ahead log is compacted into the snapshot
+func WithSnapshotInterval(snapshotInterval time.Duration) Option {
+	return func(o *checkpointOptions) {
+		o.storeOptions = append(o.storeOptions, walstore.WithSnapshotInterval(snapshotInterval))
+	}
+}
+
+// WithMaxConcurrentRestores sets how many connections can be restored at the same time, so the restart doesn't
+// cause a Request storm to the next hops. Default is 10.
+func WithMaxConcurrentRestores(maxConcurrentRestores int) Option {
+	return func(o *checkpointOptions) {
+		o.maxConcurrentRestores = maxConcurrentRestores
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package checkpoint provides a NetworkServiceServer chain element persisting the active connections on the local
+// disk, so they survive the restart
+package checkpoint
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/updatepath"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clienturlctx"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/restorectx"
+	"github.com/networkservicemesh/sdk/pkg/tools/walstore"
+)
+
+const (
+	restoreInitialBackoff = 100 * time.Millisecond
+	restoreMaxBackoff     = 5 * time.Second
+)
+
+// Server is a NetworkServiceServer persisting the active connections on the local disk
+type Server interface {
+	networkservice.NetworkServiceServer
+
+	// Restore requests the connections loaded from the disk with the server as if they were refreshed by the
+	// previous hop. The server should be the chain containing this element, so all the chain elements (e.g. timeout,
+	// monitor, heal, connect) receive the restored connections. Restoring Requests are marked with
+	// restorectx.WithRestore and carry the stored client URL, so they are not discovered and selected again and are
+	// not requested from the next hops: the stored client URL is dialed and the next hop is only monitored. If the
+	// restoring Request fails, the connection is requested as usual with backoff until it expires or is refreshed or
+	// closed by the previous hop. The first passes are restored before the return passes (e.g. NSMgr -> Forwarder ->
+	// NSMgr), so the return passes find the restored first passes state. Restore returns when all the connections are
+	// handled or ctx is done. Already expired connections are dropped.
+	Restore(ctx context.Context, server networkservice.NetworkServiceServer) error
+}
+
+type checkpointServer struct {
+	clock                 clock.Clock
+	store                 *walstore.Store
+	maxConcurrentRestores int
+}
+
+// NewServer creates a new checkpoint Server storing the connections in the dir. It should be placed after the
+// `updatepath` and `timeout` elements, so it receives the timeout Closes and stores the connections with the
+// expiration time set by the previous hop. It should be placed right before the `connect` element, so it stores the
+// client URL the connection is connected to.
+func NewServer(ctx context.Context, dir string, options ...Option) (Server, error) {
+	o := &checkpointOptions{
+		maxConcurrentRestores: defaultMaxConcurrentRestores,
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+
+	s, err := walstore.New(ctx, dir, func() proto.Message { return new(Entry) }, o.storeOptions...)
+	if err != nil {
+		return nil, err
+	}
+	return &checkpointServer{
+		clock:                 clock.FromContext(ctx),
+		store:                 s,
+		maxConcurrentRestores: o.maxConcurrentRestores,
+	}, nil
+}
+
+func (s *checkpointServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	index := request.GetConnection().GetPath().GetIndex()
+	mechanismPreferences := request.GetMechanismPreferences()
+
+	var clientURL string
+	if u := clienturlctx.ClientURL(ctx); u != nil {
+		clientURL = u.String()
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil || index == 0 {
+		return conn, err
+	}
+
+	// Store the request the previous hop sends to refresh the connection
+	restoreConn := conn.Clone()
+	restoreConn.Path.Index = index - 1
+	restoreConn.Id = restoreConn.GetCurrentPathSegment().GetId()
+
+	if err := s.store.Put(conn.GetId(), &Entry{
+		Request: &networkservice.NetworkServiceRequest{
+			Connection:           restoreConn,
+			MechanismPreferences: mechanismPreferences,
+		},
+		ClientUrl: clientURL,
+	}); err != nil {
+		log.FromContext(ctx).WithField("checkpointServer", "Request").Errorf("failed to store connection: %s %s", conn.GetId(), err.Error())
+	}
+
+	return conn, nil
+}
+
+func (s *checkpointServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	if err := s.store.Delete(conn.GetId()); err != nil {
+		log.FromContext(ctx).WithField("checkpointServer", "Close").Errorf("failed to delete stored connection: %s %s", conn.GetId(), err.Error())
+	}
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func (s *checkpointServer) Restore(ctx context.Context, server networkservice.NetworkServiceServer) error {
+	var firstPasses, returnPasses []*Entry
+	for _, e := range s.store.List() {
+		entry := e.(*Entry)
+		if s.clock.Until(expirationTime(entry)) <= 0 {
+			if err := s.store.Delete(connectionID(entry)); err != nil {
+				return err
+			}
+			continue
+		}
+		if isReturnPass(entry) {
+			returnPasses = append(returnPasses, entry)
+		} else {
+			firstPasses = append(firstPasses, entry)
+		}
+	}
+	if err := s.store.Snapshot(); err != nil {
+		return errors.Wrap(err, "failed to snapshot restored connections")
+	}
+
+	s.restoreAll(ctx, server, firstPasses)
+	s.restoreAll(ctx, server, returnPasses)
+
+	return nil
+}
+
+func (s *checkpointServer) restoreAll(ctx context.Context, server networkservice.NetworkServiceServer, entries []*Entry) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, s.maxConcurrentRestores)
+	for _, entry := range entries {
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+			wg.Add(1)
+			go func(entry *Entry) {
+				defer wg.Done()
+				s.restore(ctx, server, entry)
+				<-sem
+			}(entry)
+		}
+	}
+	wg.Wait()
+}
+
+func (s *checkpointServer) restore(ctx context.Context, server networkservice.NetworkServiceServer, entry *Entry) {
+	id := connectionID(entry)
+	logger := log.FromContext(ctx).WithField("checkpointServer", "restore")
+	ctx, cancel := s.clock.WithDeadline(ctx, expirationTime(entry))
+	defer cancel()
+
+	if clientURL, err := url.Parse(entry.GetClientUrl()); err == nil && entry.GetClientUrl() != "" {
+		restoreCtx, done := restorectx.WithRestore(clienturlctx.WithClientURL(ctx, clientURL))
+		_, err = server.Request(restoreCtx, entry.GetRequest().Clone())
+		done()
+		if err == nil {
+			return
+		}
+		logger.Warnf("failed to restore connection with %s, requesting it: %s %s", clientURL, id, err.Error())
+	}
+
+	for backoff := restoreInitialBackoff; ; backoff *= 2 {
+		if stored, ok := s.store.Get(id); !ok || !proto.Equal(stored, entry) {
+			// Connection has been already refreshed or closed by the previous hop
+			return
+		}
+
+		_, err := server.Request(ctx, entry.GetRequest().Clone())
+		if err == nil {
+			return
+		}
+
+		if backoff > restoreMaxBackoff {
+			backoff = restoreMaxBackoff
+		}
+		logger.Warnf("failed to restore connection, retrying in %s: %s %s", backoff, id, err.Error())
+
+		timer := s.clock.Timer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			logger.Errorf("failed to restore connection before it expires: %s", id)
+			return
+		case <-timer.C():
+		}
+	}
+}
+
+// isReturnPass returns true if the stored request is sent by the previous hop of the return pass
+func isReturnPass(entry *Entry) bool {
+	path := entry.GetRequest().GetConnection().GetPath()
+	return updatepath.IsReturnPass(&networkservice.Path{
+		Index:        path.GetIndex() + 1,
+		PathSegments: path.GetPathSegments(),
+	})
+}
+
+func connectionID(entry *Entry) string {
+	return entry.GetRequest().GetConnection().GetNextPathSegment().GetId()
+}
+
+func expirationTime(entry *Entry) time.Time {
+	expires := entry.GetRequest().GetConnection().GetCurrentPathSegment().GetExpires()
+	if expires == nil {
+		return time.Time{}
+	}
+	return expires.AsTime()
+}
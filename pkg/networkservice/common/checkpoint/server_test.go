@@ -0,0 +1,265 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkpoint_test
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/cls"
+	kernelmech "github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/checkpoint"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/clienturl"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/serialize"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/timeout"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/updatepath"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clienturlctx"
+	"github.com/networkservicemesh/sdk/pkg/tools/restorectx"
+)
+
+const nsmgrName = "nsmgr"
+
+var nextURL = &url.URL{Scheme: "unix", Path: "/next.sock"}
+
+// countServer fails the first errs Requests and records all the Requests and the restoring Requests count
+type countServer struct {
+	errs     int
+	lock     sync.Mutex
+	requests []*networkservice.NetworkServiceRequest
+	restores int
+}
+
+func (s *countServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.requests = append(s.requests, request.Clone())
+	if restorectx.IsRestore(ctx) && *clienturlctx.ClientURL(ctx) == *nextURL {
+		s.restores++
+	}
+	if len(s.requests) <= s.errs {
+		return nil, errors.New("next hop is not ready")
+	}
+	return request.GetConnection(), nil
+}
+
+func (s *countServer) Close(_ context.Context, _ *networkservice.Connection) (*empty.Empty, error) {
+	return new(empty.Empty), nil
+}
+
+func (s *countServer) Requests() []*networkservice.NetworkServiceRequest {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.requests
+}
+
+func (s *countServer) Restores() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.restores
+}
+
+type testServer struct {
+	networkservice.NetworkServiceServer
+	store   checkpoint.Server
+	counter *countServer
+}
+
+func newTestServer(ctx context.Context, t *testing.T, dir string, errs int) *testServer {
+	store, err := checkpoint.NewServer(ctx, dir)
+	require.NoError(t, err)
+
+	counter := &countServer{errs: errs}
+	return &testServer{
+		NetworkServiceServer: next.NewNetworkServiceServer(
+			updatepath.NewServer(nsmgrName),
+			serialize.NewServer(),
+			timeout.NewServer(ctx),
+			clienturl.NewServer(nextURL),
+			store,
+			counter,
+		),
+		store:   store,
+		counter: counter,
+	}
+}
+
+func (s *testServer) restore(ctx context.Context, t *testing.T) {
+	require.NoError(t, s.store.Restore(ctx, s))
+}
+
+func newRequest(expires time.Duration) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		MechanismPreferences: []*networkservice.Mechanism{
+			{Cls: cls.LOCAL, Type: kernelmech.MECHANISM},
+		},
+		Connection: &networkservice.Connection{
+			Id:             "nsc-id",
+			NetworkService: "ns",
+			Path: &networkservice.Path{
+				PathSegments: []*networkservice.PathSegment{
+					{Name: "nsc", Id: "nsc-id", Expires: timestamppb.New(time.Now().Add(expires))},
+				},
+			},
+		},
+	}
+}
+
+func TestCheckpointServer_Restore(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	dir := t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conn, err := newTestServer(ctx, t, dir, 0).Request(ctx, newRequest(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, "nsc-id", conn.GetId())
+
+	// Restart
+	cancel()
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+
+	server := newTestServer(ctx, t, dir, 0)
+	server.restore(ctx, t)
+
+	// Connection is restored with the stored client URL instead of being requested again
+	requests := server.counter.Requests()
+	require.Len(t, requests, 1)
+	require.Equal(t, 1, server.counter.Restores())
+	require.Equal(t, conn.GetPath().GetPathSegments()[1].GetId(), requests[0].GetConnection().GetId())
+	require.Len(t, requests[0].GetConnection().GetPath().GetPathSegments(), 2)
+	require.Len(t, requests[0].GetMechanismPreferences(), 1)
+
+	// Restored connection is refreshed and closed as usual
+	conn, err = server.Request(ctx, newRequest(time.Hour).SetRequestConnection(conn))
+	require.NoError(t, err)
+	_, err = server.Close(ctx, conn)
+	require.NoError(t, err)
+
+	// Restart
+	cancel()
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+
+	server = newTestServer(ctx, t, dir, 0)
+	server.restore(ctx, t)
+	require.Empty(t, server.counter.Requests())
+}
+
+func TestCheckpointServer_Expired(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	dir := t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := newTestServer(ctx, t, dir, 0).Request(ctx, newRequest(100*time.Millisecond))
+	require.NoError(t, err)
+
+	// Restart after the connection expires
+	cancel()
+	time.Sleep(200 * time.Millisecond)
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+
+	server := newTestServer(ctx, t, dir, 0)
+	server.restore(ctx, t)
+	require.Empty(t, server.counter.Requests())
+}
+
+func TestCheckpointServer_RestoreRetry(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	dir := t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := newTestServer(ctx, t, dir, 0).Request(ctx, newRequest(time.Hour))
+	require.NoError(t, err)
+
+	// Restart with the restoring Request failing and the next hop not ready yet
+	cancel()
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+
+	server := newTestServer(ctx, t, dir, 2)
+	server.restore(ctx, t)
+	require.Len(t, server.counter.Requests(), 3)
+	require.Equal(t, 1, server.counter.Restores())
+}
+
+func TestCheckpointServer_ReturnPass(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	dir := t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// NSC -> NSMgr -> Forwarder -> NSMgr
+	returnPassRequest := newRequest(time.Hour)
+	returnPassRequest.Connection.Id = "forwarder-id"
+	returnPassRequest.Connection.Path = &networkservice.Path{
+		Index: 2,
+		PathSegments: []*networkservice.PathSegment{
+			{Name: "nsc", Id: "nsc-id"},
+			{Name: nsmgrName, Id: "nsmgr-id"},
+			{Name: "forwarder", Id: "forwarder-id", Expires: timestamppb.New(time.Now().Add(time.Hour))},
+		},
+	}
+
+	server := newTestServer(ctx, t, dir, 0)
+	_, err := server.Request(ctx, returnPassRequest)
+	require.NoError(t, err)
+	firstPassConn, err := server.Request(ctx, newRequest(time.Hour))
+	require.NoError(t, err)
+
+	// Restart
+	cancel()
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+
+	server = newTestServer(ctx, t, dir, 0)
+	server.restore(ctx, t)
+
+	// First pass is restored before the return pass
+	requests := server.counter.Requests()
+	require.Len(t, requests, 2)
+	require.Equal(t, 2, server.counter.Restores())
+	require.Equal(t, firstPassConn.GetPath().GetPathSegments()[1].GetId(), requests[0].GetConnection().GetId())
+	require.Equal(t, nsmgrName, requests[1].GetConnection().GetCurrentPathSegment().GetName())
+	require.Equal(t, uint32(3), requests[1].GetConnection().GetPath().GetIndex())
+}
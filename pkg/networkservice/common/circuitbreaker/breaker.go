@@ -0,0 +1,240 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package circuitbreaker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/metrics"
+)
+
+const (
+	defaultFailureThreshold = 3
+	defaultCooldown         = 10 * time.Second
+)
+
+// Metrics exported by the Breaker
+const (
+	// StateMetric is the current State of the endpoint circuit: 0 - closed, 1 - open, 2 - half-open
+	StateMetric = "nsm_circuit_breaker_state"
+	// TripsMetric is the number of times the endpoint circuit has been opened
+	TripsMetric = "nsm_circuit_breaker_trips_total"
+)
+
+// State is a circuit breaker state for the endpoint
+type State int
+
+const (
+	// Closed - requests to the endpoint are allowed
+	Closed State = iota
+	// Open - endpoint has failed too many times in a row, requests to the endpoint are rejected until the cooldown
+	// expires
+	Open
+	// HalfOpen - cooldown has expired, a single probe request to the endpoint is allowed
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+type endpointState struct {
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// Breaker tracks consecutive Request failures for each endpoint by the endpoint name
+type Breaker struct {
+	clock            clock.Clock
+	failureThreshold int
+	cooldown         time.Duration
+
+	registry *metrics.Registry
+	states   *metrics.GaugeVec
+	trips    *metrics.CounterVec
+
+	lock       sync.Mutex
+	endpoints  map[string]*endpointState
+	tripCounts map[string]int
+}
+
+// NewBreaker creates a new Breaker. ctx is used only to get the clock.
+func NewBreaker(ctx context.Context, options ...Option) *Breaker {
+	b := &Breaker{
+		clock:            clock.FromContext(ctx),
+		failureThreshold: defaultFailureThreshold,
+		cooldown:         defaultCooldown,
+		registry:         metrics.Default(),
+		endpoints:        make(map[string]*endpointState),
+		tripCounts:       make(map[string]int),
+	}
+	for _, opt := range options {
+		opt(b)
+	}
+	b.states = b.registry.Gauge(StateMetric, "Circuit breaker state of the endpoint: 0 - closed, 1 - open, 2 - half-open", "nse")
+	b.trips = b.registry.Counter(TripsMetric, "Number of times the endpoint circuit breaker has been opened", "nse")
+	return b
+}
+
+// State returns the current state for the endpoint
+func (b *Breaker) State(nseName string) State {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.state(nseName)
+}
+
+// States returns the current states for all endpoints having at least one failure since the last successful Request
+func (b *Breaker) States() map[string]State {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	states := make(map[string]State, len(b.endpoints))
+	for nseName := range b.endpoints {
+		states[nseName] = b.state(nseName)
+	}
+	return states
+}
+
+// Trips returns the number of times the circuit has been opened for each endpoint
+func (b *Breaker) Trips() map[string]int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	trips := make(map[string]int, len(b.tripCounts))
+	for nseName, count := range b.tripCounts {
+		trips[nseName] = count
+	}
+	return trips
+}
+
+func (b *Breaker) state(nseName string) State {
+	e, ok := b.endpoints[nseName]
+	switch {
+	case !ok || e.failures < b.failureThreshold:
+		return Closed
+	case b.clock.Now().Before(e.openUntil):
+		return Open
+	default:
+		return HalfOpen
+	}
+}
+
+// available returns true if a Request to the endpoint would be allowed
+func (b *Breaker) available(nseName string) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	switch b.state(nseName) {
+	case Closed:
+		return true
+	case HalfOpen:
+		return !b.endpoints[nseName].probing
+	default:
+		return false
+	}
+}
+
+// acquire returns if a Request to the endpoint is allowed and if it is a half-open probe
+func (b *Breaker) acquire(nseName string) (allowed, probe bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	switch b.state(nseName) {
+	case Closed:
+		return true, false
+	case HalfOpen:
+		if e := b.endpoints[nseName]; !e.probing {
+			e.probing = true
+			return true, true
+		}
+	}
+	return false, false
+}
+
+// release releases the probe without changing the endpoint state
+func (b *Breaker) release(nseName string, probe bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if e, ok := b.endpoints[nseName]; ok && probe {
+		e.probing = false
+	}
+}
+
+// succeeded closes the circuit for the endpoint and returns the previous state
+func (b *Breaker) succeeded(nseName string) State {
+	b.lock.Lock()
+	prev := b.state(nseName)
+	_, tracked := b.endpoints[nseName]
+	delete(b.endpoints, nseName)
+	b.lock.Unlock()
+
+	if tracked {
+		b.states.Delete(nseName)
+	}
+	return prev
+}
+
+// failed counts a failure for the endpoint and returns the previous and the new states
+func (b *Breaker) failed(nseName string, probe bool) (prev, state State) {
+	b.lock.Lock()
+	prev = b.state(nseName)
+
+	e, tracked := b.endpoints[nseName]
+	if !tracked {
+		e = new(endpointState)
+		b.endpoints[nseName] = e
+	}
+	if probe {
+		e.probing = false
+	}
+	if e.failures++; e.failures >= b.failureThreshold {
+		e.openUntil = b.clock.Now().Add(b.cooldown)
+	}
+
+	state = b.state(nseName)
+	tripped := state == Open && prev != Open
+	if tripped {
+		b.tripCounts[nseName]++
+	}
+	b.lock.Unlock()
+
+	// Metrics are updated out of the lock, since the state gauge function takes it on collection
+	if !tracked {
+		b.states.With(nseName).SetFunc(func() float64 {
+			return float64(b.State(nseName))
+		})
+	}
+	if tripped {
+		b.trips.With(nseName).Inc()
+	}
+
+	return prev, state
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package circuitbreaker
+
+import (
+	"time"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/metrics"
+)
+
+// Option is an option pattern for NewBreaker
+type Option func(b *Breaker)
+
+// WithFailureThreshold sets count of consecutive Request failures opening the circuit for the endpoint
+func WithFailureThreshold(failureThreshold int) Option {
+	return func(b *Breaker) {
+		b.failureThreshold = failureThreshold
+	}
+}
+
+// WithCooldown sets duration for the circuit to stay open before the half-open probe
+func WithCooldown(cooldown time.Duration) Option {
+	return func(b *Breaker) {
+		b.cooldown = cooldown
+	}
+}
+
+// WithMetricsRegistry sets the registry for the circuit breaker state and trips metrics, metrics.Default() is used
+// by default
+func WithMetricsRegistry(registry *metrics.Registry) Option {
+	return func(b *Breaker) {
+		b.registry = registry
+	}
+}
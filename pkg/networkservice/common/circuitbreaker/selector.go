@@ -0,0 +1,63 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package circuitbreaker
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discover"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/selectendpoint"
+)
+
+type circuitBreakerSelector struct {
+	breaker  *Breaker
+	selector selectendpoint.Selector
+}
+
+// NewSelector returns a selectendpoint.Selector ejecting endpoints rejected by the breaker from the candidates
+// ordered by the selector
+func NewSelector(breaker *Breaker, selector selectendpoint.Selector) selectendpoint.Selector {
+	return &circuitBreakerSelector{
+		breaker:  breaker,
+		selector: selector,
+	}
+}
+
+func (s *circuitBreakerSelector) Select(ctx context.Context, request *networkservice.NetworkServiceRequest, candidates *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint {
+	var endpoints []*registry.NetworkServiceEndpoint
+	for _, endpoint := range s.selector.Select(ctx, request, candidates) {
+		if s.breaker.available(endpoint.Name) {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	return endpoints
+}
+
+func (s *circuitBreakerSelector) Connected(conn *networkservice.Connection) {
+	if observer, ok := s.selector.(selectendpoint.ConnectionObserver); ok {
+		observer.Connected(conn)
+	}
+}
+
+func (s *circuitBreakerSelector) Closed(conn *networkservice.Connection) {
+	if observer, ok := s.selector.(selectendpoint.ConnectionObserver); ok {
+		observer.Closed(conn)
+	}
+}
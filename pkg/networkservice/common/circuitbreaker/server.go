@@ -0,0 +1,117 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package circuitbreaker provides a NetworkServiceServer chain element and a selectendpoint.Selector implementing
+// a per-endpoint circuit breaker
+package circuitbreaker
+
+import (
+	"context"
+	"sync"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clienturlctx"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type circuitBreakerServer struct {
+	breaker *Breaker
+
+	lock        sync.Mutex
+	connections map[string]string // connection ID -> endpoint name
+}
+
+// NewServer - returns a new NetworkServiceServer chain element tracking Request failures for the endpoints in the
+// breaker. It rejects new connections to the endpoints with the open circuit and allows only a single probe Request
+// to the endpoints with the half-open circuit. Refreshes of the established connections are never rejected.
+// Canceled and codes.ResourceExhausted Requests are not counted as the endpoint failures.
+// NOTE: it should be placed right after the endpoint selection chain element, so the selected endpoint is already set
+// in the connection. Wrap the used selector with NewSelector to eject rejected endpoints from the selection.
+func NewServer(breaker *Breaker) networkservice.NetworkServiceServer {
+	return &circuitBreakerServer{
+		breaker:     breaker,
+		connections: make(map[string]string),
+	}
+}
+
+func (s *circuitBreakerServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	connID := request.GetConnection().GetId()
+	nseName := request.GetConnection().GetNetworkServiceEndpointName()
+	if clienturlctx.ClientURL(ctx) == nil || nseName == "" {
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	logger := log.FromContext(ctx).WithField("circuitBreakerServer", "Request")
+
+	var allowed, probe bool
+	if s.established(connID, nseName) {
+		allowed = true
+	} else if allowed, probe = s.breaker.acquire(nseName); !allowed {
+		return nil, errors.Errorf("circuit breaker is %s for the endpoint: %s", s.breaker.State(nseName), nseName)
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			// Request has been canceled by the client, it is not an endpoint failure. Deadline exceeded is still
+			// counted, since it is how the hanging endpoint fails.
+			s.breaker.release(nseName, probe)
+			return nil, err
+		}
+		if status.Code(errors.Cause(err)) == codes.ResourceExhausted {
+			// Request has been rejected by the connection quotas or rate limits, the endpoint is healthy
+			s.breaker.release(nseName, probe)
+			return nil, err
+		}
+		if prev, state := s.breaker.failed(nseName, probe); prev != state {
+			logger.Warnf("circuit breaker state changed for the endpoint %s: %s -> %s, trips: %d",
+				nseName, prev, state, s.breaker.Trips()[nseName])
+		}
+		return nil, err
+	}
+
+	if prev := s.breaker.succeeded(nseName); prev != Closed {
+		logger.Infof("circuit breaker state changed for the endpoint %s: %s -> %s", nseName, prev, Closed)
+	}
+
+	s.lock.Lock()
+	s.connections[connID] = nseName
+	s.lock.Unlock()
+
+	return conn, nil
+}
+
+func (s *circuitBreakerServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	s.lock.Lock()
+	delete(s.connections, conn.GetId())
+	s.lock.Unlock()
+
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func (s *circuitBreakerServer) established(connID, nseName string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.connections[connID] == nseName
+}
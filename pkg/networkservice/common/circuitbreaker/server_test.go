@@ -0,0 +1,236 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package circuitbreaker_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/circuitbreaker"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discover"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/selectendpoint"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clienturlctx"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+	"github.com/networkservicemesh/sdk/pkg/tools/metrics"
+)
+
+const cooldown = time.Minute
+
+type failingServer struct {
+	failed   map[string]bool
+	selected []string
+}
+
+func (s *failingServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	host := clienturlctx.ClientURL(ctx).Host
+	s.selected = append(s.selected, host)
+	if s.failed[host] {
+		return nil, errors.Errorf("endpoint failed: %s", host)
+	}
+	return request.GetConnection(), nil
+}
+
+func (s *failingServer) Close(_ context.Context, _ *networkservice.Connection) (*empty.Empty, error) {
+	return new(empty.Empty), nil
+}
+
+type hangingServer struct{}
+
+func (s *hangingServer) Request(ctx context.Context, _ *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (s *hangingServer) Close(_ context.Context, _ *networkservice.Connection) (*empty.Empty, error) {
+	return new(empty.Empty), nil
+}
+
+func inOrder() selectendpoint.Selector {
+	return selectendpoint.SelectorFunc(
+		func(_ context.Context, _ *networkservice.NetworkServiceRequest, c *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint {
+			return c.Endpoints
+		})
+}
+
+func TestCircuitBreakerServer(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	clockMock := clockmock.NewMock()
+	breaker := circuitbreaker.NewBreaker(clock.WithClock(context.Background(), clockMock),
+		circuitbreaker.WithFailureThreshold(2),
+		circuitbreaker.WithCooldown(cooldown))
+
+	fs := &failingServer{
+		failed: map[string]bool{"nse-0": true},
+	}
+	server := next.NewNetworkServiceServer(
+		selectendpoint.NewServer(selectendpoint.WithSelector(circuitbreaker.NewSelector(breaker, inOrder()))),
+		circuitbreaker.NewServer(breaker),
+		fs,
+	)
+
+	var nses []*registry.NetworkServiceEndpoint
+	for i := 0; i < 2; i++ {
+		nses = append(nses, &registry.NetworkServiceEndpoint{
+			Name: fmt.Sprintf("nse-%d", i),
+			Url:  fmt.Sprintf("tcp://nse-%d", i),
+		})
+	}
+	ctx := discover.WithCandidates(context.Background(), nses, &registry.NetworkService{Name: "ns"})
+
+	request := func(id string) *networkservice.Connection {
+		conn, err := server.Request(ctx, &networkservice.NetworkServiceRequest{
+			Connection: &networkservice.Connection{Id: id, NetworkService: "ns"},
+		})
+		require.NoError(t, err)
+		return conn
+	}
+
+	// 1. Fail 2 times in a row
+	for i := 0; i < 2; i++ {
+		require.Equal(t, "nse-1", request(fmt.Sprint(i)).NetworkServiceEndpointName)
+	}
+	require.Equal(t, []string{"nse-0", "nse-1", "nse-0", "nse-1"}, fs.selected)
+	require.Equal(t, map[string]circuitbreaker.State{"nse-0": circuitbreaker.Open}, breaker.States())
+
+	// 2. Open circuit endpoint should be ejected
+	fs.selected = nil
+	require.Equal(t, "nse-1", request("2").NetworkServiceEndpointName)
+	require.Equal(t, []string{"nse-1"}, fs.selected)
+
+	// 3. Failed half-open probe should open the circuit again
+	clockMock.Add(cooldown)
+	require.Equal(t, circuitbreaker.HalfOpen, breaker.State("nse-0"))
+
+	fs.selected = nil
+	require.Equal(t, "nse-1", request("3").NetworkServiceEndpointName)
+	require.Equal(t, []string{"nse-0", "nse-1"}, fs.selected)
+	require.Equal(t, circuitbreaker.Open, breaker.State("nse-0"))
+
+	// 4. Successful half-open probe should close the circuit
+	clockMock.Add(cooldown)
+	fs.failed["nse-0"] = false
+
+	require.Equal(t, "nse-0", request("4").NetworkServiceEndpointName)
+	require.Equal(t, circuitbreaker.Closed, breaker.State("nse-0"))
+	require.Empty(t, breaker.States())
+}
+
+func TestCircuitBreakerServer_RefreshIsNotRejected(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	breaker := circuitbreaker.NewBreaker(context.Background(), circuitbreaker.WithFailureThreshold(1))
+
+	fs := &failingServer{
+		failed: make(map[string]bool),
+	}
+	server := next.NewNetworkServiceServer(
+		circuitbreaker.NewServer(breaker),
+		fs,
+	)
+
+	nse := &registry.NetworkServiceEndpoint{Name: "nse", Url: "tcp://nse"}
+	u, err := url.Parse(nse.Url)
+	require.NoError(t, err)
+
+	ctx := clienturlctx.WithClientURL(context.Background(), u)
+
+	request := &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "id", NetworkService: "ns", NetworkServiceEndpointName: nse.Name},
+	}
+
+	_, err = server.Request(ctx, request.Clone())
+	require.NoError(t, err)
+
+	// Other connection fails and opens the circuit
+	fs.failed[nse.Name] = true
+	otherRequest := request.Clone()
+	otherRequest.Connection.Id = "other-id"
+	_, err = server.Request(ctx, otherRequest)
+	require.Error(t, err)
+	require.Equal(t, circuitbreaker.Open, breaker.State(nse.Name))
+
+	// New connection is rejected without the Request to the endpoint
+	fs.selected = nil
+	newRequest := request.Clone()
+	newRequest.Connection.Id = "new-id"
+	_, err = server.Request(ctx, newRequest)
+	require.Error(t, err)
+	require.Empty(t, fs.selected)
+
+	// Refresh is not rejected
+	fs.failed[nse.Name] = false
+	_, err = server.Request(ctx, request.Clone())
+	require.NoError(t, err)
+	require.Equal(t, circuitbreaker.Closed, breaker.State(nse.Name))
+}
+
+func TestCircuitBreakerServer_DeadlineExceeded(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	registry := metrics.NewRegistry()
+	breaker := circuitbreaker.NewBreaker(context.Background(),
+		circuitbreaker.WithFailureThreshold(1),
+		circuitbreaker.WithMetricsRegistry(registry))
+
+	server := next.NewNetworkServiceServer(
+		circuitbreaker.NewServer(breaker),
+		new(hangingServer),
+	)
+
+	u, err := url.Parse("tcp://nse")
+	require.NoError(t, err)
+
+	request := func(ctx context.Context, id string) error {
+		_, err := server.Request(clienturlctx.WithClientURL(ctx, u), &networkservice.NetworkServiceRequest{
+			Connection: &networkservice.Connection{Id: id, NetworkService: "ns", NetworkServiceEndpointName: "nse"},
+		})
+		return err
+	}
+
+	// 1. Canceled by the client request is not an endpoint failure
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.Error(t, request(cancelCtx, "canceled"))
+	require.Equal(t, circuitbreaker.Closed, breaker.State("nse"))
+
+	// 2. Hanging endpoint should open the circuit
+	deadlineCtx, cancel := context.WithDeadline(context.Background(), time.Now())
+	defer cancel()
+	require.Error(t, request(deadlineCtx, "deadline"))
+	require.Equal(t, circuitbreaker.Open, breaker.State("nse"))
+	require.Equal(t, map[string]int{"nse": 1}, breaker.Trips())
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, registry.WriteText(buf))
+	require.Contains(t, buf.String(), circuitbreaker.StateMetric+`{nse="nse"} 1`)
+	require.Contains(t, buf.String(), circuitbreaker.TripsMetric+`{nse="nse"} 1`)
+}
@@ -27,12 +27,14 @@ import (
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 
 	"github.com/networkservicemesh/sdk/pkg/networkservice/chains/client"
 	"github.com/networkservicemesh/sdk/pkg/tools/clienturlctx"
 	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
+	"github.com/networkservicemesh/sdk/pkg/tools/restorectx"
 )
 
 type connectClient struct {
@@ -57,7 +59,10 @@ func (u *connectClient) init() error {
 		defer cancel()
 
 		var cc *grpc.ClientConn
-		cc, u.dialErr = grpc.DialContext(ctx, grpcutils.URLToTarget(clientURL), append(u.dialOptions, grpc.WithReturnConnectionError())...)
+		cc, u.dialErr = grpc.DialContext(ctx, grpcutils.URLToTarget(clientURL), append(u.dialOptions,
+			grpc.WithReturnConnectionError(),
+			grpc.WithChainUnaryInterceptor(restoreInterceptor),
+		)...)
 		if u.dialErr != nil {
 			return
 		}
@@ -95,3 +100,14 @@ func (u *connectClient) Close(ctx context.Context, conn *networkservice.Connecti
 	}
 	return u.client.Close(ctx, conn, opts...)
 }
+
+// restoreInterceptor returns the connection back for the restoring Requests without requesting the next hop, the next
+// hop already has the connection restored from the checkpoint
+func restoreInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	request, ok := req.(*networkservice.NetworkServiceRequest)
+	if !ok || !restorectx.IsRestore(ctx) {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	proto.Merge(reply.(proto.Message), request.GetConnection())
+	return nil
+}
@@ -46,6 +46,7 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/inject/injecterror"
 	"github.com/networkservicemesh/sdk/pkg/tools/clienturlctx"
 	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
+	"github.com/networkservicemesh/sdk/pkg/tools/restorectx"
 )
 
 const (
@@ -538,6 +539,67 @@ func TestConnectServer_RemoteRestarted(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestConnectServer_Restore(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	// 1. Create connectServer
+
+	s := connect.NewServer(context.Background(),
+		func(_ context.Context, cc grpc.ClientConnInterface) networkservice.NetworkServiceClient {
+			return networkservice.NewNetworkServiceClient(cc)
+		},
+		connect.WithDialTimeout(time.Second),
+		connect.WithDialOptions(grpc.WithInsecure()),
+	)
+
+	// 2. Setup A
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	urlA := &url.URL{Scheme: "tcp", Host: "127.0.0.1:"}
+	serverA := new(countServer)
+
+	err := startServer(ctx, urlA, serverA)
+	require.NoError(t, err)
+
+	require.NoError(t, waitServerStarted(urlA))
+
+	// 3. Create request
+
+	request := &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:             "id",
+			NetworkService: "network-service",
+		},
+	}
+
+	// 4. Restore A --> A is not requested
+
+	restoreCtx, done := restorectx.WithRestore(clienturlctx.WithClientURL(ctx, urlA))
+
+	conn, err := s.Request(restoreCtx, request.Clone())
+	require.NoError(t, err)
+	require.Equal(t, request.GetConnection().String(), conn.String())
+	require.Equal(t, int32(0), atomic.LoadInt32(&serverA.count))
+
+	done()
+
+	// 5. Refresh A --> A is requested
+
+	request.Connection = conn
+
+	_, err = s.Request(clienturlctx.WithClientURL(restoreCtx, urlA), request.Clone())
+	require.NoError(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&serverA.count))
+
+	// 6. Close A
+
+	_, err = s.Close(ctx, conn)
+	require.NoError(t, err)
+	require.Equal(t, int32(0), atomic.LoadInt32(&serverA.count))
+}
+
 func TestConnectServer_DialTimeout(t *testing.T) {
 	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
 
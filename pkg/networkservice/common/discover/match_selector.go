@@ -20,62 +20,140 @@ package discover
 
 import (
 	"bytes"
+	"container/list"
+	"sync"
 	"text/template"
 	"time"
 
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
 	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/drainlabel"
+	"github.com/networkservicemesh/sdk/pkg/tools/labelselector"
 )
 
-// isSubset checks if B is a subset of A. TODO: reconsider this as a part of "tools"
-func isSubset(a, b, nsLabels map[string]string) bool {
-	if len(a) < len(b) {
-		return false
+type matchRoute struct {
+	destinationSelector labelselector.Selector
+}
+
+type match struct {
+	sourceSelector labelselector.Selector
+	routes         []*matchRoute
+}
+
+type networkServiceMatches struct {
+	ns      *registry.NetworkService
+	matches []*match
+}
+
+// matchesCacheSize is a max count of the Network Services with the parsed matches stored in matchesCache
+const matchesCacheSize = 256
+
+// matchesCache stores parsed matches for the recently requested Network Services, matches are parsed again only if
+// the Network Service has been registered with the different matches. Least recently used entries are evicted when
+// the cache is full.
+type matchesCache struct {
+	lock    sync.Mutex
+	entries map[string]*list.Element
+	lru     list.List
+}
+
+func (c *matchesCache) load(ns *registry.NetworkService) ([]*match, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if elem, ok := c.entries[ns.GetName()]; ok {
+		if entry := elem.Value.(*networkServiceMatches); proto.Equal(entry.ns, ns) {
+			c.lru.MoveToFront(elem)
+			return entry.matches, nil
+		}
+		c.lru.Remove(elem)
+		delete(c.entries, ns.GetName())
+	}
+
+	matches, err := parseMatches(ns)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.entries == nil {
+		c.entries = make(map[string]*list.Element)
+	}
+	c.entries[ns.GetName()] = c.lru.PushFront(&networkServiceMatches{
+		ns:      proto.Clone(ns).(*registry.NetworkService),
+		matches: matches,
+	})
+	for c.lru.Len() > matchesCacheSize {
+		elem := c.lru.Back()
+		c.lru.Remove(elem)
+		delete(c.entries, elem.Value.(*networkServiceMatches).ns.GetName())
 	}
-	for k, v := range b {
-		if a[k] != v {
-			result := ProcessLabels(v, nsLabels)
-			if a[k] != result {
-				return false
+
+	return matches, nil
+}
+
+func parseMatches(ns *registry.NetworkService) ([]*match, error) {
+	var matches []*match
+	for _, m := range ns.GetMatches() {
+		sourceSelector, err := labelselector.Parse(m.GetSourceSelector())
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid source selector for the Network Service: %s", ns.GetName())
+		}
+		parsed := &match{
+			sourceSelector: sourceSelector,
+		}
+		for _, route := range m.GetRoutes() {
+			destinationSelector, err := labelselector.Parse(route.GetDestinationSelector())
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid destination selector for the Network Service: %s", ns.GetName())
 			}
+			parsed.routes = append(parsed.routes, &matchRoute{
+				destinationSelector: destinationSelector,
+			})
 		}
+		matches = append(matches, parsed)
 	}
-	return true
+	return matches, nil
 }
 
-func matchEndpoint(nsLabels map[string]string, ns *registry.NetworkService, networkServiceEndpoints ...*registry.NetworkServiceEndpoint) []*registry.NetworkServiceEndpoint {
+func matchEndpoint(nsLabels map[string]string, ns *registry.NetworkService, matches []*match, networkServiceEndpoints ...*registry.NetworkServiceEndpoint) []*registry.NetworkServiceEndpoint {
 	var validNetworkServiceEndpoints []*registry.NetworkServiceEndpoint
 	for _, nse := range networkServiceEndpoints {
+		if drainlabel.IsDraining(nse) {
+			continue
+		}
 		if nse.GetExpirationTime() == nil || nse.GetExpirationTime().AsTime().After(time.Now()) {
 			validNetworkServiceEndpoints = append(validNetworkServiceEndpoints, nse)
 		}
 	}
 
 	// Iterate through the matches
-	for _, match := range ns.GetMatches() {
-		// All match source selector labels should be present in the requested labels map
-		if !isSubset(nsLabels, match.GetSourceSelector(), nsLabels) {
+	for _, match := range matches {
+		// All match source selector requirements should be satisfied by the requested labels
+		if !match.sourceSelector.Matches(nsLabels, nsLabels) {
 			continue
 		}
+
 		nseCandidates := make([]*registry.NetworkServiceEndpoint, 0)
 		// Check all Destinations in that match
-		for _, destination := range match.GetRoutes() {
+		for _, route := range match.routes {
 			// Each NSE should be matched against that destination
 			for _, nse := range validNetworkServiceEndpoints {
-				if isSubset(nse.GetNetworkServiceLabels()[ns.Name].Labels, destination.GetDestinationSelector(), nsLabels) {
+				if route.destinationSelector.Matches(nse.GetNetworkServiceLabels()[ns.Name].GetLabels(), nsLabels) {
 					nseCandidates = append(nseCandidates, nse)
 				}
 			}
 		}
 		return nseCandidates
 	}
-
 	return validNetworkServiceEndpoints
 }
 
 // ProcessLabels generates matches based on destination label selectors that specify templating.
 func ProcessLabels(str string, vars interface{}) string {
 	tmpl, err := template.New("tmpl").Parse(str)
-
 	if err != nil {
 		panic(err)
 	}
@@ -84,7 +162,6 @@ func ProcessLabels(str string, vars interface{}) string {
 
 func process(t *template.Template, vars interface{}) string {
 	var tmplBytes bytes.Buffer
-
 	err := t.Execute(&tmplBytes, vars)
 	if err != nil {
 		panic(err)
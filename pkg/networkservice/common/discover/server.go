@@ -31,11 +31,13 @@ import (
 
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
 	"github.com/networkservicemesh/sdk/pkg/tools/clienturlctx"
+	"github.com/networkservicemesh/sdk/pkg/tools/restorectx"
 )
 
 type discoverCandidatesServer struct {
 	nseClient registry.NetworkServiceEndpointRegistryClient
 	nsClient  registry.NetworkServiceRegistryClient
+	matches   matchesCache
 }
 
 // NewServer - creates a new NetworkServiceServer that can discover possible candidates for providing a requested
@@ -48,6 +50,11 @@ func NewServer(nsClient registry.NetworkServiceRegistryClient, nseClient registr
 }
 
 func (d *discoverCandidatesServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	if restorectx.IsRestore(ctx) && clienturlctx.ClientURL(ctx) != nil {
+		// Restored connection is connected to the stored client URL without the discovery
+		return next.Server(ctx).Request(ctx, request)
+	}
+
 	nseName := request.GetConnection().GetNetworkServiceEndpointName()
 	if nseName != "" {
 		nse, err := d.discoverNetworkServiceEndpoint(ctx, nseName)
@@ -131,16 +138,14 @@ func (d *discoverCandidatesServer) discoverNetworkServiceEndpoint(ctx context.Co
 		}
 	}
 
-	query.Watch = true
+	ctx, cancelFind := context.WithCancel(ctx)
+	defer cancelFind()
 
-	nseStream, err = d.nseClient.Find(ctx, query)
-	if err != nil {
-		return nil, errors.WithStack(err)
-	}
+	watcher := newNSEWatcher(ctx, d.nseClient, query)
 	for {
-		var nse *registry.NetworkServiceEndpoint
-		if nse, err = nseStream.Recv(); err != nil {
-			return nil, errors.WithStack(err)
+		nse, err := watcher.Recv()
+		if err != nil {
+			return nil, err
 		}
 
 		if nse.Name == nseName {
@@ -150,6 +155,11 @@ func (d *discoverCandidatesServer) discoverNetworkServiceEndpoint(ctx context.Co
 }
 
 func (d *discoverCandidatesServer) discoverNetworkServiceEndpoints(ctx context.Context, ns *registry.NetworkService, labels map[string]string) ([]*registry.NetworkServiceEndpoint, error) {
+	matches, err := d.matches.load(ns)
+	if err != nil {
+		return nil, err
+	}
+
 	query := &registry.NetworkServiceEndpointQuery{
 		NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{
 			NetworkServiceNames: []string{ns.Name},
@@ -162,27 +172,22 @@ func (d *discoverCandidatesServer) discoverNetworkServiceEndpoints(ctx context.C
 	}
 	nseList := registry.ReadNetworkServiceEndpointList(nseStream)
 
-	result := matchEndpoint(labels, ns, nseList...)
+	result := matchEndpoint(labels, ns, matches, nseList...)
 	if len(result) != 0 {
 		return result, nil
 	}
 
-	query.Watch = true
-
 	ctx, cancelFind := context.WithCancel(ctx)
 	defer cancelFind()
 
-	nseStream, err = d.nseClient.Find(ctx, query)
-	if err != nil {
-		return nil, errors.WithStack(err)
-	}
+	watcher := newNSEWatcher(ctx, d.nseClient, query)
 	for {
-		var nse *registry.NetworkServiceEndpoint
-		if nse, err = nseStream.Recv(); err != nil {
-			return nil, errors.WithStack(err)
+		nse, err := watcher.Recv()
+		if err != nil {
+			return nil, err
 		}
 
-		result = matchEndpoint(labels, ns, nse)
+		result = matchEndpoint(labels, ns, matches, nse)
 		if len(result) != 0 {
 			return result, nil
 		}
@@ -212,16 +217,11 @@ func (d *discoverCandidatesServer) discoverNetworkService(ctx context.Context, n
 	ctx, cancelFind := context.WithCancel(ctx)
 	defer cancelFind()
 
-	query.Watch = true
-
-	nsStream, err = d.nsClient.Find(ctx, query)
-	if err != nil {
-		return nil, errors.WithStack(err)
-	}
+	watcher := newNSWatcher(ctx, d.nsClient, query)
 	for {
-		var ns *registry.NetworkService
-		if ns, err = nsStream.Recv(); err != nil {
-			return nil, errors.WithStack(err)
+		ns, err := watcher.Recv()
+		if err != nil {
+			return nil, err
 		}
 
 		if ns.Name == name {
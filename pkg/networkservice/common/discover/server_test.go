@@ -39,6 +39,7 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
 	registrynext "github.com/networkservicemesh/sdk/pkg/registry/core/next"
 	"github.com/networkservicemesh/sdk/pkg/tools/clienturlctx"
+	"github.com/networkservicemesh/sdk/pkg/tools/drainlabel"
 )
 
 func endpoints() []*registry.NetworkServiceEndpoint {
@@ -279,6 +280,39 @@ func TestMatchNothing(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestMatchDrainingNSE(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	nsName := networkServiceName()
+	nses := endpoints()
+	nses[2].NetworkServiceLabels[nsName].Labels[drainlabel.Key] = drainlabel.Value
+
+	nsServer, nseServer := testServers(t, nsName, nses, fromFirewallMatch(), fromSomeMiddleAppMatch())
+
+	request := &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			NetworkService: nsName,
+			Labels: map[string]string{
+				"app": "unknown-app",
+			},
+		},
+	}
+
+	server := next.NewNetworkServiceServer(
+		discover.NewServer(adapters.NetworkServiceServerToClient(nsServer), adapters.NetworkServiceEndpointServerToClient(nseServer)),
+		checkcontext.NewServer(t, func(t *testing.T, ctx context.Context) {
+			nses := discover.Candidates(ctx).Endpoints
+			require.Len(t, nses, 2)
+			for _, nse := range nses {
+				require.False(t, drainlabel.IsDraining(nse))
+			}
+		}),
+	)
+
+	_, err := server.Request(context.Background(), request)
+	require.NoError(t, err)
+}
+
 func TestMatchSelectedNSE(t *testing.T) {
 	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
 
@@ -540,6 +574,121 @@ func TestMatchSelectedNSESecondAttempt(t *testing.T) {
 	})
 }
 
+func TestMatchSetBasedSelectors(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	nsName := networkServiceName()
+	nsServer, nseServer := testServers(t, nsName, endpoints(), &registry.Match{
+		SourceSelector: map[string]string{
+			"app":     "in (firewall, some-middle-app)",
+			"!canary": "",
+		},
+		Routes: []*registry.Destination{
+			{
+				DestinationSelector: map[string]string{
+					"app": "notin (firewall, {{.app}})",
+				},
+			},
+		},
+	})
+
+	request := &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			NetworkService: nsName,
+			Labels: map[string]string{
+				"app": "some-middle-app",
+			},
+		},
+	}
+
+	server := next.NewNetworkServiceServer(
+		discover.NewServer(adapters.NetworkServiceServerToClient(nsServer), adapters.NetworkServiceEndpointServerToClient(nseServer)),
+		checkcontext.NewServer(t, func(t *testing.T, ctx context.Context) {
+			nses := discover.Candidates(ctx).Endpoints
+			require.Len(t, nses, 1)
+			require.Equal(t, labels(nsName, map[string]string{"app": "vpn-gateway"}), nses[0].NetworkServiceLabels)
+		}),
+	)
+
+	_, err := server.Request(context.Background(), request)
+	require.NoError(t, err)
+}
+
+func TestMatchExistsSelector(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	nsName := networkServiceName()
+	nses := endpoints()
+	nses[0].NetworkServiceLabels[nsName].Labels["app"] = "*"
+	delete(nses[2].NetworkServiceLabels[nsName].Labels, "app")
+
+	// "*" means Exists, "== *" matches the "*" label value
+	nsServer, nseServer := testServers(t, nsName, nses,
+		&registry.Match{
+			SourceSelector: map[string]string{"mode": "exists"},
+			Routes: []*registry.Destination{
+				{DestinationSelector: map[string]string{"app": "*"}},
+			},
+		},
+		&registry.Match{
+			SourceSelector: map[string]string{"mode": "equals"},
+			Routes: []*registry.Destination{
+				{DestinationSelector: map[string]string{"app": "== *"}},
+			},
+		},
+	)
+
+	for mode, want := range map[string][]string{
+		"exists": {nses[0].Name, nses[1].Name},
+		"equals": {nses[0].Name},
+	} {
+		request := &networkservice.NetworkServiceRequest{
+			Connection: &networkservice.Connection{
+				NetworkService: nsName,
+				Labels:         map[string]string{"mode": mode},
+			},
+		}
+
+		server := next.NewNetworkServiceServer(
+			discover.NewServer(adapters.NetworkServiceServerToClient(nsServer), adapters.NetworkServiceEndpointServerToClient(nseServer)),
+			checkcontext.NewServer(t, func(t *testing.T, ctx context.Context) {
+				var names []string
+				for _, nse := range discover.Candidates(ctx).Endpoints {
+					names = append(names, nse.Name)
+				}
+				require.ElementsMatch(t, want, names)
+			}),
+		)
+
+		_, err := server.Request(context.Background(), request)
+		require.NoError(t, err)
+	}
+}
+
+func TestMatchInvalidSelector(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	nsName := networkServiceName()
+	nsServer, nseServer := testServers(t, nsName, endpoints(), &registry.Match{
+		Routes: []*registry.Destination{
+			{
+				DestinationSelector: map[string]string{
+					"app": "in ()",
+				},
+			},
+		},
+	})
+
+	server := discover.NewServer(adapters.NetworkServiceServerToClient(nsServer), adapters.NetworkServiceEndpointServerToClient(nseServer))
+
+	_, err := server.Request(context.Background(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			NetworkService: nsName,
+		},
+	})
+	require.Error(t, err)
+}
+
 type injectConditionServer struct {
 	condition func() bool
 }
@@ -0,0 +1,137 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package discover
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/revision"
+)
+
+// nseWatcher receives the NSE events from the registry. If the stream breaks, the watch is resumed from the last
+// received revision, or relisted if the revision has been already compacted.
+type nseWatcher struct {
+	ctx      context.Context
+	client   registry.NetworkServiceEndpointRegistryClient
+	query    *registry.NetworkServiceEndpointQuery
+	watchCtx context.Context
+	stream   registry.NetworkServiceEndpointRegistry_FindClient
+	received bool
+}
+
+func newNSEWatcher(ctx context.Context, client registry.NetworkServiceEndpointRegistryClient, query *registry.NetworkServiceEndpointQuery) *nseWatcher {
+	query.Watch = true
+	return &nseWatcher{
+		ctx:      ctx,
+		client:   client,
+		query:    query,
+		watchCtx: revision.WithRelist(ctx),
+	}
+}
+
+// Recv returns the next NSE event, unregister events are skipped
+func (w *nseWatcher) Recv() (*registry.NetworkServiceEndpoint, error) {
+	for {
+		if w.stream == nil {
+			stream, err := w.client.Find(w.watchCtx, w.query)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			w.stream, w.received = stream, false
+		}
+
+		nse, err := w.stream.Recv()
+		if err == nil {
+			w.received = true
+			if rev, ok := revision.Get(nse); ok {
+				w.watchCtx = revision.WithStart(w.ctx, rev)
+				revision.Clear(nse)
+			}
+			if nse.ExpirationTime != nil && nse.ExpirationTime.Seconds < 0 {
+				continue
+			}
+			return nse.Clone(), nil
+		}
+
+		switch {
+		case w.ctx.Err() != nil:
+			return nil, errors.WithStack(err)
+		case revision.IsTooOld(err):
+			w.watchCtx = revision.WithRelist(w.ctx)
+		case !w.received:
+			return nil, errors.WithStack(err)
+		}
+		w.stream = nil
+	}
+}
+
+// nsWatcher is the same as nseWatcher for the NS events
+type nsWatcher struct {
+	ctx      context.Context
+	client   registry.NetworkServiceRegistryClient
+	query    *registry.NetworkServiceQuery
+	watchCtx context.Context
+	stream   registry.NetworkServiceRegistry_FindClient
+	received bool
+}
+
+func newNSWatcher(ctx context.Context, client registry.NetworkServiceRegistryClient, query *registry.NetworkServiceQuery) *nsWatcher {
+	query.Watch = true
+	return &nsWatcher{
+		ctx:      ctx,
+		client:   client,
+		query:    query,
+		watchCtx: revision.WithRelist(ctx),
+	}
+}
+
+// Recv returns the next NS event
+func (w *nsWatcher) Recv() (*registry.NetworkService, error) {
+	for {
+		if w.stream == nil {
+			stream, err := w.client.Find(w.watchCtx, w.query)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			w.stream, w.received = stream, false
+		}
+
+		ns, err := w.stream.Recv()
+		if err == nil {
+			w.received = true
+			if rev, ok := revision.Get(ns); ok {
+				w.watchCtx = revision.WithStart(w.ctx, rev)
+				revision.Clear(ns)
+			}
+			return ns.Clone(), nil
+		}
+
+		switch {
+		case w.ctx.Err() != nil:
+			return nil, errors.WithStack(err)
+		case revision.IsTooOld(err):
+			w.watchCtx = revision.WithRelist(w.ctx)
+		case !w.received:
+			return nil, errors.WithStack(err)
+		}
+		w.stream = nil
+	}
+}
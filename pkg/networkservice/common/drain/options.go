@@ -0,0 +1,38 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drain
+
+import (
+	"github.com/networkservicemesh/api/pkg/api/registry"
+)
+
+type drainOptions struct {
+	registryClient registry.NetworkServiceEndpointRegistryClient
+	nse            *registry.NetworkServiceEndpoint
+}
+
+// Option is an option pattern for NewServer
+type Option func(o *drainOptions)
+
+// WithRegistryClient sets the registry client and the NSE registration to re-register the NSE marked as draining on
+// Drain, so discover stops selecting it. nse is read on Drain, so it can be completed after NewServer.
+func WithRegistryClient(registryClient registry.NetworkServiceEndpointRegistryClient, nse *registry.NetworkServiceEndpoint) Option {
+	return func(o *drainOptions) {
+		o.registryClient = registryClient
+		o.nse = nse
+	}
+}
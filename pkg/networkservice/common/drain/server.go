@@ -0,0 +1,173 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package drain provides a NetworkServiceServer chain element taking the endpoint out of service without dropping
+// the traffic
+package drain
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/drainlabel"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+// Server is a NetworkServiceServer which can be drained
+type Server interface {
+	networkservice.NetworkServiceServer
+
+	// Drain rejects the new connections with codes.Unavailable, marks the NSE as draining in the registry and keeps
+	// serving the refreshes of the existing connections for the gracePeriod. Connections left after the gracePeriod
+	// are closed, so the previous hops heal them with another endpoint. Drain returns when all the connections are
+	// closed or ctx is done.
+	Drain(ctx context.Context, gracePeriod time.Duration) error
+}
+
+type drainServer struct {
+	ctx         context.Context
+	clock       clock.Clock
+	closeServer *networkservice.NetworkServiceServer
+	options     *drainOptions
+
+	lock          sync.Mutex
+	conns         map[string]*networkservice.Connection
+	draining      bool
+	drained       chan struct{}
+	drainedClosed bool
+}
+
+// NewServer creates a new drain Server. It should be placed after the `timeout` element, so it receives the timeout
+// Closes.
+//             - closeServer - *networkservice.NetworkServiceServer. Points to the chain containing this element, the
+//                             connections left after the grace period are closed with it as if they were closed by
+//                             the previous hop. It is read on Drain, so it can be set after NewServer.
+func NewServer(ctx context.Context, closeServer *networkservice.NetworkServiceServer, options ...Option) Server {
+	o := new(drainOptions)
+	for _, opt := range options {
+		opt(o)
+	}
+
+	return &drainServer{
+		ctx:         ctx,
+		clock:       clock.FromContext(ctx),
+		closeServer: closeServer,
+		options:     o,
+		conns:       make(map[string]*networkservice.Connection),
+		drained:     make(chan struct{}),
+	}
+}
+
+func (s *drainServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	connID := request.GetConnection().GetId()
+	index := request.GetConnection().GetPath().GetIndex()
+
+	s.lock.Lock()
+	_, ok := s.conns[connID]
+	draining := s.draining
+	s.lock.Unlock()
+
+	if draining && !ok {
+		return nil, status.Errorf(codes.Unavailable, "endpoint is draining, new connection is rejected: %s", connID)
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store the connection the previous hop closes
+	closeConn := conn.Clone()
+	if index > 0 {
+		closeConn.Path.Index = index - 1
+		closeConn.Id = closeConn.GetCurrentPathSegment().GetId()
+	}
+
+	s.lock.Lock()
+	s.conns[conn.GetId()] = closeConn
+	s.lock.Unlock()
+
+	return conn, nil
+}
+
+func (s *drainServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	s.lock.Lock()
+	delete(s.conns, conn.GetId())
+	s.checkDrained()
+	s.lock.Unlock()
+
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func (s *drainServer) Drain(ctx context.Context, gracePeriod time.Duration) error {
+	s.lock.Lock()
+	s.draining = true
+	s.checkDrained()
+	s.lock.Unlock()
+
+	if s.options.registryClient != nil {
+		nse := s.options.nse.Clone()
+		drainlabel.Mark(nse)
+		// Registration expiration time is set on the previous Register, so it should be renewed
+		nse.ExpirationTime = nil
+		if _, err := s.options.registryClient.Register(ctx, nse); err != nil {
+			return errors.Wrapf(err, "failed to mark NSE as draining: %s", nse.GetName())
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "drain is interrupted")
+	case <-s.drained:
+		return nil
+	case <-s.clock.After(gracePeriod):
+	}
+
+	s.lock.Lock()
+	conns := make([]*networkservice.Connection, 0, len(s.conns))
+	for _, conn := range s.conns {
+		conns = append(conns, conn)
+	}
+	s.lock.Unlock()
+
+	logger := log.FromContext(ctx).WithField("drainServer", "Drain")
+	for _, conn := range conns {
+		closeCtx, cancel := context.WithCancel(s.ctx)
+		if _, err := (*s.closeServer).Close(closeCtx, conn.Clone()); err != nil {
+			logger.Errorf("failed to close connection: %s %s", conn.GetId(), err.Error())
+		}
+		cancel()
+	}
+
+	return nil
+}
+
+func (s *drainServer) checkDrained() {
+	if s.draining && len(s.conns) == 0 && !s.drainedClosed {
+		close(s.drained)
+		s.drainedClosed = true
+	}
+}
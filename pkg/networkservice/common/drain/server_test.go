@@ -0,0 +1,283 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drain_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/drain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/updatepath"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/refresh"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	registrynext "github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+	"github.com/networkservicemesh/sdk/pkg/tools/drainlabel"
+	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
+)
+
+const (
+	nsName  = "ns"
+	nseName = "nse"
+	waitFor = time.Second
+	tick    = 10 * time.Millisecond
+)
+
+type closeServer struct {
+	lock   sync.Mutex
+	closed []string
+}
+
+func (s *closeServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *closeServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	s.lock.Lock()
+	s.closed = append(s.closed, conn.GetId())
+	s.lock.Unlock()
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func (s *closeServer) Closed() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]string(nil), s.closed...)
+}
+
+type testEndpoint struct {
+	networkservice.NetworkServiceServer
+	drain.Server
+	counter *closeServer
+}
+
+func newTestEndpoint(ctx context.Context, options ...drain.Option) *testEndpoint {
+	e := &testEndpoint{
+		counter: new(closeServer),
+	}
+	e.Server = drain.NewServer(ctx, &e.NetworkServiceServer, options...)
+	e.NetworkServiceServer = next.NewNetworkServiceServer(
+		updatepath.NewServer(nseName),
+		e.Server,
+		e.counter,
+	)
+	return e
+}
+
+func (e *testEndpoint) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	return e.NetworkServiceServer.Request(ctx, request)
+}
+
+func (e *testEndpoint) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return e.NetworkServiceServer.Close(ctx, conn)
+}
+
+func newRequest(id string) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:             id,
+			NetworkService: nsName,
+			Path: &networkservice.Path{
+				PathSegments: []*networkservice.PathSegment{
+					{Name: "nsc", Id: id},
+				},
+			},
+		},
+	}
+}
+
+func TestDrainServer_RejectNewConnections(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	e := newTestEndpoint(ctx)
+
+	conn, err := e.Request(ctx, newRequest("1"))
+	require.NoError(t, err)
+
+	drainCh := make(chan error, 1)
+	go func() {
+		drainCh <- e.Drain(ctx, time.Hour)
+	}()
+
+	require.Eventually(t, func() bool {
+		_, err = e.Request(ctx, newRequest("2"))
+		return err != nil
+	}, waitFor, tick)
+	require.Equal(t, codes.Unavailable, grpcutils.UnwrapCode(err))
+
+	// Refresh is still served
+	refresh := newRequest("1")
+	refresh.Connection = conn.Clone()
+	conn, err = e.Request(ctx, refresh)
+	require.NoError(t, err)
+
+	require.Never(t, func() bool { return len(drainCh) > 0 }, 100*time.Millisecond, tick)
+
+	_, err = e.Close(ctx, conn)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return len(drainCh) > 0 }, waitFor, tick)
+	require.NoError(t, <-drainCh)
+	require.Len(t, e.counter.Closed(), 1)
+}
+
+func TestDrainServer_GracePeriod(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	e := newTestEndpoint(ctx)
+
+	conn, err := e.Request(ctx, newRequest("1"))
+	require.NoError(t, err)
+	nseConnID := conn.GetPath().GetPathSegments()[1].GetId()
+
+	require.NoError(t, e.Drain(ctx, 100*time.Millisecond))
+
+	// Connection is closed on behalf of the previous hop, so the endpoint side sees the same connection ID
+	require.Equal(t, []string{nseConnID}, e.counter.Closed())
+
+	// Nothing left to close
+	_, err = e.Request(ctx, newRequest("1"))
+	require.Error(t, err)
+	require.Equal(t, codes.Unavailable, grpcutils.UnwrapCode(err))
+}
+
+func TestDrainServer_Empty(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	e := newTestEndpoint(ctx)
+
+	require.NoError(t, e.Drain(ctx, time.Hour))
+	require.Empty(t, e.counter.Closed())
+}
+
+func TestDrainServer_Interrupted(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	e := newTestEndpoint(ctx)
+
+	_, err := e.Request(ctx, newRequest("1"))
+	require.NoError(t, err)
+
+	drainCtx, drainCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer drainCancel()
+
+	require.Error(t, e.Drain(drainCtx, time.Hour))
+	require.Empty(t, e.counter.Closed())
+}
+
+func TestDrainServer_RegistryMark(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	registryClient := adapters.NetworkServiceEndpointServerToClient(memory.NewNetworkServiceEndpointRegistryServer())
+
+	nse := &registry.NetworkServiceEndpoint{
+		Name:                nseName,
+		NetworkServiceNames: []string{nsName},
+	}
+	_, err := registryClient.Register(ctx, nse.Clone())
+	require.NoError(t, err)
+
+	e := newTestEndpoint(ctx, drain.WithRegistryClient(registryClient, nse))
+	require.NoError(t, e.Drain(ctx, time.Hour))
+
+	// Own registration is not changed
+	require.False(t, drainlabel.IsDraining(nse))
+
+	stream, err := registryClient.Find(ctx, &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{Name: nseName},
+	})
+	require.NoError(t, err)
+
+	nses := registry.ReadNetworkServiceEndpointList(stream)
+	require.Len(t, nses, 1)
+	require.True(t, drainlabel.IsDraining(nses[0]))
+}
+
+func TestDrainServer_RegistryMarkRenewsExpiration(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const expireTimeout = time.Hour
+
+	clockMock := clockmock.NewMock()
+	clockMock.Set(time.Now().Add(-expireTimeout + time.Second))
+	ctx = clock.WithClock(ctx, clockMock)
+
+	memoryClient := adapters.NetworkServiceEndpointServerToClient(memory.NewNetworkServiceEndpointRegistryServer())
+	registryClient := registrynext.NewNetworkServiceEndpointRegistryClient(
+		refresh.NewNetworkServiceEndpointRegistryClient(
+			refresh.WithChainContext(ctx),
+			refresh.WithDefaultExpiryDuration(expireTimeout),
+		),
+		memoryClient,
+	)
+
+	nse := &registry.NetworkServiceEndpoint{
+		Name:                nseName,
+		NetworkServiceNames: []string{nsName},
+		ExpirationTime:      timestamppb.New(clockMock.Now().Add(expireTimeout)),
+	}
+	_, err := memoryClient.Register(ctx, nse.Clone())
+	require.NoError(t, err)
+
+	// Most of the registration TTL has passed
+	clockMock.Add(expireTimeout - time.Second)
+
+	e := newTestEndpoint(ctx, drain.WithRegistryClient(registryClient, nse))
+	require.NoError(t, e.Drain(ctx, time.Hour))
+
+	stream, err := registryClient.Find(ctx, &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{Name: nseName},
+	})
+	require.NoError(t, err)
+
+	nses := registry.ReadNetworkServiceEndpointList(stream)
+	require.Len(t, nses, 1)
+	require.True(t, drainlabel.IsDraining(nses[0]))
+	require.Greater(t, clockMock.Until(nses[0].ExpirationTime.AsTime()), expireTimeout/2)
+}
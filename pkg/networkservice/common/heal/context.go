@@ -22,6 +22,7 @@ import (
 
 const (
 	registerClientFuncKey contextKeyType = "RegisterFunc"
+	healingKey            contextKeyType = "Healing"
 )
 
 type contextKeyType string
@@ -39,3 +40,16 @@ func registerClientFunc(ctx context.Context) RegisterClientFunc {
 	}
 	return nil
 }
+
+func withHealing(parent context.Context) context.Context {
+	if parent == nil {
+		panic("cannot create context from nil parent")
+	}
+	return context.WithValue(parent, healingKey, true)
+}
+
+// IsHealing returns true if the ctx is the context of the Request restoring or healing the connection
+func IsHealing(ctx context.Context) bool {
+	healing, _ := ctx.Value(healingKey).(bool)
+	return healing
+}
@@ -356,7 +356,7 @@ func (f *healServer) restoreConnection(ctx, baseCtx context.Context, request *ne
 	if deadline.After(expireTime) {
 		deadline = expireTime
 	}
-	requestCtx, requestCancel := context.WithDeadline(f.ctx, deadline)
+	requestCtx, requestCancel := context.WithDeadline(withHealing(f.ctx), deadline)
 	defer requestCancel()
 
 	for f.ctx.Err() == nil {
@@ -383,7 +383,7 @@ func (f *healServer) processHeal(baseCtx context.Context, request *networkservic
 	if candidates != nil || conn.GetPath().GetIndex() == 0 {
 		logEntry.Infof("Starting heal process for %s", conn.GetId())
 
-		healCtx, healCancel := context.WithCancel(f.ctx)
+		healCtx, healCancel := context.WithCancel(withHealing(f.ctx))
 		defer healCancel()
 
 		reRequest := request.Clone()
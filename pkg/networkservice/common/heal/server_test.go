@@ -112,6 +112,64 @@ func TestHealClient_Request(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestHealClient_RequestIsHealing(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+	eventCh := make(chan *networkservice.ConnectionEvent, 1)
+	defer close(eventCh)
+
+	isHealingCh := make(chan bool, 1)
+	onHeal := &testOnHeal{
+		RequestFunc: func(ctx context.Context, in *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (connection *networkservice.Connection, e error) {
+			if ctx.Err() == nil {
+				select {
+				case isHealingCh <- heal.IsHealing(ctx):
+				default:
+				}
+			}
+			return &networkservice.Connection{}, nil
+		},
+	}
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+	monitorServer := eventchannel.NewMonitorServer(eventCh)
+	server := chain.NewNetworkServiceServer(
+		updatepath.NewServer("testServer"),
+		monitor.NewServer(ctx, &monitorServer),
+		updatetoken.NewServer(sandbox.GenerateTestToken),
+	)
+	healServer := heal.NewServer(ctx, addressof.NetworkServiceClient(onHeal))
+	client := chain.NewNetworkServiceClient(
+		updatepath.NewClient("testClient"),
+		adapters.NewServerToClient(healServer),
+		heal.NewClient(ctx, adapters.NewMonitorServerToClient(monitorServer)),
+		adapters.NewServerToClient(updatetoken.NewServer(sandbox.GenerateTestToken)),
+		adapters.NewServerToClient(server),
+	)
+
+	requestCtx, reqCancelFunc := context.WithTimeout(ctx, waitForTimeout)
+	defer reqCancelFunc()
+	conn, err := client.Request(requestCtx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			NetworkService: "ns-1",
+		},
+	})
+	require.Nil(t, err)
+
+	_, err = server.Close(requestCtx, conn.Clone())
+	require.NoError(t, err)
+
+	select {
+	case <-time.After(waitHealTimeout):
+		require.FailNow(t, "timeout waiting for Heal event")
+	case isHealing := <-isHealingCh:
+		require.True(t, isHealing)
+	}
+
+	_, err = client.Close(requestCtx, conn)
+	require.NoError(t, err)
+}
+
 func TestHealClient_EmptyInit(t *testing.T) {
 	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
 	eventCh := make(chan *networkservice.ConnectionEvent, 1)
@@ -32,6 +32,7 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/registry/common/interpose"
 	"github.com/networkservicemesh/sdk/pkg/tools/clienturlctx"
 	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/restorectx"
 	"github.com/networkservicemesh/sdk/pkg/tools/stringurl"
 )
 
@@ -86,6 +87,10 @@ func (l *interposeServer) Request(ctx context.Context, request *networkservice.N
 			return nil, errors.Errorf("connection id should match current path segment id")
 		}
 
+		if restorectx.IsRestore(ctx) {
+			return l.restore(ctx, request, clientURL)
+		}
+
 		// Iterate over all cross connect NSEs to check one with passed state.
 		l.interposeNSEs.Range(func(_ string, crossNSEURL *url.URL) bool {
 			crossCTX := clienturlctx.WithClientURL(ctx, crossNSEURL)
@@ -118,7 +123,12 @@ func (l *interposeServer) Request(ctx context.Context, request *networkservice.N
 		crossCTX = clienturlctx.WithClientURL(ctx, connInfo.interposeNSEURL)
 	} else {
 		// Go to endpoint URL if it matches one we had on previous step.
-		if clientURL != connInfo.endpointURL && *clientURL != *connInfo.endpointURL {
+		if connInfo.endpointURL == nil {
+			// Restored connection gets the endpoint URL on the first return pass
+			connInfo.endpointURL = clientURL
+			l.activeConnection.Store(connInfo.clientConnID, connInfo)
+			l.activeConnection.Store(connID, connInfo)
+		} else if clientURL != connInfo.endpointURL && *clientURL != *connInfo.endpointURL {
 			return nil, errors.Errorf("new selected endpoint URL %v doesn't match endpoint URL selected before interpose NSE %v", clientURL, connInfo.endpointURL)
 		}
 		crossCTX = ctx
@@ -127,6 +137,32 @@ func (l *interposeServer) Request(ctx context.Context, request *networkservice.N
 	return next.Server(crossCTX).Request(crossCTX, request)
 }
 
+// restore connects the restored connection to the cross NSE it has been connected to before the restart. The stored
+// client URL is the cross NSE URL, the endpoint URL is set on the restored return pass.
+func (l *interposeServer) restore(ctx context.Context, request *networkservice.NetworkServiceRequest, crossNSEURL *url.URL) (*networkservice.Connection, error) {
+	connID := request.GetConnection().GetId()
+
+	var found bool
+	l.interposeNSEs.Range(func(_ string, u *url.URL) bool {
+		found = crossNSEURL != nil && *u == *crossNSEURL
+		return !found
+	})
+	if !found {
+		return nil, errors.Errorf("cross NSE %v is not found for the restored connection: %v", crossNSEURL, connID)
+	}
+
+	l.activeConnection.Store(connID, connectionInfo{
+		clientConnID:    connID,
+		interposeNSEURL: crossNSEURL,
+	})
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		l.activeConnection.Delete(connID)
+		return nil, err
+	}
+	return conn, nil
+}
+
 func (l *interposeServer) getConnectionID(conn *networkservice.Connection) (string, bool) {
 	id := conn.Id
 	for i := conn.GetPath().GetIndex() - 1; i > 0; i-- {
@@ -0,0 +1,52 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+)
+
+type metricsClient struct {
+	*recorder
+}
+
+// NewClient creates a networkservice.NetworkServiceClient chain element recording the metrics labelled by the
+// chainName, requested NetworkService and selected NSE
+func NewClient(chainName string, options ...Option) networkservice.NetworkServiceClient {
+	return &metricsClient{
+		recorder: newRecorder(clientSide, chainName, options...),
+	}
+}
+
+func (c *metricsClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	return c.request(ctx, request, func() (*networkservice.Connection, error) {
+		return next.Client(ctx).Request(ctx, request, opts...)
+	})
+}
+
+func (c *metricsClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	return c.close(ctx, conn, func() (*empty.Empty, error) {
+		return next.Client(ctx).Close(ctx, conn, opts...)
+	})
+}
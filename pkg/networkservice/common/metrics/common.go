@@ -0,0 +1,171 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/heal"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
+	"github.com/networkservicemesh/sdk/pkg/tools/metrics"
+)
+
+const (
+	serverSide = "server"
+	clientSide = "client"
+
+	requestMethod = "Request"
+	closeMethod   = "Close"
+)
+
+// Metrics recorded by the chain elements
+const (
+	CallsMetric             = "nsm_networkservice_calls_total"
+	ErrorsMetric            = "nsm_networkservice_errors_total"
+	DurationMetric          = "nsm_networkservice_call_duration_seconds"
+	ActiveConnectionsMetric = "nsm_networkservice_active_connections"
+	HealAttemptsMetric      = "nsm_networkservice_heal_attempts"
+)
+
+type connectionLabels struct {
+	networkService, nse string
+}
+
+type recorder struct {
+	side, chainName string
+
+	calls    *metrics.CounterVec
+	errors   *metrics.CounterVec
+	duration *metrics.HistogramVec
+	active   *metrics.GaugeVec
+	heal     *metrics.GaugeVec
+
+	connections map[string]connectionLabels
+	lock        sync.Mutex
+}
+
+func newRecorder(side, chainName string, options ...Option) *recorder {
+	o := &metricsOptions{
+		registry: metrics.Default(),
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+
+	return &recorder{
+		side:      side,
+		chainName: chainName,
+		calls: o.registry.Counter(CallsMetric, "Number of the NetworkService calls by gRPC code",
+			"side", "chain", "method", "network_service", "nse", "code"),
+		errors: o.registry.Counter(ErrorsMetric, "Number of the failed NetworkService calls by gRPC code",
+			"side", "chain", "method", "network_service", "nse", "code"),
+		duration: o.registry.Histogram(DurationMetric, "Duration of the NetworkService calls in seconds", o.buckets,
+			"side", "chain", "method", "network_service", "nse"),
+		active: o.registry.Gauge(ActiveConnectionsMetric, "Number of the active connections",
+			"side", "chain", "network_service", "nse"),
+		heal: o.registry.Gauge(HealAttemptsMetric, "Number of the heal attempts in progress",
+			"side", "chain", "network_service"),
+		connections: make(map[string]connectionLabels),
+	}
+}
+
+func (r *recorder) request(
+	ctx context.Context,
+	request *networkservice.NetworkServiceRequest,
+	call func() (*networkservice.Connection, error),
+) (*networkservice.Connection, error) {
+	networkService := request.GetConnection().GetNetworkService()
+
+	if heal.IsHealing(ctx) {
+		healAttempts := r.heal.With(r.side, r.chainName, networkService)
+		healAttempts.Inc()
+		defer healAttempts.Dec()
+	}
+
+	start := clock.FromContext(ctx).Now()
+	conn, err := call()
+
+	nse := request.GetConnection().GetNetworkServiceEndpointName()
+	if conn.GetNetworkServiceEndpointName() != "" {
+		nse = conn.GetNetworkServiceEndpointName()
+	}
+	r.record(ctx, requestMethod, networkService, nse, start, err)
+
+	if err == nil {
+		r.connect(conn.GetId(), connectionLabels{networkService: networkService, nse: nse})
+	}
+
+	return conn, err
+}
+
+func (r *recorder) close(
+	ctx context.Context,
+	conn *networkservice.Connection,
+	call func() (*empty.Empty, error),
+) (*empty.Empty, error) {
+	start := clock.FromContext(ctx).Now()
+	rv, err := call()
+
+	r.record(ctx, closeMethod, conn.GetNetworkService(), conn.GetNetworkServiceEndpointName(), start, err)
+
+	// Connection is not active anymore even if Close has failed
+	r.disconnect(conn.GetId())
+
+	return rv, err
+}
+
+func (r *recorder) record(ctx context.Context, method, networkService, nse string, start time.Time, err error) {
+	code := grpcutils.UnwrapCode(err).String()
+
+	r.calls.With(r.side, r.chainName, method, networkService, nse, code).Inc()
+	if err != nil {
+		r.errors.With(r.side, r.chainName, method, networkService, nse, code).Inc()
+	}
+	r.duration.With(r.side, r.chainName, method, networkService, nse).Observe(clock.FromContext(ctx).Since(start).Seconds())
+}
+
+func (r *recorder) connect(id string, labels connectionLabels) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if old, ok := r.connections[id]; ok {
+		if old == labels {
+			return
+		}
+		// Connection has been healed to another NSE
+		r.active.With(r.side, r.chainName, old.networkService, old.nse).Dec()
+	}
+	r.connections[id] = labels
+	r.active.With(r.side, r.chainName, labels.networkService, labels.nse).Inc()
+}
+
+func (r *recorder) disconnect(id string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if labels, ok := r.connections[id]; ok {
+		delete(r.connections, id)
+		r.active.With(r.side, r.chainName, labels.networkService, labels.nse).Dec()
+	}
+}
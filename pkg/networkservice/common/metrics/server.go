@@ -0,0 +1,53 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics provides chain elements recording the NetworkService calls metrics: calls and errors by gRPC code,
+// calls latency, active connections and heal attempts. Use metrics.Handler from the tools to serve them.
+package metrics
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+)
+
+type metricsServer struct {
+	*recorder
+}
+
+// NewServer creates a networkservice.NetworkServiceServer chain element recording the metrics labelled by the
+// chainName, requested NetworkService and selected NSE
+func NewServer(chainName string, options ...Option) networkservice.NetworkServiceServer {
+	return &metricsServer{
+		recorder: newRecorder(serverSide, chainName, options...),
+	}
+}
+
+func (s *metricsServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	return s.request(ctx, request, func() (*networkservice.Connection, error) {
+		return next.Server(ctx).Request(ctx, request)
+	})
+}
+
+func (s *metricsServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return s.close(ctx, conn, func() (*empty.Empty, error) {
+		return next.Server(ctx).Close(ctx, conn)
+	})
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/metrics"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	metricstools "github.com/networkservicemesh/sdk/pkg/tools/metrics"
+)
+
+type selectNSEServer struct {
+	err error
+}
+
+func (s *selectNSEServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	request.GetConnection().NetworkServiceEndpointName = "nse-1"
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *selectNSEServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func writeText(t *testing.T, r *metricstools.Registry) string {
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteText(&buf))
+	return buf.String()
+}
+
+func TestMetrics(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	r := metricstools.NewRegistry()
+	selectServer := new(selectNSEServer)
+
+	client := next.NewNetworkServiceClient(
+		metrics.NewClient("nsc", metrics.WithRegistry(r)),
+		adapters.NewServerToClient(next.NewNetworkServiceServer(
+			metrics.NewServer("nsmgr", metrics.WithRegistry(r)),
+			selectServer,
+		)),
+	)
+
+	request := &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:             "id",
+			NetworkService: "ns",
+		},
+	}
+
+	// 1. Request and refresh
+	conn, err := client.Request(context.Background(), request.Clone())
+	require.NoError(t, err)
+
+	_, err = client.Request(context.Background(), request.Clone())
+	require.NoError(t, err)
+
+	text := writeText(t, r)
+	for _, side := range []string{`side="client",chain="nsc"`, `side="server",chain="nsmgr"`} {
+		require.Contains(t, text, metrics.CallsMetric+`{`+side+`,method="Request",network_service="ns",nse="nse-1",code="OK"} 2`)
+		require.Contains(t, text, metrics.ActiveConnectionsMetric+`{`+side+`,network_service="ns",nse="nse-1"} 1`)
+		require.Contains(t, text, metrics.DurationMetric+`_count{`+side+`,method="Request",network_service="ns",nse="nse-1"} 2`)
+	}
+	require.NotContains(t, text, metrics.ErrorsMetric)
+
+	// 2. Close
+	_, err = client.Close(context.Background(), conn)
+	require.NoError(t, err)
+
+	text = writeText(t, r)
+	require.Contains(t, text, metrics.CallsMetric+`{side="server",chain="nsmgr",method="Close",network_service="ns",nse="nse-1",code="OK"} 1`)
+	require.Contains(t, text, metrics.ActiveConnectionsMetric+`{side="server",chain="nsmgr",network_service="ns",nse="nse-1"} 0`)
+
+	// 3. Failed Request
+	selectServer.err = status.Error(codes.Unavailable, "no NSEs")
+
+	_, err = client.Request(context.Background(), request.Clone())
+	require.Error(t, err)
+
+	text = writeText(t, r)
+	require.Contains(t, text, metrics.ErrorsMetric+`{side="client",chain="nsc",method="Request",network_service="ns",nse="",code="Unavailable"} 1`)
+	require.Contains(t, text, metrics.ErrorsMetric+`{side="server",chain="nsmgr",method="Request",network_service="ns",nse="",code="Unavailable"} 1`)
+	require.Contains(t, text, metrics.ActiveConnectionsMetric+`{side="client",chain="nsc",network_service="ns",nse="nse-1"} 0`)
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+import (
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// Config is a connection quotas configuration. Every new connection is counted against the quota of the requested
+// Network Service, of the client identity and of the Network Service Endpoint. Zero quota means no limit.
+type Config struct {
+	// NetworkService is the default max count of the connections to every Network Service
+	NetworkService int `json:"networkService"`
+	// NetworkServices are the max counts of the connections to the Network Services by their names
+	NetworkServices map[string]int `json:"networkServices,omitempty"`
+	// Identity is the default max count of the connections of every client identity
+	Identity int `json:"identity"`
+	// Identities are the max counts of the connections of the clients by their identities
+	Identities map[string]int `json:"identities,omitempty"`
+	// Endpoint is the default max count of the connections to every Network Service Endpoint
+	Endpoint int `json:"endpoint"`
+	// Endpoints are the max counts of the connections to the Network Service Endpoints by their names
+	Endpoints map[string]int `json:"endpoints,omitempty"`
+}
+
+// ParseConfig parses YAML or JSON connection quotas configuration
+func ParseConfig(data []byte) (*Config, error) {
+	config := new(Config)
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, errors.Wrap(err, "failed to parse connection quotas config")
+	}
+	return config, nil
+}
+
+func (c *Config) networkServiceQuota(networkService string) int {
+	if quota, ok := c.NetworkServices[networkService]; ok {
+		return quota
+	}
+	return c.NetworkService
+}
+
+func (c *Config) identityQuota(identity string) int {
+	if quota, ok := c.Identities[identity]; ok {
+		return quota
+	}
+	return c.Identity
+}
+
+func (c *Config) endpointQuota(endpoint string) int {
+	if quota, ok := c.Endpoints[endpoint]; ok {
+		return quota
+	}
+	return c.Endpoint
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+type quotaOptions struct {
+	config     *Config
+	configPath string
+}
+
+// Option is an option pattern for NewServer
+type Option func(o *quotaOptions)
+
+// WithConfig sets the static connection quotas configuration
+func WithConfig(config *Config) Option {
+	return func(o *quotaOptions) {
+		o.config = config
+	}
+}
+
+// WithConfigPath sets the path of the YAML or JSON connection quotas configuration file. File is watched, so the
+// configuration changes are applied without a restart. Missing file means no quotas.
+func WithConfigPath(configPath string) Option {
+	return func(o *quotaOptions) {
+		o.configPath = configPath
+	}
+}
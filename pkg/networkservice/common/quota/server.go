@@ -0,0 +1,205 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package quota provides a networkservice.NetworkServiceServer chain element limiting the count of the concurrent
+// connections per Network Service, per client identity and per Network Service Endpoint
+package quota
+
+import (
+	"context"
+	"sync"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/updatepath"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/fs"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/ratelimit"
+)
+
+// slot is a connection counted against the quotas, empty keys are not counted
+type slot struct {
+	networkService string
+	identity       string
+	endpoint       string
+}
+
+type quotaServer struct {
+	ctx context.Context
+
+	lock            sync.Mutex
+	config          *Config
+	slots           map[string]slot
+	networkServices map[string]int
+	identities      map[string]int
+	endpoints       map[string]int
+}
+
+// NewServer creates a networkservice.NetworkServiceServer chain element limiting the count of the concurrent
+// connections. Requests for the new connections over the quotas fail with codes.ResourceExhausted, refreshes of the
+// existing connections are counted only once. Close always releases the slot, so the element should be placed after
+// the `timeout` to be closed on the connection expiration, e.g. added with endpoint.WithAdditionalFunctionality.
+// Client identity is the verified identity of the direct caller, see ratelimit.PeerIdentity.
+func NewServer(ctx context.Context, options ...Option) networkservice.NetworkServiceServer {
+	o := &quotaOptions{
+		config: new(Config),
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+
+	s := &quotaServer{
+		ctx:             ctx,
+		config:          o.config,
+		slots:           make(map[string]slot),
+		networkServices: make(map[string]int),
+		identities:      make(map[string]int),
+		endpoints:       make(map[string]int),
+	}
+
+	if o.configPath != "" {
+		updateCh := fs.WatchFile(ctx, o.configPath)
+		s.update(<-updateCh)
+		go func() {
+			for data := range updateCh {
+				s.update(data)
+			}
+		}()
+	}
+
+	return s
+}
+
+func (s *quotaServer) update(data []byte) {
+	config := new(Config)
+	if data != nil {
+		var err error
+		if config, err = ParseConfig(data); err != nil {
+			log.FromContext(s.ctx).Errorf("keeping the previous connection quotas config: %s", err.Error())
+			return
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.config = config
+}
+
+func (s *quotaServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	conn := request.GetConnection()
+	if updatepath.IsReturnPass(conn.GetPath()) {
+		// The connection has been already counted on its first pass through this chain
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	connID := conn.GetId()
+	newSlot := slot{
+		networkService: conn.GetNetworkService(),
+		identity:       ratelimit.PeerIdentity(ctx),
+		endpoint:       conn.GetNetworkServiceEndpointName(),
+	}
+
+	s.lock.Lock()
+	oldSlot, refresh := s.slots[connID]
+	if refresh && oldSlot == newSlot {
+		s.lock.Unlock()
+		return next.Server(ctx).Request(ctx, request)
+	}
+	if refresh {
+		s.release(oldSlot)
+	}
+	if err := s.check(newSlot); err != nil {
+		if refresh {
+			s.take(oldSlot)
+		}
+		s.lock.Unlock()
+		return nil, err
+	}
+	s.take(newSlot)
+	s.slots[connID] = newSlot
+	s.lock.Unlock()
+
+	rv, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		s.lock.Lock()
+		s.release(newSlot)
+		if refresh {
+			s.take(oldSlot)
+			s.slots[connID] = oldSlot
+		} else {
+			delete(s.slots, connID)
+		}
+		s.lock.Unlock()
+		return nil, err
+	}
+	return rv, nil
+}
+
+func (s *quotaServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	s.lock.Lock()
+	if sl, ok := s.slots[conn.GetId()]; ok {
+		s.release(sl)
+		delete(s.slots, conn.GetId())
+	}
+	s.lock.Unlock()
+
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func (s *quotaServer) check(sl slot) error {
+	if quota := s.config.networkServiceQuota(sl.networkService); exceeded(s.networkServices, sl.networkService, quota) {
+		return status.Errorf(codes.ResourceExhausted, "connection quota exceeded for the Network Service %q: %d", sl.networkService, quota)
+	}
+	if quota := s.config.identityQuota(sl.identity); exceeded(s.identities, sl.identity, quota) {
+		return status.Errorf(codes.ResourceExhausted, "connection quota exceeded for the client %q: %d", sl.identity, quota)
+	}
+	if quota := s.config.endpointQuota(sl.endpoint); exceeded(s.endpoints, sl.endpoint, quota) {
+		return status.Errorf(codes.ResourceExhausted, "connection quota exceeded for the Network Service Endpoint %q: %d", sl.endpoint, quota)
+	}
+	return nil
+}
+
+func (s *quotaServer) take(sl slot) {
+	add(s.networkServices, sl.networkService, 1)
+	add(s.identities, sl.identity, 1)
+	add(s.endpoints, sl.endpoint, 1)
+}
+
+func (s *quotaServer) release(sl slot) {
+	add(s.networkServices, sl.networkService, -1)
+	add(s.identities, sl.identity, -1)
+	add(s.endpoints, sl.endpoint, -1)
+}
+
+func exceeded(counts map[string]int, key string, quota int) bool {
+	return key != "" && quota > 0 && counts[key] >= quota
+}
+
+func add(counts map[string]int, key string, delta int) {
+	if key == "" {
+		return
+	}
+	counts[key] += delta
+	if counts[key] <= 0 {
+		delete(counts, key)
+	}
+}
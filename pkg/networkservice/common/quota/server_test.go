@@ -0,0 +1,265 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota_test
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc/codes"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/quota"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/serialize"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/timeout"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/inject/injecterror"
+	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
+	"github.com/networkservicemesh/sdk/pkg/tools/sandbox"
+)
+
+const (
+	nsName   = "ns"
+	nseName  = "nse"
+	clientA  = "spiffe://test.com/a"
+	clientB  = "spiffe://test.com/b"
+	lifetime = time.Hour
+	waitFor  = time.Second
+	tick     = 10 * time.Millisecond
+)
+
+func newRequest(t *testing.T, id, networkService, endpoint, spiffeID string, expires time.Duration) *networkservice.NetworkServiceRequest {
+	tok, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.StandardClaims{Subject: spiffeID}).SignedString([]byte("super secret"))
+	require.NoError(t, err)
+
+	expireTime, err := ptypes.TimestampProto(time.Now().Add(expires))
+	require.NoError(t, err)
+
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:                         id,
+			NetworkService:             networkService,
+			NetworkServiceEndpointName: endpoint,
+			Path: &networkservice.Path{
+				Index: 1,
+				PathSegments: []*networkservice.PathSegment{
+					{Name: "nsc", Id: id + "-nsc", Token: tok, Expires: expireTime},
+					{Name: nseName, Id: id},
+				},
+			},
+		},
+	}
+}
+
+func newServer(ctx context.Context, servers ...networkservice.NetworkServiceServer) networkservice.NetworkServiceServer {
+	return next.NewNetworkServiceServer(append([]networkservice.NetworkServiceServer{
+		serialize.NewServer(),
+		timeout.NewServer(ctx),
+	}, servers...)...)
+}
+
+func requireExhausted(t *testing.T, err error) {
+	require.Error(t, err)
+	require.Equal(t, codes.ResourceExhausted, grpcutils.UnwrapCode(err))
+}
+
+func TestQuotaServer_NetworkService(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := newServer(ctx, quota.NewServer(ctx, quota.WithConfig(&quota.Config{
+		NetworkService:  1,
+		NetworkServices: map[string]int{nsName: 2},
+	})))
+
+	conn1, err := server.Request(ctx, newRequest(t, "1", nsName, "", clientA, lifetime))
+	require.NoError(t, err)
+	_, err = server.Request(ctx, newRequest(t, "2", nsName, "", clientA, lifetime))
+	require.NoError(t, err)
+
+	_, err = server.Request(ctx, newRequest(t, "3", nsName, "", clientA, lifetime))
+	requireExhausted(t, err)
+
+	// Refresh is not counted twice
+	_, err = server.Request(ctx, newRequest(t, "1", nsName, "", clientA, lifetime))
+	require.NoError(t, err)
+
+	// Default quota is applied to the other Network Services
+	_, err = server.Request(ctx, newRequest(t, "4", "other", "", clientA, lifetime))
+	require.NoError(t, err)
+	_, err = server.Request(ctx, newRequest(t, "5", "other", "", clientA, lifetime))
+	requireExhausted(t, err)
+
+	_, err = server.Close(ctx, conn1)
+	require.NoError(t, err)
+
+	_, err = server.Request(ctx, newRequest(t, "3", nsName, "", clientA, lifetime))
+	require.NoError(t, err)
+}
+
+func TestQuotaServer_IdentityAndEndpoint(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := newServer(ctx, quota.NewServer(ctx, quota.WithConfig(&quota.Config{
+		Identities: map[string]int{clientA: 1},
+		Endpoint:   2,
+	})))
+
+	ctxA, ctxB := sandbox.WithPeerSpiffeID(ctx, t, clientA), sandbox.WithPeerSpiffeID(ctx, t, clientB)
+
+	_, err := server.Request(ctxA, newRequest(t, "1", nsName, nseName, clientA, lifetime))
+	require.NoError(t, err)
+	_, err = server.Request(ctxA, newRequest(t, "2", nsName, nseName, clientA, lifetime))
+	requireExhausted(t, err)
+
+	_, err = server.Request(ctxB, newRequest(t, "2", nsName, nseName, clientB, lifetime))
+	require.NoError(t, err)
+	_, err = server.Request(ctxB, newRequest(t, "3", nsName, nseName, clientB, lifetime))
+	requireExhausted(t, err)
+
+	// Refresh moving the connection to the full endpoint is rejected and keeps the previous slot
+	_, err = server.Request(ctxB, newRequest(t, "4", nsName, "other", clientB, lifetime))
+	require.NoError(t, err)
+	_, err = server.Request(ctxB, newRequest(t, "4", nsName, nseName, clientB, lifetime))
+	requireExhausted(t, err)
+	_, err = server.Request(ctxB, newRequest(t, "5", nsName, "other", clientB, lifetime))
+	require.NoError(t, err)
+	_, err = server.Request(ctxB, newRequest(t, "6", nsName, "other", clientB, lifetime))
+	requireExhausted(t, err)
+}
+
+func TestQuotaServer_ForgedToken(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := newServer(ctx, quota.NewServer(ctx, quota.WithConfig(&quota.Config{
+		Identity: 1,
+	})))
+
+	ctxA := sandbox.WithPeerSpiffeID(ctx, t, clientA)
+
+	// Path segment token of the other client doesn't give the caller a new quota
+	_, err := server.Request(ctxA, newRequest(t, "1", nsName, nseName, clientA, lifetime))
+	require.NoError(t, err)
+	_, err = server.Request(ctxA, newRequest(t, "2", nsName, nseName, clientB, lifetime))
+	requireExhausted(t, err)
+}
+
+func TestQuotaServer_TimeoutReleasesSlot(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := newServer(ctx, quota.NewServer(ctx, quota.WithConfig(&quota.Config{
+		NetworkService: 1,
+	})))
+
+	_, err := server.Request(ctx, newRequest(t, "1", nsName, "", clientA, 100*time.Millisecond))
+	require.NoError(t, err)
+
+	_, err = server.Request(ctx, newRequest(t, "2", nsName, "", clientA, lifetime))
+	requireExhausted(t, err)
+
+	require.Eventually(t, func() bool {
+		_, err = server.Request(ctx, newRequest(t, "2", nsName, "", clientA, lifetime))
+		return err == nil
+	}, waitFor, tick)
+}
+
+func TestQuotaServer_FailedRequestReleasesSlot(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	q := quota.NewServer(ctx, quota.WithConfig(&quota.Config{
+		NetworkService: 1,
+	}))
+
+	_, err := newServer(ctx, q, injecterror.NewServer()).Request(ctx, newRequest(t, "1", nsName, "", clientA, lifetime))
+	require.Error(t, err)
+
+	_, err = newServer(ctx, q).Request(ctx, newRequest(t, "2", nsName, "", clientA, lifetime))
+	require.NoError(t, err)
+}
+
+func TestQuotaServer_ReturnPass(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := newServer(ctx, quota.NewServer(ctx, quota.WithConfig(&quota.Config{
+		NetworkService: 1,
+	})))
+
+	_, err := server.Request(ctx, newRequest(t, "1", nsName, "", clientA, lifetime))
+	require.NoError(t, err)
+
+	// NSMgr -> Forwarder -> NSMgr
+	request := newRequest(t, "2", nsName, "", clientA, lifetime)
+	request.Connection.Path = &networkservice.Path{
+		Index: 2,
+		PathSegments: []*networkservice.PathSegment{
+			{Name: nseName, Id: "1"},
+			request.GetConnection().GetPath().GetPathSegments()[0],
+			{Name: nseName, Id: "2"},
+		},
+	}
+	_, err = server.Request(ctx, request)
+	require.NoError(t, err)
+}
+
+func TestQuotaServer_ConfigPath(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	configPath := filepath.Join(t.TempDir(), "quota.yaml")
+	require.NoError(t, ioutil.WriteFile(configPath, []byte("networkService: 1\n"), 0o600))
+
+	server := newServer(ctx, quota.NewServer(ctx, quota.WithConfigPath(configPath)))
+
+	_, err := server.Request(ctx, newRequest(t, "1", nsName, "", clientA, lifetime))
+	require.NoError(t, err)
+	_, err = server.Request(ctx, newRequest(t, "2", nsName, "", clientA, lifetime))
+	requireExhausted(t, err)
+
+	require.NoError(t, ioutil.WriteFile(configPath, []byte("networkService: 2\n"), 0o600))
+
+	require.Eventually(t, func() bool {
+		_, err = server.Request(ctx, newRequest(t, "2", nsName, "", clientA, lifetime))
+		return err == nil
+	}, waitFor, tick)
+}
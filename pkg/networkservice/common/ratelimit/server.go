@@ -0,0 +1,84 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ratelimit provides a networkservice.NetworkServiceServer chain element limiting the rate of the Requests
+package ratelimit
+
+import (
+	"context"
+	"sync"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/ratelimit"
+)
+
+type rateLimitServer struct {
+	limiter *ratelimit.Limiter
+
+	lock  sync.Mutex
+	conns map[string]string
+}
+
+// NewServer creates a networkservice.NetworkServiceServer chain element limiting the rate of the Requests by the peer
+// and by the requested Network Service. Rejected Requests fail with codes.ResourceExhausted. Refreshes of the
+// established connections by the same peer are not limited, so the existing connections are not lost under the load.
+// Close is not limited, so the resources are always freed. Close also forgets the established connection, so the
+// element should be placed after the `timeout` to be closed on the connection expiration.
+func NewServer(limiter *ratelimit.Limiter) networkservice.NetworkServiceServer {
+	return &rateLimitServer{
+		limiter: limiter,
+		conns:   make(map[string]string),
+	}
+}
+
+func (s *rateLimitServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	peer := ratelimit.PeerIdentity(ctx)
+
+	s.lock.Lock()
+	owner, established := s.conns[request.GetConnection().GetId()]
+	s.lock.Unlock()
+
+	if !established || owner != peer {
+		if err := s.limiter.Allow(ctx, request.GetConnection().GetNetworkService()); err != nil {
+			return nil, err
+		}
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if conn.GetId() != "" {
+		s.lock.Lock()
+		s.conns[conn.GetId()] = peer
+		s.lock.Unlock()
+	}
+
+	return conn, nil
+}
+
+func (s *rateLimitServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	s.lock.Lock()
+	delete(s.conns, conn.GetId())
+	s.lock.Unlock()
+
+	return next.Server(ctx).Close(ctx, conn)
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/ratelimit"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
+	ratelimittools "github.com/networkservicemesh/sdk/pkg/tools/ratelimit"
+	"github.com/networkservicemesh/sdk/pkg/tools/sandbox"
+)
+
+func TestRateLimitServer(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	clockMock.Set(time.Now())
+	ctx = clock.WithClock(ctx, clockMock)
+
+	limiter := ratelimittools.NewLimiter(ctx, ratelimittools.WithConfig(&ratelimittools.Config{
+		Peer: ratelimittools.Limit{Rate: 0.5, Burst: 1},
+	}))
+
+	server := grpc.NewServer()
+	networkservice.RegisterNetworkServiceServer(server, chain.NewNetworkServiceServer(
+		ratelimit.NewServer(limiter),
+	))
+
+	serveURL := &url.URL{Scheme: "tcp", Host: "127.0.0.1:0"}
+	require.Len(t, grpcutils.ListenAndServe(ctx, serveURL, server), 0)
+
+	cc, err := grpc.DialContext(ctx, grpcutils.URLToTarget(serveURL), grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	defer func() { _ = cc.Close() }()
+
+	client := networkservice.NewNetworkServiceClient(cc)
+
+	request := &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:             "id",
+			NetworkService: "ns",
+		},
+	}
+
+	// 1. First Request is allowed
+	conn, err := client.Request(ctx, request.Clone())
+	require.NoError(t, err)
+
+	// 2. Refresh of the established connection is not limited
+	refresh := request.Clone()
+	refresh.Connection = conn.Clone()
+	conn, err = client.Request(ctx, refresh)
+	require.NoError(t, err)
+
+	// 3. Request for the new connection is rejected with retry-after
+	newRequest := request.Clone()
+	newRequest.Connection.Id = "id-2"
+
+	var trailer metadata.MD
+	_, err = client.Request(ctx, newRequest, grpc.Trailer(&trailer))
+	require.Error(t, err)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	require.Equal(t, []string{"2"}, trailer.Get(ratelimittools.RetryAfterKey))
+
+	// 4. Close is not limited
+	_, err = client.Close(ctx, conn)
+	require.NoError(t, err)
+
+	// 5. Closed connection is not established anymore
+	_, err = client.Request(ctx, request.Clone())
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// 6. Request is allowed after the retry-after
+	clockMock.Add(2 * time.Second)
+
+	_, err = client.Request(ctx, request.Clone())
+	require.NoError(t, err)
+}
+
+func TestRateLimitServer_InProcess(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	limiter := ratelimittools.NewLimiter(ctx, ratelimittools.WithConfig(&ratelimittools.Config{
+		NetworkServices: map[string]ratelimittools.Limit{
+			"ns": {Rate: 0.001, Burst: 1},
+		},
+	}))
+
+	client := adapters.NewServerToClient(ratelimit.NewServer(limiter))
+
+	_, err := client.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{NetworkService: "ns"},
+	})
+	require.NoError(t, err)
+
+	_, err = client.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{NetworkService: "ns"},
+	})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	_, err = client.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{NetworkService: "other-ns"},
+	})
+	require.NoError(t, err)
+}
+
+func TestRateLimitServer_RefreshByAnotherPeer(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	limiter := ratelimittools.NewLimiter(ctx, ratelimittools.WithConfig(&ratelimittools.Config{
+		NetworkServices: map[string]ratelimittools.Limit{
+			"ns": {Rate: 0.001, Burst: 1},
+		},
+	}))
+
+	client := adapters.NewServerToClient(ratelimit.NewServer(limiter))
+
+	ctxA := sandbox.WithPeerSpiffeID(ctx, t, "spiffe://test.com/a")
+	ctxB := sandbox.WithPeerSpiffeID(ctx, t, "spiffe://test.com/b")
+
+	request := &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "id", NetworkService: "ns"},
+	}
+
+	_, err := client.Request(ctxA, request.Clone())
+	require.NoError(t, err)
+
+	_, err = client.Request(ctxA, request.Clone())
+	require.NoError(t, err)
+
+	// Connection established by A is not established for B
+	_, err = client.Request(ctxB, request.Clone())
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
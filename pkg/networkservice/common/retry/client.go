@@ -0,0 +1,109 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retry provides a NetworkServiceClient chain element retrying the failed Requests
+package retry
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type retryClient struct {
+	*retryOptions
+}
+
+// NewClient - returns a new client chain element retrying the Request failed with one of the retry codes with
+// exponential backoff and jitter until the max attempts count is reached or the ctx is done. Every attempt is made with
+// the same connection ID, so the retries are idempotent for the server chain elements. Close is not retried.
+func NewClient(options ...Option) networkservice.NetworkServiceClient {
+	return &retryClient{
+		retryOptions: newRetryOptions(options...),
+	}
+}
+
+func (c *retryClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	if request.GetConnection() == nil {
+		request.Connection = &networkservice.Connection{}
+	}
+	if request.GetConnection().GetId() == "" {
+		request.GetConnection().Id = uuid.New().String()
+	}
+
+	logger := log.FromContext(ctx).WithField("retryClient", "Request")
+	clk := clock.FromContext(ctx)
+
+	backoff := c.initialBackoff
+	for attempt := 1; ; attempt++ {
+		conn, err := next.Client(ctx).Request(ctx, request.Clone(), opts...)
+		if err == nil || !c.shouldRetry(err, attempt) {
+			return conn, err
+		}
+
+		delay := jitter(backoff)
+		if deadline, ok := ctx.Deadline(); ok && clk.Until(deadline) < delay {
+			logger.Warnf("no time left to retry after the attempt %d: %s", attempt, err.Error())
+			return nil, err
+		}
+		logger.Warnf("retrying in %s after the attempt %d: %s", delay, attempt, err.Error())
+
+		timer := clk.Timer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C():
+		}
+
+		backoff = time.Duration(float64(backoff) * c.multiplier)
+		if backoff > c.maxBackoff {
+			backoff = c.maxBackoff
+		}
+	}
+}
+
+func (c *retryClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	return next.Client(ctx).Close(ctx, conn, opts...)
+}
+
+func (c *retryClient) shouldRetry(err error, attempt int) bool {
+	if c.maxAttempts > 0 && attempt >= c.maxAttempts {
+		return false
+	}
+	_, ok := c.codes[grpcutils.UnwrapCode(err)]
+	return ok
+}
+
+// jitter returns a random delay in [backoff/2, backoff)
+func jitter(backoff time.Duration) time.Duration {
+	half := int64(backoff / 2)
+	if half <= 0 {
+		return backoff
+	}
+	return time.Duration(half + rand.Int63n(half))
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/cls"
+	kernelmech "github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/retry"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
+	"github.com/networkservicemesh/sdk/pkg/tools/sandbox"
+)
+
+const backoff = 10 * time.Millisecond
+
+// flakyClient fails the first Requests with the errors and records the connection IDs of all the Requests
+type flakyClient struct {
+	errs []error
+	ids  []string
+}
+
+func (c *flakyClient) Request(_ context.Context, request *networkservice.NetworkServiceRequest, _ ...grpc.CallOption) (*networkservice.Connection, error) {
+	c.ids = append(c.ids, request.GetConnection().GetId())
+	if len(c.ids) <= len(c.errs) {
+		return nil, c.errs[len(c.ids)-1]
+	}
+	return request.GetConnection(), nil
+}
+
+func (c *flakyClient) Close(_ context.Context, _ *networkservice.Connection, _ ...grpc.CallOption) (*empty.Empty, error) {
+	return new(empty.Empty), nil
+}
+
+func newRetryClient(flaky *flakyClient, options ...retry.Option) networkservice.NetworkServiceClient {
+	return chain.NewNetworkServiceClient(
+		retry.NewClient(append([]retry.Option{retry.WithBackoff(backoff, 2*backoff, 2)}, options...)...),
+		flaky,
+	)
+}
+
+func unavailable() error {
+	return status.Error(codes.Unavailable, "nsmgr is not ready")
+}
+
+func TestRetryClient_Unavailable(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	flaky := &flakyClient{errs: []error{unavailable(), unavailable()}}
+
+	conn, err := newRetryClient(flaky).Request(context.Background(), &networkservice.NetworkServiceRequest{})
+	require.NoError(t, err)
+
+	require.Len(t, flaky.ids, 3)
+	require.NotEmpty(t, flaky.ids[0])
+	for _, id := range flaky.ids {
+		require.Equal(t, flaky.ids[0], id)
+	}
+	require.Equal(t, flaky.ids[0], conn.GetId())
+}
+
+func TestRetryClient_NotRetryableCode(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	flaky := &flakyClient{errs: []error{status.Error(codes.InvalidArgument, "invalid request")}}
+
+	_, err := newRetryClient(flaky).Request(context.Background(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "id"},
+	})
+	require.Equal(t, codes.InvalidArgument, grpcutils.UnwrapCode(err))
+	require.Equal(t, []string{"id"}, flaky.ids)
+}
+
+func TestRetryClient_WithCodes(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	flaky := &flakyClient{errs: []error{status.Error(codes.ResourceExhausted, "rate limited"), unavailable()}}
+
+	_, err := newRetryClient(flaky, retry.WithCodes(codes.ResourceExhausted)).Request(context.Background(), &networkservice.NetworkServiceRequest{})
+	require.Equal(t, codes.Unavailable, grpcutils.UnwrapCode(err))
+	require.Len(t, flaky.ids, 2)
+}
+
+func TestRetryClient_MaxAttempts(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	flaky := &flakyClient{errs: []error{unavailable(), unavailable(), unavailable(), unavailable()}}
+
+	_, err := newRetryClient(flaky, retry.WithMaxAttempts(3)).Request(context.Background(), &networkservice.NetworkServiceRequest{})
+	require.Equal(t, codes.Unavailable, grpcutils.UnwrapCode(err))
+	require.Len(t, flaky.ids, 3)
+}
+
+func TestRetryClient_Deadline(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	flaky := &flakyClient{errs: []error{unavailable(), unavailable()}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	client := newRetryClient(flaky, retry.WithMaxAttempts(0), retry.WithBackoff(time.Hour, time.Hour, 2))
+
+	start := time.Now()
+	_, err := client.Request(ctx, &networkservice.NetworkServiceRequest{})
+	require.Equal(t, codes.Unavailable, grpcutils.UnwrapCode(err))
+	require.Len(t, flaky.ids, 1)
+	require.Less(t, int64(time.Since(start)), int64(time.Second))
+}
+
+func TestRetryClient_Canceled(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	flaky := &flakyClient{errs: []error{unavailable(), unavailable()}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(backoff, cancel)
+
+	client := newRetryClient(flaky, retry.WithBackoff(time.Hour, time.Hour, 2))
+
+	_, err := client.Request(ctx, &networkservice.NetworkServiceRequest{})
+	require.Equal(t, codes.Unavailable, grpcutils.UnwrapCode(err))
+	require.Len(t, flaky.ids, 1)
+}
+
+// flakyServer fails the first Request with codes.Unavailable
+type flakyServer struct {
+	ids  []string
+	lock sync.Mutex
+}
+
+func (s *flakyServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	s.lock.Lock()
+	s.ids = append(s.ids, request.GetConnection().GetPath().GetPathSegments()[0].GetId())
+	attempt := len(s.ids)
+	s.lock.Unlock()
+
+	if attempt == 1 {
+		return nil, unavailable()
+	}
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *flakyServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func TestRetryClient_Sandbox(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	domain := sandbox.NewBuilder(t).
+		SetNodesCount(1).
+		SetContext(ctx).
+		SetNSMgrProxySupplier(nil).
+		SetRegistryProxySupplier(nil).
+		Build()
+	defer domain.Cleanup()
+
+	flaky := new(flakyServer)
+	_, err := domain.Nodes[0].NewEndpoint(ctx, &registry.NetworkServiceEndpoint{
+		Name:                "flaky-endpoint",
+		NetworkServiceNames: []string{"my-service"},
+	}, sandbox.GenerateTestToken, flaky)
+	require.NoError(t, err)
+
+	nsc := domain.Nodes[0].NewClient(ctx, sandbox.GenerateTestToken, retry.NewClient(retry.WithBackoff(backoff, 2*backoff, 2)))
+
+	conn, err := nsc.Request(ctx, &networkservice.NetworkServiceRequest{
+		MechanismPreferences: []*networkservice.Mechanism{
+			{Cls: cls.LOCAL, Type: kernelmech.MECHANISM},
+		},
+		Connection: &networkservice.Connection{
+			Id:             "1",
+			NetworkService: "my-service",
+			Context:        &networkservice.ConnectionContext{},
+		},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, conn)
+
+	require.Equal(t, []string{"1", "1"}, flaky.ids)
+}
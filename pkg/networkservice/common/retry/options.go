@@ -0,0 +1,85 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry
+
+import (
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	defaultMaxAttempts    = 5
+	defaultInitialBackoff = 100 * time.Millisecond
+	defaultMaxBackoff     = 5 * time.Second
+	defaultMultiplier     = 2.0
+)
+
+type retryOptions struct {
+	codes          map[codes.Code]struct{}
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	multiplier     float64
+}
+
+// Option is an option pattern for NewClient
+type Option func(o *retryOptions)
+
+// WithCodes sets the gRPC codes the Request is retried on replacing the default codes.Unavailable
+func WithCodes(retryCodes ...codes.Code) Option {
+	return func(o *retryOptions) {
+		o.codes = make(map[codes.Code]struct{}, len(retryCodes))
+		for _, code := range retryCodes {
+			o.codes[code] = struct{}{}
+		}
+	}
+}
+
+// WithMaxAttempts sets the max count of the Request attempts including the first one, 0 means no limit: the Request
+// is retried until the ctx is done
+func WithMaxAttempts(maxAttempts int) Option {
+	return func(o *retryOptions) {
+		o.maxAttempts = maxAttempts
+	}
+}
+
+// WithBackoff sets the backoff before the first retry and the max backoff, the backoff is multiplied by the
+// multiplier after every retry
+func WithBackoff(initialBackoff, maxBackoff time.Duration, multiplier float64) Option {
+	return func(o *retryOptions) {
+		o.initialBackoff = initialBackoff
+		o.maxBackoff = maxBackoff
+		o.multiplier = multiplier
+	}
+}
+
+func newRetryOptions(options ...Option) *retryOptions {
+	o := &retryOptions{
+		codes: map[codes.Code]struct{}{
+			codes.Unavailable: {},
+		},
+		maxAttempts:    defaultMaxAttempts,
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+		multiplier:     defaultMultiplier,
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+	return o
+}
@@ -1,5 +1,7 @@
 // Copyright (c) 2019-2020 VMware, Inc.
 //
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
 // SPDX-License-Identifier: Apache-2.0
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
@@ -19,9 +21,14 @@
 package roundrobin
 
 import (
+	"context"
 	"sync"
 
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
 	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discover"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/selectendpoint"
 )
 
 type roundRobinSelector struct {
@@ -31,23 +38,24 @@ type roundRobinSelector struct {
 	roundRobin map[string]int
 }
 
+// NewSelector returns a selectendpoint.Selector round robining among the candidates for each Network Service
+func NewSelector() selectendpoint.Selector {
+	return newRoundRobinSelector()
+}
+
 func newRoundRobinSelector() *roundRobinSelector {
 	return &roundRobinSelector{
 		roundRobin: make(map[string]int),
 	}
 }
 
-func (rr *roundRobinSelector) selectEndpoint(ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
-	if rr == nil || len(networkServiceEndpoints) == 0 {
+func (rr *roundRobinSelector) Select(_ context.Context, _ *networkservice.NetworkServiceRequest, candidates *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint {
+	if len(candidates.Endpoints) == 0 {
 		return nil
 	}
 	rr.Lock()
 	defer rr.Unlock()
-	idx := rr.roundRobin[ns.GetName()] % len(networkServiceEndpoints)
-	endpoint := networkServiceEndpoints[idx]
-	if endpoint == nil {
-		return nil
-	}
-	rr.roundRobin[ns.GetName()] = rr.roundRobin[ns.GetName()] + 1
-	return endpoint
+	idx := rr.roundRobin[candidates.NetworkService.GetName()] % len(candidates.Endpoints)
+	rr.roundRobin[candidates.NetworkService.GetName()]++
+	return append(append([]*registry.NetworkServiceEndpoint(nil), candidates.Endpoints[idx:]...), candidates.Endpoints[:idx]...)
 }
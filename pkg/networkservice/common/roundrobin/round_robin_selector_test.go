@@ -17,12 +17,15 @@
 package roundrobin
 
 import (
+	"context"
 	"testing"
 
 	"google.golang.org/protobuf/proto"
 
 	"github.com/networkservicemesh/api/pkg/api/registry"
 	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discover"
 )
 
 type args struct {
@@ -229,8 +232,12 @@ func Test_roundRobinSelector_SelectEndpoint(t *testing.T) {
 	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
 	rr := newRoundRobinSelector()
 	for _, tt := range tests {
-		if got := rr.selectEndpoint(tt.args.ns, tt.args.networkServiceEndpoints); !proto.Equal(got, tt.want) {
-			t.Errorf("%s: roundRobinSelector.selectEndpoint() = %v, want %v", tt.name, got, tt.want)
+		got := rr.Select(context.Background(), nil, &discover.NetworkServiceCandidates{
+			NetworkService: tt.args.ns,
+			Endpoints:      tt.args.networkServiceEndpoints,
+		})
+		if len(got) != len(tt.args.networkServiceEndpoints) || !proto.Equal(got[0], tt.want) {
+			t.Errorf("%s: roundRobinSelector.Select() = %v, want %v first", tt.name, got, tt.want)
 		}
 	}
 }
@@ -0,0 +1,168 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selectendpoint
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discover"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/spiffejwt"
+)
+
+type affinityKey struct {
+	identity       string
+	networkService string
+}
+
+type affinitySession struct {
+	nseName     string
+	lastSeen    time.Time
+	connections map[string]struct{}
+}
+
+type affinitySelector struct {
+	clock         clock.Clock
+	identityLabel string
+	window        time.Duration
+
+	lock        sync.Mutex
+	sessions    map[affinityKey]*affinitySession
+	connections map[string]affinityKey // connection ID -> session key
+}
+
+// NewAffinitySelector returns a Selector keeping the client on the same endpoint across the reselections. The client
+// identity is taken from the request label with the identityLabel key, or from the spiffe ID of the client token, or
+// from the connection ID if nothing else is set. The endpoint serving the client identity for the Network Service is
+// preferred while it has been seen alive within the window, even if the client has been temporarily moved to another
+// endpoint. Otherwise the candidates are ordered by the rendezvous hash of the client identity.
+func NewAffinitySelector(ctx context.Context, identityLabel string, window time.Duration) Selector {
+	return &affinitySelector{
+		clock:         clock.FromContext(ctx),
+		identityLabel: identityLabel,
+		window:        window,
+		sessions:      make(map[affinityKey]*affinitySession),
+		connections:   make(map[string]affinityKey),
+	}
+}
+
+func (s *affinitySelector) Select(_ context.Context, request *networkservice.NetworkServiceRequest, candidates *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint {
+	key := s.key(request.GetConnection())
+	endpoints := rendezvousOrder(key.identity, candidates.Endpoints)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	session, ok := s.sessions[key]
+	if !ok || !s.alive(session) {
+		return endpoints
+	}
+	for i, endpoint := range endpoints {
+		if endpoint.Name == session.nseName {
+			copy(endpoints[1:i+1], endpoints[:i])
+			endpoints[0] = endpoint
+			break
+		}
+	}
+	return endpoints
+}
+
+func (s *affinitySelector) Connected(conn *networkservice.Connection) {
+	key := s.key(conn)
+	nseName := conn.GetNetworkServiceEndpointName()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	session, ok := s.sessions[key]
+	if !ok {
+		session = &affinitySession{
+			connections: make(map[string]struct{}),
+		}
+		s.sessions[key] = session
+	}
+	if !ok || session.nseName == nseName || !s.alive(session) {
+		session.nseName = nseName
+		session.lastSeen = s.clock.Now()
+	}
+
+	if oldKey, ok := s.connections[conn.GetId()]; ok && oldKey != key {
+		s.release(oldKey, conn.GetId())
+	}
+	s.connections[conn.GetId()] = key
+	session.connections[conn.GetId()] = struct{}{}
+}
+
+func (s *affinitySelector) Closed(conn *networkservice.Connection) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key, ok := s.connections[conn.GetId()]
+	if !ok {
+		return
+	}
+	delete(s.connections, conn.GetId())
+
+	if session := s.sessions[key]; session.nseName == conn.GetNetworkServiceEndpointName() {
+		session.lastSeen = s.clock.Now()
+	}
+	s.release(key, conn.GetId())
+}
+
+func (s *affinitySelector) key(conn *networkservice.Connection) affinityKey {
+	key := affinityKey{
+		networkService: conn.GetNetworkService(),
+	}
+	if identity, ok := conn.GetLabels()[s.identityLabel]; ok && s.identityLabel != "" {
+		key.identity = identity
+	} else if pathSegments := conn.GetPath().GetPathSegments(); len(pathSegments) > 0 {
+		if spiffeID, err := spiffejwt.SpiffeIDFromToken(pathSegments[0].GetToken()); err == nil {
+			key.identity = spiffeID.String()
+		}
+	}
+	if key.identity == "" {
+		key.identity = conn.GetId()
+	}
+	return key
+}
+
+func (s *affinitySelector) alive(session *affinitySession) bool {
+	return s.clock.Since(session.lastSeen) < s.window
+}
+
+// release removes the connection from the session and schedules the session cleanup once the session has no
+// connections and the window has passed
+func (s *affinitySelector) release(key affinityKey, connID string) {
+	session := s.sessions[key]
+	delete(session.connections, connID)
+	if len(session.connections) > 0 {
+		return
+	}
+	s.clock.AfterFunc(s.window, func() {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		if s.sessions[key] == session && len(session.connections) == 0 && !s.alive(session) {
+			delete(s.sessions, key)
+		}
+	})
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selectendpoint
+
+import (
+	"context"
+	"hash/fnv"
+	"sort"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discover"
+)
+
+// NewConsistentHashSelector returns a Selector ordering the candidates by the rendezvous hash of the request label
+// with the hashLabel key. Requests with the same label value go to the same endpoint while it is present in the
+// candidates, removing an endpoint reshuffles only the requests going to it. If the request has no such label,
+// connection ID is used instead.
+func NewConsistentHashSelector(hashLabel string) Selector {
+	return SelectorFunc(func(_ context.Context, request *networkservice.NetworkServiceRequest, candidates *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint {
+		key, ok := request.GetConnection().GetLabels()[hashLabel]
+		if !ok {
+			key = request.GetConnection().GetId()
+		}
+		return rendezvousOrder(key, candidates.Endpoints)
+	})
+}
+
+func rendezvousOrder(key string, candidates []*registry.NetworkServiceEndpoint) []*registry.NetworkServiceEndpoint {
+	scores := make(map[string]uint64, len(candidates))
+	for _, endpoint := range candidates {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(key))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(endpoint.Name))
+		scores[endpoint.Name] = h.Sum64()
+	}
+
+	endpoints := append([]*registry.NetworkServiceEndpoint(nil), candidates...)
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		return scores[endpoints[i].Name] > scores[endpoints[j].Name]
+	})
+	return endpoints
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selectendpoint
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discover"
+)
+
+type leastConnectionsSelector struct {
+	lock        sync.Mutex
+	connections map[string]string // connection ID -> endpoint name
+	active      map[string]int    // endpoint name -> active connections count
+}
+
+// NewLeastConnectionsSelector returns a Selector ordering the candidates by the count of the active connections
+// established through them
+func NewLeastConnectionsSelector() Selector {
+	return &leastConnectionsSelector{
+		connections: make(map[string]string),
+		active:      make(map[string]int),
+	}
+}
+
+func (s *leastConnectionsSelector) Select(_ context.Context, _ *networkservice.NetworkServiceRequest, candidates *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint {
+	endpoints := append([]*registry.NetworkServiceEndpoint(nil), candidates.Endpoints...)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		return s.active[endpoints[i].Name] < s.active[endpoints[j].Name]
+	})
+	return endpoints
+}
+
+func (s *leastConnectionsSelector) Connected(conn *networkservice.Connection) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	nseName := conn.GetNetworkServiceEndpointName()
+	if oldNSEName, ok := s.connections[conn.GetId()]; ok {
+		if oldNSEName == nseName {
+			return
+		}
+		s.release(oldNSEName)
+	}
+	s.connections[conn.GetId()] = nseName
+	s.active[nseName]++
+}
+
+func (s *leastConnectionsSelector) Closed(conn *networkservice.Connection) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if nseName, ok := s.connections[conn.GetId()]; ok {
+		delete(s.connections, conn.GetId())
+		s.release(nseName)
+	}
+}
+
+func (s *leastConnectionsSelector) release(nseName string) {
+	if s.active[nseName]--; s.active[nseName] <= 0 {
+		delete(s.active, nseName)
+	}
+}
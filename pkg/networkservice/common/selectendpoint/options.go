@@ -0,0 +1,40 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selectendpoint
+
+// Option is an option pattern for NewServer
+type Option func(s *selectEndpointServer)
+
+// WithSelector sets the default Selector used for the Network Services with no specific Selector set
+func WithSelector(selector Selector) Option {
+	if selector == nil {
+		panic("selector cannot be nil")
+	}
+	return func(s *selectEndpointServer) {
+		s.defaultSelector = selector
+	}
+}
+
+// WithNetworkServiceSelector sets Selector used for the networkService
+func WithNetworkServiceSelector(networkService string, selector Selector) Option {
+	if selector == nil {
+		panic("selector cannot be nil")
+	}
+	return func(s *selectEndpointServer) {
+		s.selectors[networkService] = selector
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selectendpoint
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discover"
+)
+
+// Selector orders the candidates provided by discover.Candidates(ctx) by preference. The first endpoint in the
+// result is tried first, the next one is tried only if all the previous have failed. Endpoints missing in the
+// result are not tried at all.
+type Selector interface {
+	Select(ctx context.Context, request *networkservice.NetworkServiceRequest, candidates *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint
+}
+
+// SelectorFunc is a function adapter for the Selector
+type SelectorFunc func(ctx context.Context, request *networkservice.NetworkServiceRequest, candidates *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint
+
+// Select calls f(ctx, request, candidates)
+func (f SelectorFunc) Select(ctx context.Context, request *networkservice.NetworkServiceRequest, candidates *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint {
+	return f(ctx, request, candidates)
+}
+
+// ConnectionObserver is an optional interface for the Selector to be notified about the connections established
+// and closed through the selected endpoints. Connected can be called multiple times for the same connection on
+// refresh.
+type ConnectionObserver interface {
+	Connected(conn *networkservice.Connection)
+	Closed(conn *networkservice.Connection)
+}
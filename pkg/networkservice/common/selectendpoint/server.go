@@ -0,0 +1,115 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package selectendpoint provides a networkservice chain element that selects an endpoint among the candidates
+// provided by discover.Candidates(ctx) using a pluggable Selector
+package selectendpoint
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discover"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clienturlctx"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type selectEndpointServer struct {
+	defaultSelector Selector
+	selectors       map[string]Selector
+}
+
+// NewServer - provides a NetworkServiceServer chain element that selects an endpoint among candidates provided by
+// discover.Candidates(ctx) in the context. If no Selector is set with the options, NewRandomSelector() is used.
+func NewServer(options ...Option) networkservice.NetworkServiceServer {
+	s := &selectEndpointServer{
+		defaultSelector: NewRandomSelector(),
+		selectors:       make(map[string]Selector),
+	}
+	for _, opt := range options {
+		opt(s)
+	}
+	return s
+}
+
+func (s *selectEndpointServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	selector := s.selector(request.GetConnection().GetNetworkService())
+
+	if clienturlctx.ClientURL(ctx) != nil {
+		conn, err := next.Server(ctx).Request(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		connected(selector, conn)
+		return conn, nil
+	}
+
+	candidates := discover.Candidates(ctx)
+	if candidates == nil {
+		return nil, errors.Errorf("no candidates provided for the Network Service: %v", request.GetConnection().GetNetworkService())
+	}
+
+	endpoints := selector.Select(ctx, request, candidates)
+	if len(endpoints) == 0 {
+		return nil, errors.Errorf("failed to find endpoint for Network Service: %v %v", candidates.NetworkService, candidates.Endpoints)
+	}
+
+	logger := log.FromContext(ctx).WithField("selectEndpointServer", "Request")
+	for _, endpoint := range endpoints {
+		u, err := url.Parse(endpoint.Url)
+		if err != nil {
+			logger.Errorf("failed to parse endpoint URL: %s %s", endpoint.Url, err.Error())
+			continue
+		}
+		request.GetConnection().NetworkServiceEndpointName = endpoint.Name
+		conn, err := next.Server(ctx).Request(clienturlctx.WithClientURL(ctx, u), request)
+		if err == nil {
+			connected(selector, conn)
+			return conn, nil
+		}
+		logger.Warnf("endpoint failed: %s %s", endpoint.Name, err.Error())
+	}
+	return nil, errors.Errorf("all candidates %#v fail", candidates)
+}
+
+func (s *selectEndpointServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	if clienturlctx.ClientURL(ctx) == nil {
+		return nil, errors.Errorf("passed incorrect connection: %+v", conn)
+	}
+	if observer, ok := s.selector(conn.GetNetworkService()).(ConnectionObserver); ok {
+		observer.Closed(conn)
+	}
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func (s *selectEndpointServer) selector(networkService string) Selector {
+	if selector, ok := s.selectors[networkService]; ok {
+		return selector
+	}
+	return s.defaultSelector
+}
+
+func connected(selector Selector, conn *networkservice.Connection) {
+	if observer, ok := selector.(ConnectionObserver); ok {
+		observer.Connected(conn)
+	}
+}
@@ -0,0 +1,365 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selectendpoint_test
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discover"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/selectendpoint"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clientinfo"
+	"github.com/networkservicemesh/sdk/pkg/tools/clienturlctx"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+)
+
+const (
+	nsName      = "ns"
+	weightLabel = "weight"
+	hashLabel   = "app"
+	idLabel     = "client"
+)
+
+type recordServer struct {
+	failed   map[string]bool
+	selected []string
+}
+
+func (s *recordServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	u := clienturlctx.ClientURL(ctx)
+	s.selected = append(s.selected, u.Host)
+	if s.failed[u.Host] {
+		return nil, errors.Errorf("endpoint failed: %s", u.Host)
+	}
+	return request.GetConnection(), nil
+}
+
+func (s *recordServer) Close(_ context.Context, _ *networkservice.Connection) (*empty.Empty, error) {
+	return new(empty.Empty), nil
+}
+
+func testCandidates(weights ...string) *discover.NetworkServiceCandidates {
+	candidates := &discover.NetworkServiceCandidates{
+		NetworkService: &registry.NetworkService{Name: nsName},
+	}
+	for i, weight := range weights {
+		candidates.Endpoints = append(candidates.Endpoints, &registry.NetworkServiceEndpoint{
+			Name: fmt.Sprintf("nse-%d", i),
+			Url:  fmt.Sprintf("tcp://nse-%d", i),
+			NetworkServiceLabels: map[string]*registry.NetworkServiceLabels{
+				nsName: {
+					Labels: map[string]string{weightLabel: weight},
+				},
+			},
+		})
+	}
+	return candidates
+}
+
+func testRequest(id string, labels map[string]string) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id:             id,
+			NetworkService: nsName,
+			Labels:         labels,
+		},
+	}
+}
+
+func TestSelectEndpointServer_NextCandidateOnFailure(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	candidates := testCandidates("1", "1", "1")
+	rs := &recordServer{
+		failed: map[string]bool{"nse-0": true, "nse-1": true},
+	}
+	server := next.NewNetworkServiceServer(
+		selectendpoint.NewServer(selectendpoint.WithSelector(selectendpoint.SelectorFunc(
+			func(_ context.Context, _ *networkservice.NetworkServiceRequest, c *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint {
+				return c.Endpoints
+			}),
+		)),
+		rs,
+	)
+
+	ctx := discover.WithCandidates(context.Background(), candidates.Endpoints, candidates.NetworkService)
+	conn, err := server.Request(ctx, testRequest("id", nil))
+	require.NoError(t, err)
+	require.Equal(t, "nse-2", conn.GetNetworkServiceEndpointName())
+	require.Equal(t, []string{"nse-0", "nse-1", "nse-2"}, rs.selected)
+
+	rs.failed["nse-2"] = true
+	_, err = server.Request(ctx, testRequest("id", nil))
+	require.Error(t, err)
+}
+
+func TestSelectEndpointServer_NetworkServiceSelector(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	candidates := testCandidates("1", "1")
+	reverse := selectendpoint.SelectorFunc(
+		func(_ context.Context, _ *networkservice.NetworkServiceRequest, c *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint {
+			return []*registry.NetworkServiceEndpoint{c.Endpoints[1], c.Endpoints[0]}
+		})
+
+	rs := new(recordServer)
+	server := next.NewNetworkServiceServer(
+		selectendpoint.NewServer(selectendpoint.WithNetworkServiceSelector(nsName, reverse)),
+		rs,
+	)
+
+	ctx := discover.WithCandidates(context.Background(), candidates.Endpoints, candidates.NetworkService)
+	for i := 0; i < 10; i++ {
+		conn, err := server.Request(ctx, testRequest("id", nil))
+		require.NoError(t, err)
+		require.Equal(t, "nse-1", conn.GetNetworkServiceEndpointName())
+	}
+}
+
+func TestSelectEndpointServer_LeastConnections(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	candidates := testCandidates("1", "1", "1")
+	server := next.NewNetworkServiceServer(
+		selectendpoint.NewServer(selectendpoint.WithSelector(selectendpoint.NewLeastConnectionsSelector())),
+		new(recordServer),
+	)
+
+	ctx := discover.WithCandidates(context.Background(), candidates.Endpoints, candidates.NetworkService)
+
+	var conns []*networkservice.Connection
+	selected := make(map[string]int)
+	for i := 0; i < 6; i++ {
+		conn, err := server.Request(ctx, testRequest(fmt.Sprint(i), nil))
+		require.NoError(t, err)
+		conns = append(conns, conn.Clone())
+		selected[conn.GetNetworkServiceEndpointName()]++
+	}
+	require.Equal(t, map[string]int{"nse-0": 2, "nse-1": 2, "nse-2": 2}, selected)
+
+	// Refresh shouldn't be counted twice
+	refreshCtx := clienturlctx.WithClientURL(context.Background(), &url.URL{Scheme: "tcp", Host: conns[0].GetNetworkServiceEndpointName()})
+	_, err := server.Request(refreshCtx, &networkservice.NetworkServiceRequest{Connection: conns[0].Clone()})
+	require.NoError(t, err)
+
+	// Close both connections on the conns[1] endpoint
+	for _, conn := range conns {
+		if conn.GetNetworkServiceEndpointName() == conns[1].GetNetworkServiceEndpointName() {
+			closeCtx := clienturlctx.WithClientURL(context.Background(), &url.URL{Scheme: "tcp", Host: conn.GetNetworkServiceEndpointName()})
+			_, err = server.Close(closeCtx, conn)
+			require.NoError(t, err)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		conn, err := server.Request(ctx, testRequest(fmt.Sprint("new-", i), nil))
+		require.NoError(t, err)
+		require.Equal(t, conns[1].GetNetworkServiceEndpointName(), conn.GetNetworkServiceEndpointName())
+	}
+}
+
+func TestSelectEndpointServer_Weighted(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	candidates := testCandidates("0", "1", "9")
+	selector := selectendpoint.NewWeightedSelector(weightLabel)
+
+	selected := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		endpoints := selector.Select(context.Background(), testRequest("id", nil), candidates)
+		require.Len(t, endpoints, 3)
+		require.Equal(t, "nse-0", endpoints[2].Name)
+		selected[endpoints[0].Name]++
+	}
+	require.Greater(t, selected["nse-2"], 3*selected["nse-1"])
+}
+
+func TestSelectEndpointServer_ConsistentHash(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	candidates := testCandidates("1", "1", "1", "1", "1")
+	selector := selectendpoint.NewConsistentHashSelector(hashLabel)
+
+	first := make(map[string]string)
+	for i := 0; i < 20; i++ {
+		app := fmt.Sprint("app-", i)
+		endpoints := selector.Select(context.Background(), testRequest(fmt.Sprint(i), map[string]string{hashLabel: app}), candidates)
+		require.Len(t, endpoints, 5)
+		first[app] = endpoints[0].Name
+
+		endpoints = selector.Select(context.Background(), testRequest("other", map[string]string{hashLabel: app}), candidates)
+		require.Equal(t, first[app], endpoints[0].Name)
+	}
+
+	// Removing an endpoint should move only the requests going to it
+	removed := candidates.Endpoints[0].Name
+	candidates.Endpoints = candidates.Endpoints[1:]
+	for app, name := range first {
+		endpoints := selector.Select(context.Background(), testRequest("id", map[string]string{hashLabel: app}), candidates)
+		if name != removed {
+			require.Equal(t, name, endpoints[0].Name)
+		}
+	}
+}
+
+func TestSelectEndpointServer_Topology(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	candidates := testCandidates("1", "1", "1", "1")
+	for i, topology := range []map[string]string{
+		{clientinfo.ClusterNameLabel: "cluster-2", clientinfo.NodeNameLabel: "node-1"},
+		{clientinfo.ClusterNameLabel: "cluster-1", clientinfo.NodeNameLabel: "node-2"},
+		{clientinfo.ClusterNameLabel: "cluster-1", clientinfo.NodeNameLabel: "node-1"},
+		{},
+	} {
+		for k, v := range topology {
+			candidates.Endpoints[i].NetworkServiceLabels[nsName].Labels[k] = v
+		}
+	}
+
+	rs := &recordServer{
+		failed: make(map[string]bool),
+	}
+	server := next.NewNetworkServiceServer(
+		selectendpoint.NewServer(selectendpoint.WithSelector(
+			selectendpoint.NewTopologySelector(selectendpoint.NewRandomSelector()),
+		)),
+		rs,
+	)
+
+	ctx := discover.WithCandidates(context.Background(), candidates.Endpoints, candidates.NetworkService)
+	request := testRequest("id", map[string]string{
+		clientinfo.ClusterNameLabel: "cluster-1",
+		clientinfo.NodeNameLabel:    "node-1",
+	})
+
+	for _, expected := range []string{"nse-2", "nse-1"} {
+		for i := 0; i < 10; i++ {
+			conn, err := server.Request(ctx, request.Clone())
+			require.NoError(t, err)
+			require.Equal(t, expected, conn.GetNetworkServiceEndpointName())
+		}
+		rs.failed[expected] = true
+	}
+
+	// Endpoints from the other cluster and with unknown topology have the same distance
+	conn, err := server.Request(ctx, request.Clone())
+	require.NoError(t, err)
+	require.Contains(t, []string{"nse-0", "nse-3"}, conn.GetNetworkServiceEndpointName())
+}
+
+func TestSelectEndpointServer_Affinity(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	clockMock := clockmock.NewMock()
+	ctx := clock.WithClock(context.Background(), clockMock)
+
+	candidates := testCandidates("1", "1", "1")
+	rs := &recordServer{
+		failed: make(map[string]bool),
+	}
+	server := next.NewNetworkServiceServer(
+		selectendpoint.NewServer(selectendpoint.WithSelector(
+			selectendpoint.NewAffinitySelector(ctx, idLabel, time.Minute),
+		)),
+		rs,
+	)
+
+	ctx = discover.WithCandidates(ctx, candidates.Endpoints, candidates.NetworkService)
+	request := testRequest("id", map[string]string{idLabel: "client-1"})
+
+	conn, err := server.Request(ctx, request.Clone())
+	require.NoError(t, err)
+	home := conn.GetNetworkServiceEndpointName()
+
+	// Same client identity goes to the same endpoint
+	conn, err = server.Request(ctx, testRequest("other-id", map[string]string{idLabel: "client-1"}))
+	require.NoError(t, err)
+	require.Equal(t, home, conn.GetNetworkServiceEndpointName())
+
+	// Home endpoint dies, client is consistently moved to another one
+	rs.failed[home] = true
+	conn, err = server.Request(ctx, request.Clone())
+	require.NoError(t, err)
+	fallback := conn.GetNetworkServiceEndpointName()
+	require.NotEqual(t, home, fallback)
+	for i := 0; i < 5; i++ {
+		conn, err = server.Request(ctx, request.Clone())
+		require.NoError(t, err)
+		require.Equal(t, fallback, conn.GetNetworkServiceEndpointName())
+	}
+
+	// Home endpoint comes back within the window
+	clockMock.Add(time.Minute / 2)
+	rs.failed[home] = false
+	conn, err = server.Request(ctx, request.Clone())
+	require.NoError(t, err)
+	require.Equal(t, home, conn.GetNetworkServiceEndpointName())
+
+	// Home endpoint comes back after the window, client stays on the new endpoint
+	rs.failed[home] = true
+	_, err = server.Request(ctx, request.Clone())
+	require.NoError(t, err)
+	clockMock.Add(time.Minute * 2)
+	_, err = server.Request(ctx, request.Clone())
+	require.NoError(t, err)
+	rs.failed[home] = false
+	conn, err = server.Request(ctx, request.Clone())
+	require.NoError(t, err)
+	require.Equal(t, fallback, conn.GetNetworkServiceEndpointName())
+}
+
+func TestSelectEndpointServer_AffinitySpiffeID(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	candidates := testCandidates("1", "1", "1", "1", "1")
+	selector := selectendpoint.NewAffinitySelector(context.Background(), idLabel, time.Minute)
+
+	tokenRequest := func(id, spiffeID string) *networkservice.NetworkServiceRequest {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.StandardClaims{Subject: spiffeID}).SignedString([]byte("key"))
+		require.NoError(t, err)
+
+		request := testRequest(id, nil)
+		request.Connection.Path = &networkservice.Path{
+			PathSegments: []*networkservice.PathSegment{{Token: token}},
+		}
+		return request
+	}
+
+	for i := 0; i < 10; i++ {
+		spiffeID := fmt.Sprintf("spiffe://test.com/client-%d", i)
+		expected := selector.Select(context.Background(), tokenRequest("id-1", spiffeID), candidates)[0].Name
+		for j := 0; j < 5; j++ {
+			actual := selector.Select(context.Background(), tokenRequest(fmt.Sprint("id-", j), spiffeID), candidates)[0].Name
+			require.Equal(t, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selectendpoint
+
+import (
+	"context"
+	"sort"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discover"
+	"github.com/networkservicemesh/sdk/pkg/tools/clientinfo"
+)
+
+const (
+	sameNodeDistance = iota
+	sameClusterDistance
+	unknownDistance
+)
+
+type topologySelector struct {
+	selector Selector
+}
+
+// NewTopologySelector returns a Selector ordering the candidates by the topology distance to the client: endpoints on
+// the same node go first, then endpoints in the same cluster, then all the others. Endpoints with the same distance
+// are ordered by the selector. The topology is taken from the clientinfo labels of the request and the labels
+// registered by the endpoints for the requested Network Service.
+func NewTopologySelector(selector Selector) Selector {
+	return &topologySelector{
+		selector: selector,
+	}
+}
+
+func (s *topologySelector) Select(ctx context.Context, request *networkservice.NetworkServiceRequest, candidates *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint {
+	endpoints := s.selector.Select(ctx, request, candidates)
+
+	clientLabels := request.GetConnection().GetLabels()
+	distances := make(map[string]int, len(endpoints))
+	for _, endpoint := range endpoints {
+		endpointLabels := endpoint.GetNetworkServiceLabels()[candidates.NetworkService.GetName()].GetLabels()
+		distances[endpoint.Name] = topologyDistance(clientLabels, endpointLabels)
+	}
+
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		return distances[endpoints[i].Name] < distances[endpoints[j].Name]
+	})
+	return endpoints
+}
+
+func (s *topologySelector) Connected(conn *networkservice.Connection) {
+	if observer, ok := s.selector.(ConnectionObserver); ok {
+		observer.Connected(conn)
+	}
+}
+
+func (s *topologySelector) Closed(conn *networkservice.Connection) {
+	if observer, ok := s.selector.(ConnectionObserver); ok {
+		observer.Closed(conn)
+	}
+}
+
+func topologyDistance(clientLabels, endpointLabels map[string]string) int {
+	clientCluster, endpointCluster := clientLabels[clientinfo.ClusterNameLabel], endpointLabels[clientinfo.ClusterNameLabel]
+	if clientCluster != endpointCluster {
+		return unknownDistance
+	}
+	if clientNode := clientLabels[clientinfo.NodeNameLabel]; clientNode != "" && clientNode == endpointLabels[clientinfo.NodeNameLabel] {
+		return sameNodeDistance
+	}
+	if clientCluster != "" {
+		return sameClusterDistance
+	}
+	return unknownDistance
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selectendpoint
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/discover"
+)
+
+const defaultWeight = 1
+
+// NewWeightedSelector returns a Selector ordering the candidates randomly with the probability of each endpoint to
+// go first proportional to its weight. The weight is taken from the endpoint Network Service label with the
+// weightLabel key, endpoints with no such label or with invalid label value have weight = 1. Endpoints with weight = 0
+// go last.
+func NewWeightedSelector(weightLabel string) Selector {
+	return SelectorFunc(func(_ context.Context, _ *networkservice.NetworkServiceRequest, candidates *discover.NetworkServiceCandidates) []*registry.NetworkServiceEndpoint {
+		type weightedEndpoint struct {
+			endpoint *registry.NetworkServiceEndpoint
+			key      float64
+		}
+
+		weightedEndpoints := make([]*weightedEndpoint, 0, len(candidates.Endpoints))
+		for _, endpoint := range candidates.Endpoints {
+			weight := endpointWeight(endpoint, candidates.NetworkService.GetName(), weightLabel)
+			// Efraimidis-Spirakis weighted random sampling: the endpoint with the least -ln(U)/w goes first.
+			key := math.Inf(1)
+			if weight > 0 {
+				key = -math.Log(1-rand.Float64()) / weight
+			}
+			weightedEndpoints = append(weightedEndpoints, &weightedEndpoint{
+				endpoint: endpoint,
+				key:      key,
+			})
+		}
+
+		sort.SliceStable(weightedEndpoints, func(i, j int) bool {
+			return weightedEndpoints[i].key < weightedEndpoints[j].key
+		})
+
+		endpoints := make([]*registry.NetworkServiceEndpoint, 0, len(weightedEndpoints))
+		for _, we := range weightedEndpoints {
+			endpoints = append(endpoints, we.endpoint)
+		}
+		return endpoints
+	})
+}
+
+func endpointWeight(endpoint *registry.NetworkServiceEndpoint, networkService, weightLabel string) float64 {
+	value, ok := endpoint.GetNetworkServiceLabels()[networkService].GetLabels()[weightLabel]
+	if !ok {
+		return defaultWeight
+	}
+	weight, err := strconv.ParseFloat(value, 64)
+	if err != nil || weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+		return defaultWeight
+	}
+	return weight
+}
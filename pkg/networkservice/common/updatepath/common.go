@@ -100,3 +100,19 @@ func updatePath(conn *networkservice.Connection, segmentName string) (*networkse
 
 	return conn, conn.Path.Index - 1, nil
 }
+
+// IsReturnPass returns true if the current path segment owner has been already passed before, e.g. NSMgr receives the
+// same connection back from the Forwarder on the way to the NSE
+func IsReturnPass(path *networkservice.Path) bool {
+	segments := path.GetPathSegments()
+	index := int(path.GetIndex())
+	if index >= len(segments) {
+		return false
+	}
+	for _, segment := range segments[:index] {
+		if segment.GetName() == segments[index].GetName() {
+			return true
+		}
+	}
+	return false
+}
@@ -261,3 +261,21 @@ func TestUpdatePath(t *testing.T) {
 		})
 	}
 }
+
+func TestIsReturnPass(t *testing.T) {
+	segments := []*networkservice.PathSegment{
+		{Name: "nsc", Id: "1"},
+		{Name: "nsmgr", Id: "2"},
+		{Name: "forwarder", Id: "3"},
+		{Name: "nsmgr", Id: "4"},
+		{Name: "nse", Id: "5"},
+	}
+	for index, expected := range []bool{false, false, false, true, false} {
+		require.Equal(t, expected, updatepath.IsReturnPass(&networkservice.Path{
+			Index:        uint32(index),
+			PathSegments: segments,
+		}), index)
+	}
+	require.False(t, updatepath.IsReturnPass(&networkservice.Path{Index: 5, PathSegments: segments}))
+	require.False(t, updatepath.IsReturnPass(nil))
+}
@@ -22,10 +22,22 @@ import (
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/profile"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/trace"
+	profiletools "github.com/networkservicemesh/sdk/pkg/tools/profile"
 )
 
-// NewNetworkServiceClient - chains together a list of networkservice.NetworkServiceClient with tracing
+// NewNetworkServiceClient - chains together a list of networkservice.NetworkServiceClient with tracing and profiling
+// if enabled
 func NewNetworkServiceClient(clients ...networkservice.NetworkServiceClient) networkservice.NetworkServiceClient {
-	return next.NewWrappedNetworkServiceClient(trace.NewNetworkServiceClient, clients...)
+	return next.NewWrappedNetworkServiceClient(clientWrapper(), clients...)
+}
+
+func clientWrapper() next.ClientWrapper {
+	if !profiletools.IsEnabled() {
+		return trace.NewNetworkServiceClient
+	}
+	return func(client networkservice.NetworkServiceClient) networkservice.NetworkServiceClient {
+		return trace.NewNetworkServiceClient(profile.NewNetworkServiceClient(client))
+	}
 }
@@ -25,18 +25,30 @@ import (
 
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/setlogoption"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/profile"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/trace"
+	profiletools "github.com/networkservicemesh/sdk/pkg/tools/profile"
 )
 
-// NewNetworkServiceServer - chains together a list of networkservice.Servers with tracing
+// NewNetworkServiceServer - chains together a list of networkservice.Servers with tracing and profiling if enabled
 func NewNetworkServiceServer(servers ...networkservice.NetworkServiceServer) networkservice.NetworkServiceServer {
-	return next.NewWrappedNetworkServiceServer(trace.NewNetworkServiceServer, servers...)
+	return next.NewWrappedNetworkServiceServer(serverWrapper(), servers...)
 }
 
-// NewNamedNetworkServiceServer - chains together a list of networkservice.Servers with tracing and name log option
+// NewNamedNetworkServiceServer - chains together a list of networkservice.Servers with tracing, profiling if enabled
+// and name log option
 func NewNamedNetworkServiceServer(name string, servers ...networkservice.NetworkServiceServer) networkservice.NetworkServiceServer {
 	return next.NewNetworkServiceServer(
 		setlogoption.NewServer(map[string]string{"name": name}),
-		next.NewWrappedNetworkServiceServer(trace.NewNetworkServiceServer, servers...),
+		next.NewWrappedNetworkServiceServer(serverWrapper(), servers...),
 	)
 }
+
+func serverWrapper() next.ServerWrapper {
+	if !profiletools.IsEnabled() {
+		return trace.NewNetworkServiceServer
+	}
+	return func(server networkservice.NetworkServiceServer) networkservice.NetworkServiceServer {
+		return trace.NewNetworkServiceServer(profile.NewNetworkServiceServer(server))
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package profile
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+)
+
+type beginProfileClient struct {
+	*element
+	profiled networkservice.NetworkServiceClient
+}
+
+type endProfileClient struct {
+	*element
+}
+
+// NewNetworkServiceClient - wraps profiling around the supplied profiled, latencies are recorded in the
+// profile.Default()
+func NewNetworkServiceClient(profiled networkservice.NetworkServiceClient) networkservice.NetworkServiceClient {
+	e := newElement(profiled)
+	return next.NewNetworkServiceClient(
+		&beginProfileClient{element: e, profiled: profiled},
+		&endProfileClient{element: e},
+	)
+}
+
+func (c *beginProfileClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	ctx, finish := c.withProfile(ctx, requestMethod)
+	defer finish()
+
+	return c.profiled.Request(ctx, request, opts...)
+}
+
+func (c *beginProfileClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	ctx, finish := c.withProfile(ctx, closeMethod)
+	defer finish()
+
+	return c.profiled.Close(ctx, conn, opts...)
+}
+
+func (c *endProfileClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	defer c.measureNext(ctx)()
+
+	return next.Client(ctx).Request(ctx, request, opts...)
+}
+
+func (c *endProfileClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	defer c.measureNext(ctx)()
+
+	return next.Client(ctx).Close(ctx, conn, opts...)
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package profile provides a wrapper recording the latencies of the networkservice.NetworkService{Client, Server}
+// chain elements
+package profile
+
+import (
+	"context"
+	"time"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/profile"
+	"github.com/networkservicemesh/sdk/pkg/tools/typeutils"
+)
+
+const (
+	requestMethod = "Request"
+	closeMethod   = "Close"
+)
+
+// element is a profiled chain element, a pointer to it is also used as a context key to pass the time spent in
+// the next elements from the end to the begin wrapper
+type element struct {
+	name string
+}
+
+type downstream struct {
+	clock   clock.Clock
+	elapsed time.Duration
+}
+
+func newElement(profiled interface{}) *element {
+	return &element{
+		name: typeutils.GetTypeName(profiled),
+	}
+}
+
+// withProfile - starts profiling of the element method, returned func records the time elapsed since the start
+// excluding the time spent in the next elements
+func (e *element) withProfile(ctx context.Context, method string) (context.Context, func()) {
+	clk := clock.FromContext(ctx)
+	start := clk.Now()
+
+	d := &downstream{clock: clk}
+	return context.WithValue(ctx, e, d), func() {
+		profile.Default().Record(e.name, method, clk.Since(start)-d.elapsed)
+	}
+}
+
+// measureNext - starts measuring of the time spent in the next elements, returned func adds it to the element
+// downstream time
+func (e *element) measureNext(ctx context.Context) func() {
+	d, ok := ctx.Value(e).(*downstream)
+	if !ok {
+		return func() {}
+	}
+	start := d.clock.Now()
+	return func() {
+		d.elapsed += d.clock.Since(start)
+	}
+}
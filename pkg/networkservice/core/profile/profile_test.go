@@ -0,0 +1,185 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package profile_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/profile"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+	profiletools "github.com/networkservicemesh/sdk/pkg/tools/profile"
+)
+
+const (
+	requestMethod = "Request"
+	closeMethod   = "Close"
+)
+
+// sleep spends d in the element before and after the next elements
+type sleep struct {
+	clock *clockmock.Mock
+	d     time.Duration
+}
+
+type firstServer struct{ sleep }
+
+type secondServer struct{ sleep }
+
+type firstClient struct{ sleep }
+
+type secondClient struct{ sleep }
+
+func (s *sleep) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	s.clock.Add(s.d)
+	defer s.clock.Add(s.d)
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *sleep) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	s.clock.Add(s.d)
+	defer s.clock.Add(s.d)
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func (c *firstClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	c.clock.Add(c.d)
+	defer c.clock.Add(c.d)
+	return next.Client(ctx).Request(ctx, request, opts...)
+}
+
+func (c *firstClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	c.clock.Add(c.d)
+	defer c.clock.Add(c.d)
+	return next.Client(ctx).Close(ctx, conn, opts...)
+}
+
+func (c *secondClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	c.clock.Add(c.d)
+	defer c.clock.Add(c.d)
+	return next.Client(ctx).Request(ctx, request, opts...)
+}
+
+func (c *secondClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	c.clock.Add(c.d)
+	defer c.clock.Add(c.d)
+	return next.Client(ctx).Close(ctx, conn, opts...)
+}
+
+func findStats(element, method string) *profiletools.Stats {
+	for _, s := range profiletools.Default().Stats() {
+		if strings.HasSuffix(s.Element, "/"+element) && s.Method == method {
+			return s
+		}
+	}
+	return nil
+}
+
+func requireStats(t *testing.T, element, method string, expected time.Duration) {
+	s := findStats(element, method)
+	require.NotNil(t, s, "%s.%s", element, method)
+	require.Equal(t, int64(1), s.Count)
+	require.InEpsilon(t, float64(expected), float64(s.Max), 0.01)
+}
+
+func TestProfileServer(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	profiletools.Enable(true)
+	defer profiletools.Enable(false)
+	profiletools.Default().Reset()
+
+	clockMock := clockmock.NewMock()
+	ctx := clock.WithClock(context.Background(), clockMock)
+
+	server := chain.NewNetworkServiceServer(
+		&firstServer{sleep{clock: clockMock, d: time.Second}},
+		&secondServer{sleep{clock: clockMock, d: 2 * time.Second}},
+	)
+
+	conn, err := server.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "id"},
+	})
+	require.NoError(t, err)
+
+	_, err = server.Close(ctx, conn)
+	require.NoError(t, err)
+
+	requireStats(t, "firstServer", requestMethod, 2*time.Second)
+	requireStats(t, "secondServer", requestMethod, 4*time.Second)
+	requireStats(t, "firstServer", closeMethod, 2*time.Second)
+	requireStats(t, "secondServer", closeMethod, 4*time.Second)
+}
+
+func TestProfileServer_Disabled(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	profiletools.Default().Reset()
+
+	clockMock := clockmock.NewMock()
+	ctx := clock.WithClock(context.Background(), clockMock)
+
+	server := chain.NewNetworkServiceServer(
+		&firstServer{sleep{clock: clockMock, d: time.Second}},
+	)
+
+	_, err := server.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "id"},
+	})
+	require.NoError(t, err)
+
+	require.Nil(t, findStats("firstServer", requestMethod))
+}
+
+func TestProfileClient(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	profiletools.Default().Reset()
+
+	clockMock := clockmock.NewMock()
+	ctx := clock.WithClock(context.Background(), clockMock)
+
+	client := next.NewWrappedNetworkServiceClient(profile.NewNetworkServiceClient,
+		&firstClient{sleep{clock: clockMock, d: time.Second}},
+		&secondClient{sleep{clock: clockMock, d: 3 * time.Second}},
+	)
+
+	conn, err := client.Request(ctx, &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "id"},
+	})
+	require.NoError(t, err)
+
+	_, err = client.Close(ctx, conn)
+	require.NoError(t, err)
+
+	requireStats(t, "firstClient", requestMethod, 2*time.Second)
+	requireStats(t, "secondClient", requestMethod, 6*time.Second)
+	requireStats(t, "firstClient", closeMethod, 2*time.Second)
+	requireStats(t, "secondClient", closeMethod, 6*time.Second)
+}
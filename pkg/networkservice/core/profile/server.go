@@ -0,0 +1,72 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package profile
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+)
+
+type beginProfileServer struct {
+	*element
+	profiled networkservice.NetworkServiceServer
+}
+
+type endProfileServer struct {
+	*element
+}
+
+// NewNetworkServiceServer - wraps profiling around the supplied profiled, latencies are recorded in the
+// profile.Default()
+func NewNetworkServiceServer(profiled networkservice.NetworkServiceServer) networkservice.NetworkServiceServer {
+	e := newElement(profiled)
+	return next.NewNetworkServiceServer(
+		&beginProfileServer{element: e, profiled: profiled},
+		&endProfileServer{element: e},
+	)
+}
+
+func (s *beginProfileServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	ctx, finish := s.withProfile(ctx, requestMethod)
+	defer finish()
+
+	return s.profiled.Request(ctx, request)
+}
+
+func (s *beginProfileServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	ctx, finish := s.withProfile(ctx, closeMethod)
+	defer finish()
+
+	return s.profiled.Close(ctx, conn)
+}
+
+func (s *endProfileServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	defer s.measureNext(ctx)()
+
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *endProfileServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	defer s.measureNext(ctx)()
+
+	return next.Server(ctx).Close(ctx, conn)
+}
@@ -26,41 +26,167 @@ import (
 	"google.golang.org/grpc"
 
 	registryserver "github.com/networkservicemesh/sdk/pkg/registry"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/authorize"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/connect"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/expire"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/filestore"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/null"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/proxy"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/replicate"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/serialize"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/setid"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/validatematches"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/admin"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
 )
 
+type serverOptions struct {
+	dialOptions []grpc.DialOption
+	nsStore     filestore.NetworkServiceRegistryServer
+	nseStore    filestore.NetworkServiceEndpointRegistryServer
+	replicaName string
+	peers       []*url.URL
+	replicate   []replicate.Option
+	withAdmin   bool
+	admin       []admin.Option
+	authorize   []authorize.Option
+}
+
+// Option modifies server option value
+type Option func(o *serverOptions)
+
+// WithDialOptions sets gRPC Dial Options to be passed to GRPC connections
+func WithDialOptions(dialOptions ...grpc.DialOption) Option {
+	return func(o *serverOptions) {
+		o.dialOptions = dialOptions
+	}
+}
+
+// WithFileStore sets file stores persisting the registry state on the local disk. State loaded by the stores is
+// restored to the registry in NewServer.
+func WithFileStore(nsStore filestore.NetworkServiceRegistryServer, nseStore filestore.NetworkServiceEndpointRegistryServer) Option {
+	return func(o *serverOptions) {
+		o.nsStore = nsStore
+		o.nseStore = nseStore
+	}
+}
+
+// WithReplication sets the registry to replicate its state with the peer registries. All the replicas should have
+// unique names.
+func WithReplication(replicaName string, peers []*url.URL, options ...replicate.Option) Option {
+	return func(o *serverOptions) {
+		o.replicaName = replicaName
+		o.peers = peers
+		o.replicate = options
+	}
+}
+
+// WithAdmin enables the admin gRPC service with the options, e.g. the policies authorizing its calls. By default the
+// admin service is not registered.
+func WithAdmin(options ...admin.Option) Option {
+	return func(o *serverOptions) {
+		o.withAdmin = true
+		o.admin = options
+	}
+}
+
+// WithAuthorize enables the authorization of the NS and NSE registry calls with the options, e.g. the policies. By
+// default the calls are not authorized. State restored from the file stores and the admin calls are not checked by it.
+func WithAuthorize(options ...authorize.Option) Option {
+	return func(o *serverOptions) {
+		o.authorize = append([]authorize.Option{}, options...)
+	}
+}
+
 // NewServer creates new registry server based on memory storage
-func NewServer(ctx context.Context, expiryDuration time.Duration, proxyRegistryURL *url.URL, options ...grpc.DialOption) registryserver.Registry {
+// NewServer used to take the gRPC Dial Options as the variadic argument: `NewServer(ctx, expiryDuration, proxyRegistryURL, dialOptions...)`
+// should be changed to `NewServer(ctx, expiryDuration, proxyRegistryURL, WithDialOptions(dialOptions...))`.
+func NewServer(ctx context.Context, expiryDuration time.Duration, proxyRegistryURL *url.URL, options ...Option) registryserver.Registry {
+	opts := new(serverOptions)
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	var nsStore registry.NetworkServiceRegistryServer = null.NewNetworkServiceRegistryServer()
+	if opts.nsStore != nil {
+		nsStore = opts.nsStore
+	}
+	var nseStore registry.NetworkServiceEndpointRegistryServer = null.NewNetworkServiceEndpointRegistryServer()
+	if opts.nseStore != nil {
+		nseStore = opts.nseStore
+	}
+
+	var nsReplicate registry.NetworkServiceRegistryServer = null.NewNetworkServiceRegistryServer()
+	var nseReplicate registry.NetworkServiceEndpointRegistryServer = null.NewNetworkServiceEndpointRegistryServer()
+	if opts.replicaName != "" {
+		replicateOptions := append([]replicate.Option{replicate.WithDialOptions(opts.dialOptions...)}, opts.replicate...)
+		nsReplicate = replicate.NewNetworkServiceRegistryServer(ctx, opts.replicaName, opts.peers, replicateOptions...)
+		nseReplicate = replicate.NewNetworkServiceEndpointRegistryServer(ctx, opts.replicaName, opts.peers, replicateOptions...)
+	}
+
+	nseExpire := expire.NewNetworkServiceEndpointRegistryServer(ctx, expiryDuration)
+	nseMemory := memory.NewNetworkServiceEndpointRegistryServer()
+	nsMemory := memory.NewNetworkServiceRegistryServer()
+
 	nseChain := chain.NewNetworkServiceEndpointRegistryServer(
 		serialize.NewNetworkServiceEndpointRegistryServer(),
-		expire.NewNetworkServiceEndpointRegistryServer(ctx, expiryDuration),
-		memory.NewNetworkServiceEndpointRegistryServer(),
+		nseStore,
+		nseExpire,
+		nseReplicate,
+		nseMemory,
 		setid.NewNetworkServiceEndpointRegistryServer(),
 		proxy.NewNetworkServiceEndpointRegistryServer(proxyRegistryURL),
 		connect.NewNetworkServiceEndpointRegistryServer(ctx, func(ctx context.Context, cc grpc.ClientConnInterface) registry.NetworkServiceEndpointRegistryClient {
 			return chain.NewNetworkServiceEndpointRegistryClient(
 				registry.NewNetworkServiceEndpointRegistryClient(cc),
 			)
-		}, connect.WithClientDialOptions(options...)),
+		}, connect.WithClientDialOptions(opts.dialOptions...)),
 	)
 	nsChain := chain.NewNetworkServiceRegistryServer(
 		serialize.NewNetworkServiceRegistryServer(),
+		validatematches.NewNetworkServiceRegistryServer(),
 		expire.NewNetworkServiceServer(ctx, adapters.NetworkServiceEndpointServerToClient(nseChain)),
-		memory.NewNetworkServiceRegistryServer(),
+		nsStore,
+		nsReplicate,
+		nsMemory,
 		proxy.NewNetworkServiceRegistryServer(proxyRegistryURL),
 		connect.NewNetworkServiceRegistryServer(ctx, func(ctx context.Context, cc grpc.ClientConnInterface) registry.NetworkServiceRegistryClient {
 			return chain.NewNetworkServiceRegistryClient(
 				registry.NewNetworkServiceRegistryClient(cc),
 			)
-		}, connect.WithClientDialOptions(options...)),
+		}, connect.WithClientDialOptions(opts.dialOptions...)),
 	)
 
-	return registryserver.NewServer(nsChain, nseChain)
+	// NSEs are restored first, so the NS expire gets them on the NS registration
+	if opts.nseStore != nil {
+		if err := opts.nseStore.Restore(ctx, nseChain); err != nil {
+			log.FromContext(ctx).Errorf("failed to restore NSEs: %s", err.Error())
+		}
+	}
+	if opts.nsStore != nil {
+		if err := opts.nsStore.Restore(ctx, nsChain); err != nil {
+			log.FromContext(ctx).Errorf("failed to restore NSs: %s", err.Error())
+		}
+	}
+
+	nsServer, nseServer := nsChain, nseChain
+	if opts.authorize != nil {
+		nsServer = chain.NewNetworkServiceRegistryServer(authorize.NewNetworkServiceRegistryServer(opts.authorize...), nsChain)
+		nseServer = chain.NewNetworkServiceEndpointRegistryServer(authorize.NewNetworkServiceEndpointRegistryServer(opts.authorize...), nseChain)
+	}
+
+	if !opts.withAdmin {
+		return registryserver.NewServer(nsServer, nseServer)
+	}
+
+	adminServer := admin.NewServer(ctx, append([]admin.Option{
+		admin.WithMemory(nsMemory, nseMemory),
+		admin.WithTimers(nseExpire.(expire.TimersProvider)),
+		admin.WithUnregisterServer(nseChain),
+	}, opts.admin...)...)
+
+	return registryserver.NewServer(nsServer, nseServer, registryserver.WithAdminServer(adminServer))
 }
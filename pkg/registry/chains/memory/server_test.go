@@ -0,0 +1,90 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc/codes"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	memorychain "github.com/networkservicemesh/sdk/pkg/registry/chains/memory"
+	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
+	"github.com/networkservicemesh/sdk/pkg/tools/sandbox"
+)
+
+const (
+	spiffeIDA = "spiffe://test.com/a"
+	spiffeIDB = "spiffe://test.com/b"
+)
+
+func requirePermissionDenied(t *testing.T, err error) {
+	require.Error(t, err)
+	require.Equal(t, codes.PermissionDenied, grpcutils.UnwrapCode(err))
+}
+
+func TestNewServer_Authorize(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := memorychain.NewServer(ctx, time.Minute, nil, memorychain.WithAuthorize()).NetworkServiceEndpointRegistryServer()
+
+	nse := &registry.NetworkServiceEndpoint{
+		Name:                "nse",
+		NetworkServiceNames: []string{"ns"},
+	}
+
+	_, err := server.Register(ctx, nse.Clone())
+	requirePermissionDenied(t, err)
+
+	reg, err := server.Register(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDA), nse.Clone())
+	require.NoError(t, err)
+
+	_, err = server.Register(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDB), reg.Clone())
+	requirePermissionDenied(t, err)
+
+	_, err = server.Unregister(ctx, reg.Clone())
+	requirePermissionDenied(t, err)
+
+	_, err = server.Unregister(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDA), reg.Clone())
+	require.NoError(t, err)
+}
+
+func TestNewServer_NotAuthorizedByDefault(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := memorychain.NewServer(ctx, time.Minute, nil).NetworkServiceEndpointRegistryServer()
+
+	reg, err := server.Register(ctx, &registry.NetworkServiceEndpoint{
+		Name:                "nse",
+		NetworkServiceNames: []string{"ns"},
+	})
+	require.NoError(t, err)
+
+	_, err = server.Unregister(ctx, reg)
+	require.NoError(t, err)
+}
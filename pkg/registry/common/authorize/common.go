@@ -0,0 +1,100 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorize
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/opa"
+	"github.com/networkservicemesh/sdk/pkg/tools/spiffejwt"
+	"github.com/networkservicemesh/sdk/pkg/tools/token"
+)
+
+const (
+	registerOperation   = "register"
+	unregisterOperation = "unregister"
+	findOperation       = "find"
+)
+
+// Policy represents authorization policy for the registry requests
+type Policy interface {
+	// Check checks authorization
+	Check(ctx context.Context, input interface{}) error
+}
+
+type policiesList []Policy
+
+func (l policiesList) check(ctx context.Context, input *Input) error {
+	for _, p := range l {
+		if p == nil {
+			continue
+		}
+		if err := p.Check(ctx, input); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func defaultPolicies() policiesList {
+	return []Policy{
+		opa.WithRegistrantPolicy(),
+		opa.WithAllowedIdentitiesPolicy(),
+	}
+}
+
+// Input is the model passed to the policies
+type Input struct {
+	// Operation is one of "register", "unregister", "find" or one of the admin API operations
+	Operation string `json:"operation"`
+	// Token is the token of the caller
+	Token string `json:"token"`
+	// SpiffeID is the verified SPIFFE ID of the caller, empty if the caller can't be verified
+	SpiffeID string `json:"spiffe_id"`
+	// Registrant is the SPIFFE ID of the caller registered the NSE, empty for the new NSEs
+	Registrant string `json:"registrant"`
+	// NetworkServiceNames are the Network Services the entry is registered under
+	NetworkServiceNames []string `json:"network_service_names"`
+	// AllowedIdentities are the SPIFFE IDs allowed to register under the Network Service
+	AllowedIdentities map[string][]string `json:"allowed_identities"`
+	// NetworkServiceEndpoint is the requested NSE, empty for the NS requests
+	NetworkServiceEndpoint interface{} `json:"network_service_endpoint,omitempty"`
+	// NetworkService is the requested NS, empty for the NSE requests
+	NetworkService interface{} `json:"network_service,omitempty"`
+}
+
+// NewInput creates the Input for the operation with the caller identity taken from the ctx. It is used to check the
+// calls of the registry APIs other than the NS and NSE registries with the same policies.
+func NewInput(ctx context.Context, operation string) *Input {
+	return newInput(ctx, operation, nil)
+}
+
+func newInput(ctx context.Context, operation string, allowedIdentities map[string][]string) *Input {
+	input := &Input{
+		Operation:         operation,
+		AllowedIdentities: allowedIdentities,
+	}
+	if tok, _, err := token.FromContext(ctx); err == nil {
+		input.Token = tok
+	}
+	// The identity is taken from the peer certificate verified by the transport, the token is only trusted if it
+	// is signed by the same certificate
+	if spiffeID, err := spiffejwt.PeerSpiffeID(ctx); err == nil {
+		input.SpiffeID = spiffeID.String()
+	}
+	return input
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorize
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+)
+
+type authorizeNSServer struct {
+	*authorizeOptions
+}
+
+// NewNetworkServiceRegistryServer - creates a NetworkServiceRegistryServer chain element checking the registry calls
+// with the policies. By default, only the allowed identities can register the Network Services.
+func NewNetworkServiceRegistryServer(options ...Option) registry.NetworkServiceRegistryServer {
+	return &authorizeNSServer{
+		authorizeOptions: newAuthorizeOptions(options...),
+	}
+}
+
+func (s *authorizeNSServer) Register(ctx context.Context, ns *registry.NetworkService) (*registry.NetworkService, error) {
+	if err := s.policies.check(ctx, s.newInput(ctx, registerOperation, ns)); err != nil {
+		return nil, err
+	}
+	return next.NetworkServiceRegistryServer(ctx).Register(ctx, ns)
+}
+
+func (s *authorizeNSServer) Find(query *registry.NetworkServiceQuery, server registry.NetworkServiceRegistry_FindServer) error {
+	if err := s.policies.check(server.Context(), s.newInput(server.Context(), findOperation, query.GetNetworkService())); err != nil {
+		return err
+	}
+	return next.NetworkServiceRegistryServer(server.Context()).Find(query, server)
+}
+
+func (s *authorizeNSServer) Unregister(ctx context.Context, ns *registry.NetworkService) (*empty.Empty, error) {
+	if err := s.policies.check(ctx, s.newInput(ctx, unregisterOperation, ns)); err != nil {
+		return nil, err
+	}
+	return next.NetworkServiceRegistryServer(ctx).Unregister(ctx, ns)
+}
+
+func (s *authorizeNSServer) newInput(ctx context.Context, operation string, ns *registry.NetworkService) *Input {
+	input := newInput(ctx, operation, s.allowedIdentities)
+	if ns.GetName() != "" {
+		input.NetworkServiceNames = []string{ns.GetName()}
+	}
+	input.NetworkService = ns
+	return input
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorize
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+)
+
+type registrant struct {
+	spiffeID       string
+	expirationTime time.Time
+}
+
+type authorizeNSEServer struct {
+	*authorizeOptions
+	registrants map[string]*registrant
+	mutex       sync.Mutex
+}
+
+// NewNetworkServiceEndpointRegistryServer - creates a NetworkServiceEndpointRegistryServer chain element checking
+// the registry calls with the policies. By default, only the original registrant can update or unregister the NSE
+// and only the allowed identities can register NSEs under the Network Services.
+func NewNetworkServiceEndpointRegistryServer(options ...Option) registry.NetworkServiceEndpointRegistryServer {
+	return &authorizeNSEServer{
+		authorizeOptions: newAuthorizeOptions(options...),
+		registrants:      make(map[string]*registrant),
+	}
+}
+
+func (s *authorizeNSEServer) Register(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*registry.NetworkServiceEndpoint, error) {
+	input := s.newInput(ctx, registerOperation, nse)
+	if err := s.policies.check(ctx, input); err != nil {
+		return nil, err
+	}
+
+	resp, err := next.NetworkServiceEndpointRegistryServer(ctx).Register(ctx, nse)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &registrant{
+		spiffeID: input.SpiffeID,
+	}
+	if resp.GetExpirationTime() != nil {
+		r.expirationTime = resp.GetExpirationTime().AsTime().Local()
+	}
+
+	s.mutex.Lock()
+	s.registrants[resp.Name] = r
+	s.mutex.Unlock()
+
+	return resp, nil
+}
+
+func (s *authorizeNSEServer) Find(query *registry.NetworkServiceEndpointQuery, server registry.NetworkServiceEndpointRegistry_FindServer) error {
+	input := s.newInput(server.Context(), findOperation, query.GetNetworkServiceEndpoint())
+	if err := s.policies.check(server.Context(), input); err != nil {
+		return err
+	}
+	return next.NetworkServiceEndpointRegistryServer(server.Context()).Find(query, server)
+}
+
+func (s *authorizeNSEServer) Unregister(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*empty.Empty, error) {
+	if err := s.policies.check(ctx, s.newInput(ctx, unregisterOperation, nse)); err != nil {
+		return nil, err
+	}
+
+	resp, err := next.NetworkServiceEndpointRegistryServer(ctx).Unregister(ctx, nse)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	delete(s.registrants, nse.Name)
+	s.mutex.Unlock()
+
+	return resp, nil
+}
+
+func (s *authorizeNSEServer) newInput(ctx context.Context, operation string, nse *registry.NetworkServiceEndpoint) *Input {
+	input := newInput(ctx, operation, s.allowedIdentities)
+	input.NetworkServiceNames = nse.GetNetworkServiceNames()
+	input.NetworkServiceEndpoint = nse
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if r, ok := s.registrants[nse.GetName()]; ok {
+		// The registrant is not tracked anymore when the NSE has expired
+		if !r.expirationTime.IsZero() && !clock.FromContext(ctx).Now().Before(r.expirationTime) {
+			delete(s.registrants, nse.GetName())
+		} else {
+			input.Registrant = r.spiffeID
+		}
+	}
+
+	return input
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorize
+
+type authorizeOptions struct {
+	policies          policiesList
+	allowedIdentities map[string][]string
+}
+
+// Option is an option pattern for NewNetworkServiceRegistryServer, NewNetworkServiceEndpointRegistryServer
+type Option func(o *authorizeOptions)
+
+// Any authorizes any call of the registry API
+func Any() Option {
+	return WithPolicies(nil)
+}
+
+// WithPolicies sets the policies replacing the default ones
+func WithPolicies(policies ...Policy) Option {
+	return func(o *authorizeOptions) {
+		o.policies = policies
+	}
+}
+
+// WithAllowedIdentities sets the SPIFFE IDs allowed to register NS and NSEs under the networkService
+func WithAllowedIdentities(networkService string, spiffeIDs ...string) Option {
+	return func(o *authorizeOptions) {
+		o.allowedIdentities[networkService] = append(o.allowedIdentities[networkService], spiffeIDs...)
+	}
+}
+
+func newAuthorizeOptions(options ...Option) *authorizeOptions {
+	o := &authorizeOptions{
+		policies:          defaultPolicies(),
+		allowedIdentities: make(map[string][]string),
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+	return o
+}
@@ -0,0 +1,235 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authorize_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/authorize"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+	"github.com/networkservicemesh/sdk/pkg/tools/sandbox"
+	"github.com/networkservicemesh/sdk/pkg/tools/spiffejwt"
+)
+
+const (
+	spiffeIDA = "spiffe://test.com/a"
+	spiffeIDB = "spiffe://test.com/b"
+)
+
+func requirePermissionDenied(t *testing.T, err error) {
+	require.Error(t, err)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestAuthorizeNSEServer_Registrant(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := authorize.NewNetworkServiceEndpointRegistryServer()
+
+	nse := &registry.NetworkServiceEndpoint{Name: "nse"}
+
+	_, err := server.Register(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDA), nse.Clone())
+	require.NoError(t, err)
+
+	// Refresh by the registrant
+	_, err = server.Register(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDA), nse.Clone())
+	require.NoError(t, err)
+
+	_, err = server.Register(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDB), nse.Clone())
+	requirePermissionDenied(t, err)
+
+	_, err = server.Register(ctx, nse.Clone())
+	requirePermissionDenied(t, err)
+
+	_, err = server.Unregister(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDB), nse.Clone())
+	requirePermissionDenied(t, err)
+
+	_, err = adapters.NetworkServiceEndpointServerToClient(server).Find(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDB), &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: nse.Clone(),
+	})
+	require.NoError(t, err)
+
+	_, err = server.Unregister(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDA), nse.Clone())
+	require.NoError(t, err)
+
+	_, err = server.Register(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDB), nse.Clone())
+	require.NoError(t, err)
+}
+
+func TestAuthorizeNSEServer_RegistrantExpired(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	clockMock := clockmock.NewMock()
+	clockMock.Set(time.Now())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = clock.WithClock(ctx, clockMock)
+
+	server := authorize.NewNetworkServiceEndpointRegistryServer()
+
+	nse := &registry.NetworkServiceEndpoint{
+		Name:           "nse",
+		ExpirationTime: timestamppb.New(clockMock.Now().Add(time.Minute)),
+	}
+
+	_, err := server.Register(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDA), nse.Clone())
+	require.NoError(t, err)
+
+	_, err = server.Register(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDB), nse.Clone())
+	requirePermissionDenied(t, err)
+
+	clockMock.Add(time.Minute)
+
+	_, err = server.Register(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDB), nse.Clone())
+	require.NoError(t, err)
+}
+
+func TestAuthorizeServer_AllowedIdentities(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	options := []authorize.Option{
+		authorize.WithAllowedIdentities("ns-1", spiffeIDA),
+	}
+
+	nsServer := authorize.NewNetworkServiceRegistryServer(options...)
+
+	_, err := nsServer.Register(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDB), &registry.NetworkService{Name: "ns-1"})
+	requirePermissionDenied(t, err)
+
+	_, err = nsServer.Register(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDA), &registry.NetworkService{Name: "ns-1"})
+	require.NoError(t, err)
+
+	_, err = nsServer.Register(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDB), &registry.NetworkService{Name: "ns-2"})
+	require.NoError(t, err)
+
+	nseServer := authorize.NewNetworkServiceEndpointRegistryServer(options...)
+
+	_, err = nseServer.Register(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDB), &registry.NetworkServiceEndpoint{
+		Name:                "nse-1",
+		NetworkServiceNames: []string{"ns-2", "ns-1"},
+	})
+	requirePermissionDenied(t, err)
+
+	_, err = nseServer.Register(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDA), &registry.NetworkServiceEndpoint{
+		Name:                "nse-1",
+		NetworkServiceNames: []string{"ns-2", "ns-1"},
+	})
+	require.NoError(t, err)
+
+	_, err = nseServer.Register(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDB), &registry.NetworkServiceEndpoint{
+		Name:                "nse-2",
+		NetworkServiceNames: []string{"ns-2"},
+	})
+	require.NoError(t, err)
+}
+
+func TestAuthorizeNSEServer_Any(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := authorize.NewNetworkServiceEndpointRegistryServer(authorize.Any())
+
+	_, err := server.Register(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDA), &registry.NetworkServiceEndpoint{Name: "nse"})
+	require.NoError(t, err)
+
+	_, err = server.Unregister(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDB), &registry.NetworkServiceEndpoint{Name: "nse"})
+	require.NoError(t, err)
+}
+
+func TestAuthorizeNSEServer_NoIdentity(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := authorize.NewNetworkServiceEndpointRegistryServer()
+
+	nse := &registry.NetworkServiceEndpoint{Name: "nse"}
+
+	_, err := server.Register(ctx, nse.Clone())
+	requirePermissionDenied(t, err)
+
+	_, err = server.Register(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDA), nse.Clone())
+	require.NoError(t, err)
+
+	_, err = server.Register(ctx, nse.Clone())
+	requirePermissionDenied(t, err)
+
+	_, err = server.Unregister(ctx, nse.Clone())
+	requirePermissionDenied(t, err)
+}
+
+func TestAuthorizeNSEServer_ForgedToken(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := authorize.NewNetworkServiceEndpointRegistryServer()
+
+	nse := &registry.NetworkServiceEndpoint{Name: "nse"}
+
+	_, err := server.Register(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDA), nse.Clone())
+	require.NoError(t, err)
+
+	// B connects with its own certificate and sends the token claiming to be A
+	forgedCtx := sandbox.WithPeerSpiffeID(ctx, t, spiffeIDB)
+
+	forgedSVID, err := sandbox.NewTestSVID(spiffeIDA)
+	require.NoError(t, err)
+	forgedToken, _, err := spiffejwt.TokenGeneratorFunc(forgedSVID, time.Hour)(nil)
+	require.NoError(t, err)
+	forgedCtx = metadata.NewIncomingContext(forgedCtx, metadata.Pairs(
+		"nsm-client-token", forgedToken,
+		"nsm-client-token-expires", time.Now().Add(time.Hour).Format(time.RFC3339Nano),
+	))
+
+	_, err = server.Register(forgedCtx, nse.Clone())
+	requirePermissionDenied(t, err)
+
+	_, err = server.Unregister(forgedCtx, nse.Clone())
+	requirePermissionDenied(t, err)
+
+	// Token without the peer certificate is not trusted
+	_, err = server.Unregister(metadata.NewIncomingContext(ctx, metadata.Pairs(
+		"nsm-client-token", forgedToken,
+		"nsm-client-token-expires", time.Now().Add(time.Hour).Format(time.RFC3339Nano),
+	)), nse.Clone())
+	requirePermissionDenied(t, err)
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientinfo_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/clientinfo"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/checks/checknse"
+)
+
+func TestClientInfoNSEClient_Register(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	envs := map[string]string{
+		"NODE_NAME":    "node",
+		"POD_NAME":     "pod",
+		"CLUSTER_NAME": "cluster",
+	}
+	for name, value := range envs {
+		require.NoError(t, os.Setenv(name, value))
+	}
+	defer func() {
+		for name := range envs {
+			_ = os.Unsetenv(name)
+		}
+	}()
+
+	client := next.NewNetworkServiceEndpointRegistryClient(
+		clientinfo.NewNetworkServiceEndpointRegistryClient(),
+		checknse.NewClient(t, func(t *testing.T, nse *registry.NetworkServiceEndpoint) {
+			require.Equal(t, map[string]*registry.NetworkServiceLabels{
+				"ns-1": {
+					Labels: map[string]string{
+						"app":            "firewall",
+						"NodeNameKey":    "node",
+						"PodNameKey":     "pod",
+						"ClusterNameKey": "cluster",
+					},
+				},
+				"ns-2": {
+					Labels: map[string]string{
+						"NodeNameKey":    "node",
+						"PodNameKey":     "pod",
+						"ClusterNameKey": "cluster",
+					},
+				},
+			}, nse.NetworkServiceLabels)
+		}),
+	)
+
+	_, err := client.Register(context.Background(), &registry.NetworkServiceEndpoint{
+		NetworkServiceNames: []string{"ns-1", "ns-2"},
+		NetworkServiceLabels: map[string]*registry.NetworkServiceLabels{
+			"ns-1": {
+				Labels: map[string]string{"app": "firewall"},
+			},
+		},
+	})
+	require.NoError(t, err)
+}
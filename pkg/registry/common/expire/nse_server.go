@@ -36,6 +36,12 @@ type expireNSEServer struct {
 	timers        unregisterTimerMap
 }
 
+// TimersProvider is implemented by the NSE expire server
+type TimersProvider interface {
+	// Timers returns the expiration times of the pending unregister timers by the NSE names
+	Timers() map[string]time.Time
+}
+
 type unregisterTimer struct {
 	expirationTime    time.Time
 	started, canceled bool
@@ -135,6 +141,22 @@ func (n *expireNSEServer) Find(query *registry.NetworkServiceEndpointQuery, s re
 	return next.NetworkServiceEndpointRegistryServer(s.Context()).Find(query, s)
 }
 
+// Timers returns the expiration times of the pending unregister timers by the NSE names
+func (n *expireNSEServer) Timers() map[string]time.Time {
+	timers := make(map[string]time.Time)
+	n.timers.Range(func(name string, t *unregisterTimer) bool {
+		var pending bool
+		<-t.executor.AsyncExec(func() {
+			pending = !t.started && !t.canceled
+		})
+		if pending {
+			timers[name] = t.expirationTime
+		}
+		return true
+	})
+	return timers
+}
+
 func (n *expireNSEServer) Unregister(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*empty.Empty, error) {
 	if t, ok := n.timers.LoadAndDelete(nse.Name); ok {
 		if !t.timer.Stop() {
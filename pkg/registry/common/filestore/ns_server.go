@@ -0,0 +1,95 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestore
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/walstore"
+)
+
+// NetworkServiceRegistryServer is a NetworkServiceRegistryServer persisting registered NSs on the local disk
+type NetworkServiceRegistryServer interface {
+	registry.NetworkServiceRegistryServer
+
+	// Restore registers NSs loaded from the disk with the server. The server should be the registry chain containing
+	// this element, so all the chain elements (e.g. expire) receive the restored NSs.
+	Restore(ctx context.Context, server registry.NetworkServiceRegistryServer) error
+}
+
+type fileNSServer struct {
+	store *walstore.Store
+}
+
+// NewNetworkServiceRegistryServer creates a new NetworkServiceRegistryServer storing NSs in the dir. It should be
+// placed right before the memory element, so it receives all the registrations and unregistrations including the
+// ones made by expire.
+func NewNetworkServiceRegistryServer(ctx context.Context, dir string, options ...Option) (NetworkServiceRegistryServer, error) {
+	s, err := walstore.New(ctx, dir, func() proto.Message { return new(registry.NetworkService) }, options...)
+	if err != nil {
+		return nil, err
+	}
+	return &fileNSServer{
+		store: s,
+	}, nil
+}
+
+func (s *fileNSServer) Register(ctx context.Context, ns *registry.NetworkService) (*registry.NetworkService, error) {
+	resp, err := next.NetworkServiceRegistryServer(ctx).Register(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Put(resp.Name, resp); err != nil {
+		if _, unregisterErr := next.NetworkServiceRegistryServer(ctx).Unregister(ctx, resp.Clone()); unregisterErr != nil {
+			log.FromContext(ctx).Errorf("failed to unregister not stored NS: %s", unregisterErr.Error())
+		}
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (s *fileNSServer) Find(query *registry.NetworkServiceQuery, server registry.NetworkServiceRegistry_FindServer) error {
+	return next.NetworkServiceRegistryServer(server.Context()).Find(query, server)
+}
+
+func (s *fileNSServer) Unregister(ctx context.Context, ns *registry.NetworkService) (*empty.Empty, error) {
+	if err := s.store.Delete(ns.Name); err != nil {
+		return nil, err
+	}
+	return next.NetworkServiceRegistryServer(ctx).Unregister(ctx, ns)
+}
+
+func (s *fileNSServer) Restore(ctx context.Context, server registry.NetworkServiceRegistryServer) error {
+	logger := log.FromContext(ctx).WithField("fileNSServer", "Restore")
+	for _, entity := range s.store.List() {
+		ns := entity.(*registry.NetworkService)
+		if _, err := server.Register(ctx, ns); err != nil {
+			logger.Errorf("failed to restore NS: %s %s", ns.Name, err.Error())
+		}
+	}
+	return errors.Wrap(s.store.Snapshot(), "failed to snapshot restored NSs")
+}
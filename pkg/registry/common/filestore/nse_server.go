@@ -0,0 +1,156 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestore
+
+import (
+	"context"
+	"sync"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/setid"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/walstore"
+)
+
+// NetworkServiceEndpointRegistryServer is a NetworkServiceEndpointRegistryServer persisting registered NSEs on the
+// local disk
+type NetworkServiceEndpointRegistryServer interface {
+	registry.NetworkServiceEndpointRegistryServer
+
+	// Restore registers NSEs loaded from the disk with the server. The server should be the registry chain
+	// containing this element, so all the chain elements (e.g. expire) receive the restored NSEs. Already expired
+	// NSEs are dropped.
+	Restore(ctx context.Context, server registry.NetworkServiceEndpointRegistryServer) error
+}
+
+type fileNSEServer struct {
+	ctx   context.Context
+	clock clock.Clock
+	store *walstore.Store
+
+	lock   sync.Mutex
+	timers map[string]clock.Timer
+}
+
+// NewNetworkServiceEndpointRegistryServer creates a new NetworkServiceEndpointRegistryServer storing NSEs in the dir.
+// It should be placed right before the expire element, so it stores the expiration times set by expire. Stored NSEs
+// are deleted on their expiration, since the unregistrations made by expire don't reach the store.
+func NewNetworkServiceEndpointRegistryServer(ctx context.Context, dir string, options ...Option) (NetworkServiceEndpointRegistryServer, error) {
+	s, err := walstore.New(ctx, dir, func() proto.Message { return new(registry.NetworkServiceEndpoint) }, options...)
+	if err != nil {
+		return nil, err
+	}
+	return &fileNSEServer{
+		ctx:    ctx,
+		clock:  clock.FromContext(ctx),
+		store:  s,
+		timers: make(map[string]clock.Timer),
+	}, nil
+}
+
+func (s *fileNSEServer) Register(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*registry.NetworkServiceEndpoint, error) {
+	resp, err := next.NetworkServiceEndpointRegistryServer(ctx).Register(ctx, nse)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Put(resp.Name, resp); err != nil {
+		if _, unregisterErr := next.NetworkServiceEndpointRegistryServer(ctx).Unregister(ctx, resp.Clone()); unregisterErr != nil {
+			log.FromContext(ctx).Errorf("failed to unregister not stored NSE: %s", unregisterErr.Error())
+		}
+		return nil, err
+	}
+	s.resetTimer(resp)
+
+	return resp, nil
+}
+
+func (s *fileNSEServer) Find(query *registry.NetworkServiceEndpointQuery, server registry.NetworkServiceEndpointRegistry_FindServer) error {
+	return next.NetworkServiceEndpointRegistryServer(server.Context()).Find(query, server)
+}
+
+func (s *fileNSEServer) Unregister(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*empty.Empty, error) {
+	s.stopTimer(nse.Name)
+	if err := s.store.Delete(nse.Name); err != nil {
+		return nil, err
+	}
+	return next.NetworkServiceEndpointRegistryServer(ctx).Unregister(ctx, nse)
+}
+
+func (s *fileNSEServer) Restore(ctx context.Context, server registry.NetworkServiceEndpointRegistryServer) error {
+	logger := log.FromContext(ctx).WithField("fileNSEServer", "Restore")
+
+	// Restored NSEs already have unique names set on the first registration
+	ctx = setid.WithRegisteredName(ctx)
+	for _, entity := range s.store.List() {
+		nse := entity.(*registry.NetworkServiceEndpoint)
+		if nse.ExpirationTime != nil && s.clock.Until(nse.ExpirationTime.AsTime()) <= 0 {
+			if err := s.store.Delete(nse.Name); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := server.Register(ctx, nse); err != nil {
+			logger.Errorf("failed to restore NSE: %s %s", nse.Name, err.Error())
+		}
+	}
+	return errors.Wrap(s.store.Snapshot(), "failed to snapshot restored NSEs")
+}
+
+// resetTimer schedules the stored NSE deletion on its expiration
+func (s *fileNSEServer) resetTimer(nse *registry.NetworkServiceEndpoint) {
+	s.stopTimer(nse.Name)
+	if nse.ExpirationTime == nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var timer clock.Timer
+	timer = s.clock.AfterFunc(s.clock.Until(nse.ExpirationTime.AsTime()), func() {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		if s.timers[nse.Name] != timer || s.ctx.Err() != nil {
+			return
+		}
+		delete(s.timers, nse.Name)
+
+		if err := s.store.Delete(nse.Name); err != nil {
+			log.FromContext(s.ctx).Errorf("failed to delete expired NSE: %s %s", nse.Name, err.Error())
+		}
+	})
+	s.timers[nse.Name] = timer
+}
+
+func (s *fileNSEServer) stopTimer(name string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if timer, ok := s.timers[name]; ok {
+		timer.Stop()
+		delete(s.timers, name)
+	}
+}
@@ -0,0 +1,345 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestore_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/expire"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/filestore"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/setid"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+)
+
+const (
+	expireTimeout = time.Hour
+	testWait      = time.Second
+	testTick      = 10 * time.Millisecond
+)
+
+type testRegistry struct {
+	store  filestore.NetworkServiceEndpointRegistryServer
+	server registry.NetworkServiceEndpointRegistryServer
+	cancel context.CancelFunc
+}
+
+func newTestRegistry(ctx context.Context, t *testing.T, dir string, options ...filestore.Option) *testRegistry {
+	ctx, cancel := context.WithCancel(ctx)
+
+	store, err := filestore.NewNetworkServiceEndpointRegistryServer(ctx, dir, options...)
+	require.NoError(t, err)
+
+	return &testRegistry{
+		store: store,
+		server: next.NewNetworkServiceEndpointRegistryServer(
+			store,
+			expire.NewNetworkServiceEndpointRegistryServer(ctx, expireTimeout),
+			memory.NewNetworkServiceEndpointRegistryServer(),
+			setid.NewNetworkServiceEndpointRegistryServer(),
+		),
+		cancel: cancel,
+	}
+}
+
+func (r *testRegistry) find(t *testing.T) map[string]*registry.NetworkServiceEndpoint {
+	stream, err := adapters.NetworkServiceEndpointServerToClient(r.server).Find(context.Background(), &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: new(registry.NetworkServiceEndpoint),
+	})
+	require.NoError(t, err)
+
+	nses := make(map[string]*registry.NetworkServiceEndpoint)
+	for _, nse := range registry.ReadNetworkServiceEndpointList(stream) {
+		nses[nse.Name] = nse
+	}
+	return nses
+}
+
+func TestFileStore_RestoreNSEs(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	dir := t.TempDir()
+
+	r := newTestRegistry(context.Background(), t, dir)
+
+	reg1, err := r.server.Register(context.Background(), &registry.NetworkServiceEndpoint{
+		Name: "nse-1",
+		Url:  "tcp://1.1.1.1",
+	})
+	require.NoError(t, err)
+
+	reg2, err := r.server.Register(context.Background(), &registry.NetworkServiceEndpoint{
+		Name:           "nse-2",
+		ExpirationTime: timestamppb.New(time.Now().Add(time.Second)),
+	})
+	require.NoError(t, err)
+
+	reg3, err := r.server.Register(context.Background(), &registry.NetworkServiceEndpoint{
+		Name: "nse-3",
+	})
+	require.NoError(t, err)
+	_, err = r.server.Unregister(context.Background(), reg3)
+	require.NoError(t, err)
+
+	r.cancel()
+
+	// Registry restart
+	r = newTestRegistry(context.Background(), t, dir)
+	defer r.cancel()
+
+	require.Empty(t, r.find(t))
+	require.NoError(t, r.store.Restore(context.Background(), r.server))
+
+	nses := r.find(t)
+	require.Len(t, nses, 2)
+	require.Equal(t, reg1.Url, nses[reg1.Name].Url)
+	require.Contains(t, nses, reg2.Name)
+
+	// Expiration timer should be re-armed
+	require.Eventually(t, func() bool {
+		_, ok := r.find(t)[reg2.Name]
+		return !ok
+	}, 2*testWait, testTick)
+
+	// NSE should be refreshed with the same name
+	refresh, err := r.server.Register(context.Background(), reg1.Clone())
+	require.NoError(t, err)
+	require.Equal(t, reg1.Name, refresh.Name)
+}
+
+func TestFileStore_DropExpiredAndBrokenRecords(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	dir := t.TempDir()
+
+	r := newTestRegistry(context.Background(), t, dir)
+
+	reg1, err := r.server.Register(context.Background(), &registry.NetworkServiceEndpoint{
+		Name: "nse-1",
+	})
+	require.NoError(t, err)
+
+	_, err = r.server.Register(context.Background(), &registry.NetworkServiceEndpoint{
+		Name:           "nse-2",
+		ExpirationTime: timestamppb.New(time.Now().Add(10 * time.Minute)),
+	})
+	require.NoError(t, err)
+
+	r.cancel()
+
+	// Crash in the middle of the write-ahead log record
+	wal, err := os.OpenFile(filepath.Join(dir, "wal"), os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err)
+	_, err = wal.WriteString(`{"op":"delete","na`)
+	require.NoError(t, err)
+	require.NoError(t, wal.Close())
+
+	// Registry restarts 30 minutes later
+	clockMock := clockmock.NewMock()
+	clockMock.Set(time.Now().Add(30 * time.Minute))
+
+	r = newTestRegistry(clock.WithClock(context.Background(), clockMock), t, dir)
+	defer r.cancel()
+
+	require.NoError(t, r.store.Restore(context.Background(), r.server))
+
+	nses := r.find(t)
+	require.Len(t, nses, 1)
+	require.Contains(t, nses, reg1.Name)
+}
+
+func TestFileStore_RestoreExpirationTime(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	dir := t.TempDir()
+
+	r := newTestRegistry(context.Background(), t, dir)
+
+	reg, err := r.server.Register(context.Background(), &registry.NetworkServiceEndpoint{
+		Name: "nse-1",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, reg.ExpirationTime)
+
+	r.cancel()
+
+	// Registry restarts after the NSE expiration
+	clockMock := clockmock.NewMock()
+	clockMock.Set(reg.ExpirationTime.AsTime().Add(time.Second))
+
+	r = newTestRegistry(clock.WithClock(context.Background(), clockMock), t, dir)
+	defer r.cancel()
+
+	require.NoError(t, r.store.Restore(context.Background(), r.server))
+	require.Empty(t, r.find(t))
+}
+
+func TestFileStore_RestoreNotExpired(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	dir := t.TempDir()
+
+	r := newTestRegistry(context.Background(), t, dir)
+
+	reg, err := r.server.Register(context.Background(), &registry.NetworkServiceEndpoint{
+		Name: "nse-1",
+	})
+	require.NoError(t, err)
+
+	r.cancel()
+
+	// Registry restarts before the NSE expiration
+	r = newTestRegistry(context.Background(), t, dir)
+	defer r.cancel()
+
+	require.NoError(t, r.store.Restore(context.Background(), r.server))
+
+	nses := r.find(t)
+	require.Contains(t, nses, reg.Name)
+	require.NotNil(t, nses[reg.Name].ExpirationTime)
+	require.False(t, nses[reg.Name].ExpirationTime.AsTime().After(reg.ExpirationTime.AsTime()))
+}
+
+func TestFileStore_DeleteExpired(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	dir := t.TempDir()
+
+	clockMock := clockmock.NewMock()
+	clockMock.Set(time.Now())
+	ctx := clock.WithClock(context.Background(), clockMock)
+
+	r := newTestRegistry(ctx, t, dir)
+
+	_, err := r.server.Register(context.Background(), &registry.NetworkServiceEndpoint{
+		Name:           "nse-1",
+		ExpirationTime: timestamppb.New(clockMock.Now().Add(time.Minute)),
+	})
+	require.NoError(t, err)
+
+	// Expired NSE should be deleted from the store even if the expire unregistration doesn't reach it
+	clockMock.Add(time.Minute)
+	require.Eventually(t, func() bool {
+		data, err := ioutil.ReadFile(filepath.Join(dir, "wal"))
+		require.NoError(t, err)
+		return strings.Contains(string(data), `"delete"`)
+	}, testWait, testTick)
+
+	r.cancel()
+
+	// Registry restarts with the clock set back before the NSE expiration
+	clockMock = clockmock.NewMock()
+	clockMock.Set(time.Now())
+
+	r = newTestRegistry(clock.WithClock(context.Background(), clockMock), t, dir)
+	defer r.cancel()
+
+	require.NoError(t, r.store.Restore(context.Background(), r.server))
+	require.Empty(t, r.find(t))
+}
+
+func TestFileStore_Snapshot(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	dir := t.TempDir()
+
+	clockMock := clockmock.NewMock()
+	ctx := clock.WithClock(context.Background(), clockMock)
+
+	r := newTestRegistry(ctx, t, dir, filestore.WithSnapshotInterval(time.Minute))
+
+	for _, name := range []string{"nse-1", "nse-2"} {
+		_, err := r.server.Register(context.Background(), &registry.NetworkServiceEndpoint{
+			Name: name,
+		})
+		require.NoError(t, err)
+	}
+
+	walSize := func() int {
+		data, err := ioutil.ReadFile(filepath.Join(dir, "wal"))
+		require.NoError(t, err)
+		return len(data)
+	}
+	require.NotZero(t, walSize())
+
+	// Write-ahead log should be compacted into the snapshot
+	require.Eventually(t, func() bool {
+		clockMock.Add(time.Minute)
+		return walSize() == 0
+	}, testWait, testTick)
+
+	r.cancel()
+
+	r = newTestRegistry(ctx, t, dir)
+	defer r.cancel()
+
+	require.NoError(t, r.store.Restore(context.Background(), r.server))
+	require.Len(t, r.find(t), 2)
+}
+
+func TestFileStore_RestoreNSs(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	dir := t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	store, err := filestore.NewNetworkServiceRegistryServer(ctx, dir)
+	require.NoError(t, err)
+
+	server := next.NewNetworkServiceRegistryServer(store, memory.NewNetworkServiceRegistryServer())
+	_, err = server.Register(context.Background(), &registry.NetworkService{
+		Name:    "ns-1",
+		Payload: "IP",
+	})
+	require.NoError(t, err)
+
+	cancel()
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+
+	store, err = filestore.NewNetworkServiceRegistryServer(ctx, dir)
+	require.NoError(t, err)
+
+	server = next.NewNetworkServiceRegistryServer(store, memory.NewNetworkServiceRegistryServer())
+	require.NoError(t, store.Restore(context.Background(), server))
+
+	stream, err := adapters.NetworkServiceServerToClient(server).Find(context.Background(), &registry.NetworkServiceQuery{
+		NetworkService: new(registry.NetworkService),
+	})
+	require.NoError(t, err)
+
+	nss := registry.ReadNetworkServiceList(stream)
+	require.Len(t, nss, 1)
+	require.Equal(t, "IP", nss[0].Payload)
+}
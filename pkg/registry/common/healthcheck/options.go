@@ -0,0 +1,111 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	defaultInterval         = 10 * time.Second
+	defaultTimeout          = time.Second
+	defaultFailureThreshold = 3
+	defaultMaxConcurrency   = 16
+)
+
+type healthCheckOptions struct {
+	interval         time.Duration
+	timeout          time.Duration
+	failureThreshold int
+	maxConcurrency   int
+	unregister       bool
+	serviceName      string
+	dialOptions      []grpc.DialOption
+}
+
+// Option is an option pattern for NewNetworkServiceEndpointRegistryServer
+type Option func(o *healthCheckOptions)
+
+// WithInterval sets the interval between the probes of the same NSE
+func WithInterval(interval time.Duration) Option {
+	return func(o *healthCheckOptions) {
+		o.interval = interval
+	}
+}
+
+// WithTimeout sets the timeout of the single probe
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *healthCheckOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithFailureThreshold sets the number of the consecutive failed probes marking the NSE unhealthy
+func WithFailureThreshold(failureThreshold int) Option {
+	return func(o *healthCheckOptions) {
+		o.failureThreshold = failureThreshold
+	}
+}
+
+// WithMaxConcurrency sets the max number of the probes running at the same time
+func WithMaxConcurrency(maxConcurrency int) Option {
+	return func(o *healthCheckOptions) {
+		o.maxConcurrency = maxConcurrency
+	}
+}
+
+// WithUnregister sets the unhealthy NSEs to be unregistered instead of being only filtered from the Find results
+func WithUnregister() Option {
+	return func(o *healthCheckOptions) {
+		o.unregister = true
+	}
+}
+
+// WithServiceName sets the service name to check, by default the overall NSE server health is checked
+func WithServiceName(serviceName string) Option {
+	return func(o *healthCheckOptions) {
+		o.serviceName = serviceName
+	}
+}
+
+// WithDialOptions sets gRPC Dial Options used to dial the NSEs
+func WithDialOptions(dialOptions ...grpc.DialOption) Option {
+	return func(o *healthCheckOptions) {
+		o.dialOptions = dialOptions
+	}
+}
+
+func newHealthCheckOptions(options ...Option) *healthCheckOptions {
+	o := &healthCheckOptions{
+		interval:         defaultInterval,
+		timeout:          defaultTimeout,
+		failureThreshold: defaultFailureThreshold,
+		maxConcurrency:   defaultMaxConcurrency,
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+	if o.failureThreshold < 1 {
+		o.failureThreshold = 1
+	}
+	if o.maxConcurrency < 1 {
+		o.maxConcurrency = 1
+	}
+	return o
+}
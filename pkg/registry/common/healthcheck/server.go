@@ -0,0 +1,337 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"context"
+	"net/url"
+	"sync"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type target struct {
+	nse        *registry.NetworkServiceEndpoint
+	nextServer registry.NetworkServiceEndpointRegistryServer
+	failures   int
+	unhealthy  bool
+	probing    bool
+}
+
+type healthCheckNSEServer struct {
+	*healthCheckOptions
+	ctx   context.Context
+	clock clock.Clock
+	sem   chan struct{}
+
+	lock     sync.Mutex
+	targets  map[string]*target
+	watchers map[*healthCheckFindServer]struct{}
+}
+
+// NewNetworkServiceEndpointRegistryServer creates a NetworkServiceEndpointRegistryServer chain element periodically
+// probing the registered NSEs with the gRPC health service. NSE failed the consecutive probes is marked unhealthy and
+// filtered from the Find results until the next successful probe, or unregistered if WithUnregister is set. Active
+// watchers get the unregister event for the NSE marked unhealthy and the NSE itself once it is healthy again. It should
+// be placed right before the memory element, so the unregistrations made by this element reach the memory.
+func NewNetworkServiceEndpointRegistryServer(ctx context.Context, options ...Option) registry.NetworkServiceEndpointRegistryServer {
+	s := &healthCheckNSEServer{
+		healthCheckOptions: newHealthCheckOptions(options...),
+		ctx:                ctx,
+		clock:              clock.FromContext(ctx),
+		targets:            make(map[string]*target),
+		watchers:           make(map[*healthCheckFindServer]struct{}),
+	}
+	s.sem = make(chan struct{}, s.maxConcurrency)
+
+	go s.probeLoop(s.clock.Ticker(s.interval))
+
+	return s
+}
+
+func (s *healthCheckNSEServer) Register(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*registry.NetworkServiceEndpoint, error) {
+	resp, err := next.NetworkServiceEndpointRegistryServer(ctx).Register(ctx, nse)
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if t, ok := s.targets[resp.Name]; ok && t.nse.Url == resp.Url {
+		// Refresh keeps the health of the NSE
+		t.nse = resp.Clone()
+		return resp, nil
+	}
+	s.targets[resp.Name] = &target{
+		nse:        resp.Clone(),
+		nextServer: next.NetworkServiceEndpointRegistryServer(ctx),
+	}
+
+	return resp, nil
+}
+
+func (s *healthCheckNSEServer) Find(query *registry.NetworkServiceEndpointQuery, server registry.NetworkServiceEndpointRegistry_FindServer) error {
+	findServer := &healthCheckFindServer{
+		NetworkServiceEndpointRegistry_FindServer: server,
+		server:  s,
+		visible: make(map[string]bool),
+	}
+
+	if query.Watch {
+		s.lock.Lock()
+		s.watchers[findServer] = struct{}{}
+		s.lock.Unlock()
+
+		defer func() {
+			s.lock.Lock()
+			delete(s.watchers, findServer)
+			s.lock.Unlock()
+		}()
+	}
+
+	return next.NetworkServiceEndpointRegistryServer(server.Context()).Find(query, findServer)
+}
+
+func (s *healthCheckNSEServer) Unregister(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*empty.Empty, error) {
+	s.lock.Lock()
+	delete(s.targets, nse.Name)
+	s.lock.Unlock()
+
+	return next.NetworkServiceEndpointRegistryServer(ctx).Unregister(ctx, nse)
+}
+
+func (s *healthCheckNSEServer) isUnhealthy(name string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	t, ok := s.targets[name]
+	return ok && t.unhealthy
+}
+
+func (s *healthCheckNSEServer) probeLoop(ticker clock.Ticker) {
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C():
+			// Targets still being probed since the previous tick are skipped
+			var targets []*target
+			s.lock.Lock()
+			for _, t := range s.targets {
+				if !t.probing {
+					t.probing = true
+					targets = append(targets, t)
+				}
+			}
+			s.lock.Unlock()
+
+			go s.probeAll(targets)
+		}
+	}
+}
+
+func (s *healthCheckNSEServer) probeAll(targets []*target) {
+	for i, t := range targets {
+		select {
+		case <-s.ctx.Done():
+			s.lock.Lock()
+			for _, t := range targets[i:] {
+				t.probing = false
+			}
+			s.lock.Unlock()
+			return
+		case s.sem <- struct{}{}:
+		}
+
+		go func(t *target) {
+			defer func() { <-s.sem }()
+
+			s.lock.Lock()
+			nse := t.nse.Clone()
+			s.lock.Unlock()
+
+			s.handleResult(t, s.probe(nse))
+		}(t)
+	}
+}
+
+func (s *healthCheckNSEServer) probe(nse *registry.NetworkServiceEndpoint) error {
+	u, err := url.Parse(nse.Url)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse NSE URL: %s", nse.Url)
+	}
+
+	ctx, cancel := s.clock.WithTimeout(s.ctx, s.timeout)
+	defer cancel()
+
+	cc, err := grpc.DialContext(ctx, grpcutils.URLToTarget(u), s.dialOptions...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to dial NSE: %s", nse.Url)
+	}
+	defer func() { _ = cc.Close() }()
+
+	resp, err := grpc_health_v1.NewHealthClient(cc).Check(ctx, &grpc_health_v1.HealthCheckRequest{
+		Service: s.serviceName,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to check NSE health: %s", nse.Url)
+	}
+	if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		return errors.Errorf("NSE is not serving: %s %s", nse.Url, resp.Status)
+	}
+	return nil
+}
+
+func (s *healthCheckNSEServer) handleResult(t *target, err error) {
+	s.lock.Lock()
+
+	t.probing = false
+	if s.targets[t.nse.Name] != t || s.ctx.Err() != nil {
+		s.lock.Unlock()
+		return
+	}
+
+	if err == nil {
+		t.failures = 0
+		recovered := t.unhealthy
+		t.unhealthy = false
+
+		if !recovered {
+			s.lock.Unlock()
+			return
+		}
+		nse, watchers := t.nse.Clone(), s.watchersList()
+		s.lock.Unlock()
+
+		for _, w := range watchers {
+			w.show(nse)
+		}
+		return
+	}
+
+	t.failures++
+	if t.failures < s.failureThreshold || t.unhealthy {
+		s.lock.Unlock()
+		return
+	}
+
+	logger := log.FromContext(s.ctx).WithField("healthCheckNSEServer", "handleResult")
+	logger.Warnf("NSE is unhealthy: %s %s", t.nse.Name, err.Error())
+
+	t.unhealthy = true
+	nse, watchers := t.nse.Clone(), s.watchersList()
+	if !s.unregister {
+		s.lock.Unlock()
+
+		for _, w := range watchers {
+			w.hide(nse)
+		}
+		return
+	}
+
+	delete(s.targets, t.nse.Name)
+	nextServer := t.nextServer
+	s.lock.Unlock()
+
+	// Unregister event is sent to the watchers by the memory
+	go func() {
+		if _, err := nextServer.Unregister(s.ctx, nse); err != nil {
+			logger.Errorf("failed to unregister unhealthy NSE: %s %s", nse.Name, err.Error())
+		}
+	}()
+}
+
+func (s *healthCheckNSEServer) watchersList() (watchers []*healthCheckFindServer) {
+	for w := range s.watchers {
+		watchers = append(watchers, w)
+	}
+	return watchers
+}
+
+// healthCheckFindServer filters the unhealthy NSEs. Send calls are serialized with the events sent on the NSE health
+// changes, its lock is always taken before the server lock.
+type healthCheckFindServer struct {
+	registry.NetworkServiceEndpointRegistry_FindServer
+	server *healthCheckNSEServer
+
+	lock    sync.Mutex
+	visible map[string]bool
+}
+
+func (s *healthCheckFindServer) Send(nse *registry.NetworkServiceEndpoint) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// Unregister events are always sent, so the watchers don't keep the unhealthy NSEs
+	isUnregister := nse.ExpirationTime != nil && nse.ExpirationTime.Seconds < 0
+	if !isUnregister && s.server.isUnhealthy(nse.Name) {
+		// NSE is sent once it is healthy again
+		s.visible[nse.Name] = false
+		return nil
+	}
+
+	if isUnregister {
+		delete(s.visible, nse.Name)
+	} else {
+		s.visible[nse.Name] = true
+	}
+	return s.NetworkServiceEndpointRegistry_FindServer.Send(nse)
+}
+
+// hide sends the unregister event for the unhealthy NSE, if it has been sent to the watcher
+func (s *healthCheckFindServer) hide(nse *registry.NetworkServiceEndpoint) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if visible, ok := s.visible[nse.Name]; !ok || !visible {
+		return
+	}
+	s.visible[nse.Name] = false
+
+	nse = nse.Clone()
+	nse.ExpirationTime = &timestamp.Timestamp{
+		Seconds: -1,
+	}
+	_ = s.NetworkServiceEndpointRegistry_FindServer.Send(nse)
+}
+
+// show sends the NSE healthy again, if it has been hidden from the watcher
+func (s *healthCheckFindServer) show(nse *registry.NetworkServiceEndpoint) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if visible, ok := s.visible[nse.Name]; !ok || visible {
+		return
+	}
+	s.visible[nse.Name] = true
+
+	_ = s.NetworkServiceEndpointRegistry_FindServer.Send(nse)
+}
@@ -0,0 +1,298 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck_test
+
+import (
+	"context"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/healthcheck"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
+)
+
+const interval = time.Second
+
+func serveHealth(ctx context.Context, t *testing.T, healthServer grpc_health_v1.HealthServer) *url.URL {
+	server := grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+
+	u := &url.URL{Scheme: "tcp", Host: "127.0.0.1:0"}
+	require.Len(t, grpcutils.ListenAndServe(ctx, u, server), 0)
+
+	return u
+}
+
+func findNSEs(ctx context.Context, t *testing.T, server registry.NetworkServiceEndpointRegistryServer) (names []string) {
+	stream, err := adapters.NetworkServiceEndpointServerToClient(server).Find(ctx, &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: new(registry.NetworkServiceEndpoint),
+	})
+	require.NoError(t, err)
+
+	for _, nse := range registry.ReadNetworkServiceEndpointList(stream) {
+		names = append(names, nse.Name)
+	}
+	return names
+}
+
+func TestHealthCheckNSEServer_ShouldFilterUnhealthy(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	clockMock.Set(time.Now())
+	ctx = clock.WithClock(ctx, clockMock)
+
+	healthServer := health.NewServer()
+	u := serveHealth(ctx, t, healthServer)
+
+	server := next.NewNetworkServiceEndpointRegistryServer(
+		healthcheck.NewNetworkServiceEndpointRegistryServer(ctx,
+			healthcheck.WithInterval(interval),
+			healthcheck.WithFailureThreshold(2),
+			healthcheck.WithDialOptions(grpc.WithInsecure()),
+		),
+		memory.NewNetworkServiceEndpointRegistryServer(),
+	)
+
+	_, err := server.Register(ctx, &registry.NetworkServiceEndpoint{Name: "nse", Url: u.String()})
+	require.NoError(t, err)
+
+	_, err = server.Register(ctx, &registry.NetworkServiceEndpoint{Name: "nse-dead", Url: "tcp://127.0.0.1:1"})
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, []string{"nse", "nse-dead"}, findNSEs(ctx, t, server))
+
+	// 1. Dead NSE is filtered after the failed probes
+	require.Eventually(t, func() bool {
+		clockMock.Add(interval)
+		names := findNSEs(ctx, t, server)
+		return len(names) == 1 && names[0] == "nse"
+	}, time.Second, 10*time.Millisecond)
+
+	// 2. NSE stops serving
+	healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+
+	require.Eventually(t, func() bool {
+		clockMock.Add(interval)
+		return len(findNSEs(ctx, t, server)) == 0
+	}, time.Second, 10*time.Millisecond)
+
+	// 3. NSE is back to serving
+	healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
+
+	require.Eventually(t, func() bool {
+		clockMock.Add(interval)
+		return len(findNSEs(ctx, t, server)) == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestHealthCheckNSEServer_ShouldNotifyWatchers(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	clockMock.Set(time.Now())
+	ctx = clock.WithClock(ctx, clockMock)
+
+	healthServer := health.NewServer()
+	u := serveHealth(ctx, t, healthServer)
+
+	server := next.NewNetworkServiceEndpointRegistryServer(
+		healthcheck.NewNetworkServiceEndpointRegistryServer(ctx,
+			healthcheck.WithInterval(interval),
+			healthcheck.WithFailureThreshold(1),
+			healthcheck.WithDialOptions(grpc.WithInsecure()),
+		),
+		memory.NewNetworkServiceEndpointRegistryServer(),
+	)
+
+	_, err := server.Register(ctx, &registry.NetworkServiceEndpoint{Name: "nse", Url: u.String()})
+	require.NoError(t, err)
+
+	stream, err := adapters.NetworkServiceEndpointServerToClient(server).Find(ctx, &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: new(registry.NetworkServiceEndpoint),
+		Watch:                  true,
+	})
+	require.NoError(t, err)
+
+	nse, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, "nse", nse.Name)
+
+	events := make(chan *registry.NetworkServiceEndpoint, 10)
+	go func() {
+		defer close(events)
+		for {
+			nse, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			events <- nse
+		}
+	}()
+
+	requireEvent := func() *registry.NetworkServiceEndpoint {
+		timeout := time.After(time.Second)
+		for {
+			select {
+			case nse := <-events:
+				return nse
+			case <-time.After(10 * time.Millisecond):
+				clockMock.Add(interval)
+			case <-timeout:
+				require.FailNow(t, "no event received")
+			}
+		}
+	}
+
+	// 1. Watcher gets the unregister event for the unhealthy NSE
+	healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+
+	nse = requireEvent()
+	require.Equal(t, "nse", nse.Name)
+	require.Less(t, nse.ExpirationTime.Seconds, int64(0))
+
+	// 2. Watcher gets the NSE healthy again
+	healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
+
+	nse = requireEvent()
+	require.Equal(t, "nse", nse.Name)
+	require.Nil(t, nse.ExpirationTime)
+
+	cancel()
+	for range events {
+		// Wait for the stream to be closed
+	}
+}
+
+func TestHealthCheckNSEServer_ShouldUnregisterUnhealthy(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	clockMock.Set(time.Now())
+	ctx = clock.WithClock(ctx, clockMock)
+
+	mem := memory.NewNetworkServiceEndpointRegistryServer()
+	server := next.NewNetworkServiceEndpointRegistryServer(
+		healthcheck.NewNetworkServiceEndpointRegistryServer(ctx,
+			healthcheck.WithInterval(interval),
+			healthcheck.WithUnregister(),
+			healthcheck.WithDialOptions(grpc.WithInsecure()),
+		),
+		mem,
+	)
+
+	_, err := server.Register(ctx, &registry.NetworkServiceEndpoint{Name: "nse-dead", Url: "tcp://127.0.0.1:1"})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		clockMock.Add(interval)
+		return len(findNSEs(ctx, t, mem)) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+type blockingHealthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	current, max int32
+	release      chan struct{}
+}
+
+func (s *blockingHealthServer) Check(ctx context.Context, _ *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	current := atomic.AddInt32(&s.current, 1)
+	defer atomic.AddInt32(&s.current, -1)
+
+	for max := atomic.LoadInt32(&s.max); current > max; max = atomic.LoadInt32(&s.max) {
+		if atomic.CompareAndSwapInt32(&s.max, max, current) {
+			break
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-s.release:
+	}
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+func TestHealthCheckNSEServer_ShouldLimitConcurrency(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	clockMock.Set(time.Now())
+	ctx = clock.WithClock(ctx, clockMock)
+
+	healthServer := &blockingHealthServer{release: make(chan struct{})}
+	u := serveHealth(ctx, t, healthServer)
+
+	server := next.NewNetworkServiceEndpointRegistryServer(
+		healthcheck.NewNetworkServiceEndpointRegistryServer(ctx,
+			healthcheck.WithInterval(interval),
+			healthcheck.WithTimeout(time.Hour),
+			healthcheck.WithMaxConcurrency(2),
+			healthcheck.WithDialOptions(grpc.WithInsecure()),
+		),
+		memory.NewNetworkServiceEndpointRegistryServer(),
+	)
+
+	for _, name := range []string{"nse-1", "nse-2", "nse-3", "nse-4", "nse-5"} {
+		_, err := server.Register(ctx, &registry.NetworkServiceEndpoint{Name: name, Url: u.String()})
+		require.NoError(t, err)
+	}
+
+	clockMock.Add(interval)
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&healthServer.current) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	// Next ticks don't start the new probes for the NSEs being probed
+	clockMock.Add(interval)
+
+	for i := 0; i < 5; i++ {
+		healthServer.release <- struct{}{}
+	}
+
+	require.Equal(t, int32(2), atomic.LoadInt32(&healthServer.max))
+	require.Len(t, findNSEs(ctx, t, server), 5)
+}
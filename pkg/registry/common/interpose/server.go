@@ -26,6 +26,7 @@ import (
 
 	"github.com/networkservicemesh/api/pkg/api/registry"
 
+	"github.com/networkservicemesh/sdk/pkg/registry/common/setid"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
 	"github.com/networkservicemesh/sdk/pkg/tools/stringurl"
 )
@@ -47,7 +48,7 @@ func (s *interposeRegistryServer) Register(ctx context.Context, nse *registry.Ne
 		return next.NetworkServiceEndpointRegistryServer(ctx).Register(ctx, nse)
 	}
 
-	if _, ok := s.interposeURLs.Load(nse.Name); !ok {
+	if _, ok := s.interposeURLs.Load(nse.Name); !ok && !setid.IsRegisteredName(ctx) {
 		nse.Name = interposeName(uuid.New().String())
 	}
 
@@ -1,4 +1,4 @@
-// Copyright (c) 2020 Doc.ai and/or its affiliates.
+// Copyright (c) 2020-2021 Doc.ai and/or its affiliates.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -16,4 +16,7 @@
 
 package memory
 
-const defaultEventChannelSize = 10
+const (
+	defaultEventChannelSize = 10
+	defaultHistorySize      = 1000
+)
@@ -0,0 +1,84 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/revision"
+)
+
+// event is a registry event with its revision
+type event struct {
+	revision uint64
+	entity   proto.Message
+}
+
+// history is a bounded history of the registry events, it is not thread safe and should be used only in the
+// executor. Epoch is set on creation and never changes, so it can be read outside the executor.
+type history struct {
+	epoch    uint64
+	revision uint64
+	size     int
+	events   []*event
+}
+
+func newHistory(size int) history {
+	return history{
+		// Start time is unique for every registry run, so the revisions of the previous run are not resumed
+		epoch: uint64(time.Now().UnixNano()),
+		size:  size,
+	}
+}
+
+// revisionOf returns the revision of the event in the history epoch
+func (h *history) revisionOf(e *event) revision.Revision {
+	return revision.Revision{
+		Epoch: h.epoch,
+		Index: e.revision,
+	}
+}
+
+// add assigns a new revision for the entity and stores the event in the history
+func (h *history) add(entity proto.Message) *event {
+	h.revision++
+	e := &event{
+		revision: h.revision,
+		entity:   entity,
+	}
+
+	if h.size <= 0 {
+		return e
+	}
+	if len(h.events) == h.size {
+		h.events = append(h.events[:0], h.events[1:]...)
+	}
+	h.events = append(h.events, e)
+
+	return e
+}
+
+// since returns the events with the revisions greater than the given one, revision.ErrTooOld if some of them are
+// already compacted or the revision is from the other epoch
+func (h *history) since(rev revision.Revision) ([]*event, error) {
+	if rev.Epoch != h.epoch || rev.Index > h.revision || h.revision-rev.Index > uint64(len(h.events)) {
+		return nil, revision.ErrTooOld
+	}
+	return h.events[uint64(len(h.events))-(h.revision-rev.Index):], nil
+}
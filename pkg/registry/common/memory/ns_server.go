@@ -23,25 +23,31 @@ import (
 	"github.com/edwarnicke/serialize"
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/networkservicemesh/api/pkg/api/registry"
 
 	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
 	"github.com/networkservicemesh/sdk/pkg/tools/matchutils"
+	"github.com/networkservicemesh/sdk/pkg/tools/revision"
 )
 
 type memoryNSServer struct {
 	networkServices  NetworkServiceSyncMap
 	executor         serialize.Executor
-	eventChannels    map[string]chan *registry.NetworkService
+	watchers         map[string]*watcher
 	eventChannelSize int
+	overflowPolicy   OverflowPolicy
+	history          history
 }
 
 // NewNetworkServiceRegistryServer creates new memory based NetworkServiceRegistryServer
 func NewNetworkServiceRegistryServer(options ...Option) registry.NetworkServiceRegistryServer {
 	s := &memoryNSServer{
 		eventChannelSize: defaultEventChannelSize,
-		eventChannels:    make(map[string]chan *registry.NetworkService),
+		history:          newHistory(defaultHistorySize),
+		overflowPolicy:   CoalescePolicy(),
+		watchers:         make(map[string]*watcher),
 	}
 	for _, o := range options {
 		o.apply(s)
@@ -53,6 +59,14 @@ func (s *memoryNSServer) setEventChannelSize(l int) {
 	s.eventChannelSize = l
 }
 
+func (s *memoryNSServer) setHistorySize(l int) {
+	s.history.size = l
+}
+
+func (s *memoryNSServer) setOverflowPolicy(p OverflowPolicy) {
+	s.overflowPolicy = p
+}
+
 func (s *memoryNSServer) Register(ctx context.Context, ns *registry.NetworkService) (*registry.NetworkService, error) {
 	r, err := next.NetworkServiceRegistryServer(ctx).Register(ctx, ns)
 	if err != nil {
@@ -66,18 +80,26 @@ func (s *memoryNSServer) Register(ctx context.Context, ns *registry.NetworkServi
 	return r, nil
 }
 
-func (s *memoryNSServer) sendEvent(event *registry.NetworkService) {
-	event = event.Clone()
+func (s *memoryNSServer) sendEvent(entity *registry.NetworkService) {
+	entity = entity.Clone()
 	s.executor.AsyncExec(func() {
-		for _, ch := range s.eventChannels {
-			ch <- event.Clone()
+		e := s.history.add(entity)
+		for id, w := range s.watchers {
+			if !w.push(e) {
+				delete(s.watchers, id)
+			}
 		}
 	})
 }
 
 func (s *memoryNSServer) Find(query *registry.NetworkServiceQuery, server registry.NetworkServiceRegistry_FindServer) error {
+	match, err := matchutils.NewNetworkServiceMatcher(matchutils.ModeFromContext(server.Context()), query.NetworkService)
+	if err != nil {
+		return err
+	}
+
 	if !query.Watch {
-		for _, ns := range s.allMatches(query) {
+		for _, ns := range s.allMatches(match) {
 			if err := server.Send(ns); err != nil {
 				return err
 			}
@@ -85,19 +107,51 @@ func (s *memoryNSServer) Find(query *registry.NetworkServiceQuery, server regist
 		return next.NetworkServiceRegistryServer(server.Context()).Find(query, server)
 	}
 
-	eventCh := make(chan *registry.NetworkService, s.eventChannelSize)
+	watch, err := revision.FromContext(server.Context())
+	if err != nil {
+		return err
+	}
+
+	w := newWatcher(query, s.eventChannelSize, s.overflowPolicy, func(entity proto.Message) bool {
+		return match(entity.(*registry.NetworkService))
+	})
 	id := uuid.New().String()
 
 	s.executor.AsyncExec(func() {
-		s.eventChannels[id] = eventCh
-		for _, entity := range s.allMatches(query) {
-			eventCh <- entity
+		var events []*event
+		if watch != nil && watch.Start != nil {
+			var historyErr error
+			if events, historyErr = s.history.since(*watch.Start); historyErr != nil {
+				w.fail(historyErr)
+				return
+			}
+		} else {
+			for _, entity := range s.allMatches(match) {
+				events = append(events, &event{
+					revision: s.history.revision,
+					entity:   entity,
+				})
+			}
 		}
+
+		w.init(s.history.revision, events)
+		s.watchers[id] = w
 	})
-	defer s.closeEventChannel(id, eventCh)
+	defer s.closeWatcher(id, w)
+
+	// Dropped watcher should be closed even if it is stuck on sending
+	errCh := make(chan error, 1)
+	go func() {
+		var err error
+		for ; err == nil; err = s.receiveEvent(watch, server, w) {
+		}
+		errCh <- err
+	}()
 
-	var err error
-	for ; err == nil; err = s.receiveEvent(query, server, eventCh) {
+	select {
+	case err = <-errCh:
+	case <-w.dropped:
+		err = ErrResync
 	}
 	if err != io.EOF {
 		return err
@@ -105,9 +159,9 @@ func (s *memoryNSServer) Find(query *registry.NetworkServiceQuery, server regist
 	return next.NetworkServiceRegistryServer(server.Context()).Find(query, server)
 }
 
-func (s *memoryNSServer) allMatches(query *registry.NetworkServiceQuery) (matches []*registry.NetworkService) {
+func (s *memoryNSServer) allMatches(match func(*registry.NetworkService) bool) (matches []*registry.NetworkService) {
 	s.networkServices.Range(func(_ string, ns *registry.NetworkService) bool {
-		if matchutils.MatchNetworkServices(query.NetworkService, ns) {
+		if match(ns) {
 			matches = append(matches, ns.Clone())
 		}
 		return true
@@ -115,42 +169,46 @@ func (s *memoryNSServer) allMatches(query *registry.NetworkServiceQuery) (matche
 	return matches
 }
 
-func (s *memoryNSServer) closeEventChannel(id string, eventCh <-chan *registry.NetworkService) {
-	ctx, cancel := context.WithCancel(context.Background())
-
+func (s *memoryNSServer) closeWatcher(id string, w *watcher) {
+	w.fail(io.EOF)
 	s.executor.AsyncExec(func() {
-		delete(s.eventChannels, id)
-		cancel()
+		delete(s.watchers, id)
 	})
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-eventCh:
-		}
-	}
 }
 
 func (s *memoryNSServer) receiveEvent(
-	query *registry.NetworkServiceQuery,
+	watch *revision.Watch,
 	server registry.NetworkServiceRegistry_FindServer,
-	eventCh <-chan *registry.NetworkService,
+	w *watcher,
 ) error {
-	select {
-	case <-server.Context().Done():
-		return io.EOF
-	case event := <-eventCh:
-		if matchutils.MatchNetworkServices(query.NetworkService, event) {
-			if err := server.Send(event); err != nil {
-				if server.Context().Err() != nil {
-					return io.EOF
-				}
-				return err
-			}
+	e, err := w.pop(server.Context())
+	if err != nil {
+		return err
+	}
+
+	entity := proto.Clone(e.entity).(*registry.NetworkService)
+	if watch != nil {
+		revision.Set(entity, s.history.revisionOf(e))
+	}
+	if err := server.Send(entity); err != nil {
+		if server.Context().Err() != nil {
+			return io.EOF
 		}
-		return nil
+		return err
 	}
+	w.ack(e)
+	return nil
+}
+
+// WatchersStats returns the statistics of the active watchers by their IDs
+func (s *memoryNSServer) WatchersStats() map[string]WatcherStats {
+	stats := make(map[string]WatcherStats)
+	<-s.executor.AsyncExec(func() {
+		for id, w := range s.watchers {
+			stats[id] = w.stats()
+		}
+	})
+	return stats
 }
 
 func (s *memoryNSServer) Unregister(ctx context.Context, ns *registry.NetworkService) (*empty.Empty, error) {
@@ -24,25 +24,31 @@ import (
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/networkservicemesh/api/pkg/api/registry"
 
 	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
 	"github.com/networkservicemesh/sdk/pkg/tools/matchutils"
+	"github.com/networkservicemesh/sdk/pkg/tools/revision"
 )
 
 type memoryNSEServer struct {
 	networkServiceEndpoints NetworkServiceEndpointSyncMap
 	executor                serialize.Executor
-	eventChannels           map[string]chan *registry.NetworkServiceEndpoint
+	watchers                map[string]*watcher
 	eventChannelSize        int
+	overflowPolicy          OverflowPolicy
+	history                 history
 }
 
 // NewNetworkServiceEndpointRegistryServer creates new memory based NetworkServiceEndpointRegistryServer
 func NewNetworkServiceEndpointRegistryServer(options ...Option) registry.NetworkServiceEndpointRegistryServer {
 	s := &memoryNSEServer{
 		eventChannelSize: defaultEventChannelSize,
-		eventChannels:    make(map[string]chan *registry.NetworkServiceEndpoint),
+		history:          newHistory(defaultHistorySize),
+		overflowPolicy:   CoalescePolicy(),
+		watchers:         make(map[string]*watcher),
 	}
 	for _, o := range options {
 		o.apply(s)
@@ -54,6 +60,14 @@ func (s *memoryNSEServer) setEventChannelSize(l int) {
 	s.eventChannelSize = l
 }
 
+func (s *memoryNSEServer) setHistorySize(l int) {
+	s.history.size = l
+}
+
+func (s *memoryNSEServer) setOverflowPolicy(p OverflowPolicy) {
+	s.overflowPolicy = p
+}
+
 func (s *memoryNSEServer) Register(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*registry.NetworkServiceEndpoint, error) {
 	r, err := next.NetworkServiceEndpointRegistryServer(ctx).Register(ctx, nse)
 	if err != nil {
@@ -67,18 +81,26 @@ func (s *memoryNSEServer) Register(ctx context.Context, nse *registry.NetworkSer
 	return r, err
 }
 
-func (s *memoryNSEServer) sendEvent(event *registry.NetworkServiceEndpoint) {
-	event = event.Clone()
+func (s *memoryNSEServer) sendEvent(entity *registry.NetworkServiceEndpoint) {
+	entity = entity.Clone()
 	s.executor.AsyncExec(func() {
-		for _, ch := range s.eventChannels {
-			ch <- event.Clone()
+		e := s.history.add(entity)
+		for id, w := range s.watchers {
+			if !w.push(e) {
+				delete(s.watchers, id)
+			}
 		}
 	})
 }
 
 func (s *memoryNSEServer) Find(query *registry.NetworkServiceEndpointQuery, server registry.NetworkServiceEndpointRegistry_FindServer) error {
+	match, err := matchutils.NewNetworkServiceEndpointMatcher(matchutils.ModeFromContext(server.Context()), query.NetworkServiceEndpoint)
+	if err != nil {
+		return err
+	}
+
 	if !query.Watch {
-		for _, ns := range s.allMatches(query) {
+		for _, ns := range s.allMatches(match) {
 			if err := server.Send(ns); err != nil {
 				return err
 			}
@@ -86,19 +108,51 @@ func (s *memoryNSEServer) Find(query *registry.NetworkServiceEndpointQuery, serv
 		return next.NetworkServiceEndpointRegistryServer(server.Context()).Find(query, server)
 	}
 
-	eventCh := make(chan *registry.NetworkServiceEndpoint, s.eventChannelSize)
+	watch, err := revision.FromContext(server.Context())
+	if err != nil {
+		return err
+	}
+
+	w := newWatcher(query, s.eventChannelSize, s.overflowPolicy, func(entity proto.Message) bool {
+		return match(entity.(*registry.NetworkServiceEndpoint))
+	})
 	id := uuid.New().String()
 
 	s.executor.AsyncExec(func() {
-		s.eventChannels[id] = eventCh
-		for _, entity := range s.allMatches(query) {
-			eventCh <- entity
+		var events []*event
+		if watch != nil && watch.Start != nil {
+			var historyErr error
+			if events, historyErr = s.history.since(*watch.Start); historyErr != nil {
+				w.fail(historyErr)
+				return
+			}
+		} else {
+			for _, entity := range s.allMatches(match) {
+				events = append(events, &event{
+					revision: s.history.revision,
+					entity:   entity,
+				})
+			}
 		}
+
+		w.init(s.history.revision, events)
+		s.watchers[id] = w
 	})
-	defer s.closeEventChannel(id, eventCh)
+	defer s.closeWatcher(id, w)
+
+	// Dropped watcher should be closed even if it is stuck on sending
+	errCh := make(chan error, 1)
+	go func() {
+		var err error
+		for ; err == nil; err = s.receiveEvent(watch, server, w) {
+		}
+		errCh <- err
+	}()
 
-	var err error
-	for ; err == nil; err = s.receiveEvent(query, server, eventCh) {
+	select {
+	case err = <-errCh:
+	case <-w.dropped:
+		err = ErrResync
 	}
 	if err != io.EOF {
 		return err
@@ -106,9 +160,9 @@ func (s *memoryNSEServer) Find(query *registry.NetworkServiceEndpointQuery, serv
 	return next.NetworkServiceEndpointRegistryServer(server.Context()).Find(query, server)
 }
 
-func (s *memoryNSEServer) allMatches(query *registry.NetworkServiceEndpointQuery) (matches []*registry.NetworkServiceEndpoint) {
+func (s *memoryNSEServer) allMatches(match func(*registry.NetworkServiceEndpoint) bool) (matches []*registry.NetworkServiceEndpoint) {
 	s.networkServiceEndpoints.Range(func(_ string, nse *registry.NetworkServiceEndpoint) bool {
-		if matchutils.MatchNetworkServiceEndpoints(query.NetworkServiceEndpoint, nse) {
+		if match(nse) {
 			matches = append(matches, nse.Clone())
 		}
 		return true
@@ -116,42 +170,46 @@ func (s *memoryNSEServer) allMatches(query *registry.NetworkServiceEndpointQuery
 	return matches
 }
 
-func (s *memoryNSEServer) closeEventChannel(id string, eventCh <-chan *registry.NetworkServiceEndpoint) {
-	ctx, cancel := context.WithCancel(context.Background())
-
+func (s *memoryNSEServer) closeWatcher(id string, w *watcher) {
+	w.fail(io.EOF)
 	s.executor.AsyncExec(func() {
-		delete(s.eventChannels, id)
-		cancel()
+		delete(s.watchers, id)
 	})
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-eventCh:
-		}
-	}
 }
 
 func (s *memoryNSEServer) receiveEvent(
-	query *registry.NetworkServiceEndpointQuery,
+	watch *revision.Watch,
 	server registry.NetworkServiceEndpointRegistry_FindServer,
-	eventCh <-chan *registry.NetworkServiceEndpoint,
+	w *watcher,
 ) error {
-	select {
-	case <-server.Context().Done():
-		return io.EOF
-	case event := <-eventCh:
-		if matchutils.MatchNetworkServiceEndpoints(query.NetworkServiceEndpoint, event) {
-			if err := server.Send(event); err != nil {
-				if server.Context().Err() != nil {
-					return io.EOF
-				}
-				return err
-			}
+	e, err := w.pop(server.Context())
+	if err != nil {
+		return err
+	}
+
+	entity := proto.Clone(e.entity).(*registry.NetworkServiceEndpoint)
+	if watch != nil {
+		revision.Set(entity, s.history.revisionOf(e))
+	}
+	if err := server.Send(entity); err != nil {
+		if server.Context().Err() != nil {
+			return io.EOF
 		}
-		return nil
+		return err
 	}
+	w.ack(e)
+	return nil
+}
+
+// WatchersStats returns the statistics of the active watchers by their IDs
+func (s *memoryNSEServer) WatchersStats() map[string]WatcherStats {
+	stats := make(map[string]WatcherStats)
+	<-s.executor.AsyncExec(func() {
+		for id, w := range s.watchers {
+			stats[id] = w.stats()
+		}
+	})
+	return stats
 }
 
 func (s *memoryNSEServer) Unregister(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*empty.Empty, error) {
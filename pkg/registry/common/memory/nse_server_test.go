@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sort"
 	"sync"
 	"testing"
 	"time"
@@ -33,6 +34,10 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/streamchannel"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+	"github.com/networkservicemesh/sdk/pkg/tools/matchutils"
+	"github.com/networkservicemesh/sdk/pkg/tools/revision"
 )
 
 func TestNetworkServiceEndpointRegistryServer_RegisterAndFind(t *testing.T) {
@@ -439,3 +444,321 @@ func receiveNSE(ctx context.Context, ch <-chan *registry.NetworkServiceEndpoint)
 		return nse, nil
 	}
 }
+
+func TestNetworkServiceEndpointRegistryServer_FindWithMatchMode(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+	s := next.NewNetworkServiceEndpointRegistryServer(memory.NewNetworkServiceEndpointRegistryServer())
+
+	for _, nse := range []*registry.NetworkServiceEndpoint{
+		{Name: "icmp", Url: "tcp://1.1.1.1:5000"},
+		{Name: "icmp-responder", Url: "tcp://1.1.1.10:5000"},
+		{Name: "icmp-responder-v2", Url: "tcp://2.2.2.2:5000"},
+	} {
+		_, err := s.Register(context.Background(), nse)
+		require.NoError(t, err)
+	}
+
+	find := func(mode matchutils.Mode, query *registry.NetworkServiceEndpoint) (names []string, err error) {
+		ctx, cancel := context.WithCancel(matchutils.WithMode(context.Background(), mode))
+		defer cancel()
+
+		ch := make(chan *registry.NetworkServiceEndpoint, 10)
+		err = s.Find(&registry.NetworkServiceEndpointQuery{
+			NetworkServiceEndpoint: query,
+		}, streamchannel.NewNetworkServiceEndpointFindServer(ctx, ch))
+		close(ch)
+
+		for nse := range ch {
+			names = append(names, nse.Name)
+		}
+		sort.Strings(names)
+		return names, err
+	}
+
+	for _, sample := range []struct {
+		mode     matchutils.Mode
+		query    *registry.NetworkServiceEndpoint
+		expected []string
+	}{
+		{matchutils.SubstringMode, &registry.NetworkServiceEndpoint{Name: "icmp"}, []string{"icmp", "icmp-responder", "icmp-responder-v2"}},
+		{matchutils.ExactMode, &registry.NetworkServiceEndpoint{Name: "icmp"}, []string{"icmp"}},
+		{matchutils.PrefixMode, &registry.NetworkServiceEndpoint{Name: "icmp-"}, []string{"icmp-responder", "icmp-responder-v2"}},
+		{matchutils.GlobMode, &registry.NetworkServiceEndpoint{Name: "icmp-*-v?"}, []string{"icmp-responder-v2"}},
+		{matchutils.RegexMode, &registry.NetworkServiceEndpoint{Name: "^icmp(-responder)?$"}, []string{"icmp", "icmp-responder"}},
+		{matchutils.ExactMode, &registry.NetworkServiceEndpoint{Url: "tcp://1.1.1.1:5000"}, []string{"icmp"}},
+		{matchutils.GlobMode, &registry.NetworkServiceEndpoint{Url: "tcp://1.1.1.1*"}, []string{"icmp", "icmp-responder"}},
+	} {
+		names, err := find(sample.mode, sample.query)
+		require.NoError(t, err)
+		require.Equal(t, sample.expected, names, "%s: %v", sample.mode, sample.query)
+	}
+
+	_, err := find(matchutils.RegexMode, &registry.NetworkServiceEndpoint{Name: "icmp("})
+	require.Error(t, err)
+}
+
+func TestNetworkServiceEndpointRegistryServer_WatchFromRevision(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	s := memory.NewNetworkServiceEndpointRegistryServer(memory.WithHistorySize(2))
+
+	_, err := s.Register(ctx, &registry.NetworkServiceEndpoint{Name: "nse-1"})
+	require.NoError(t, err)
+
+	watch := func(ctx context.Context) (<-chan *registry.NetworkServiceEndpoint, <-chan error) {
+		ch := make(chan *registry.NetworkServiceEndpoint, 10)
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- s.Find(&registry.NetworkServiceEndpointQuery{
+				NetworkServiceEndpoint: new(registry.NetworkServiceEndpoint),
+				Watch:                  true,
+			}, streamchannel.NewNetworkServiceEndpointFindServer(ctx, ch))
+		}()
+		return ch, errCh
+	}
+
+	// 1. Relist with revisions
+	findCtx, findCancel := context.WithCancel(ctx)
+	ch, errCh := watch(revision.WithRelist(findCtx))
+
+	nse, err := receiveNSE(ctx, ch)
+	require.NoError(t, err)
+	require.Equal(t, "nse-1", nse.Name)
+
+	rev, ok := revision.Get(nse)
+	require.True(t, ok)
+
+	findCancel()
+	require.NoError(t, <-errCh)
+
+	// 2. Missed events
+	_, err = s.Register(ctx, &registry.NetworkServiceEndpoint{Name: "nse-2"})
+	require.NoError(t, err)
+
+	_, err = s.Unregister(ctx, &registry.NetworkServiceEndpoint{Name: "nse-1"})
+	require.NoError(t, err)
+
+	// 3. Resume from the revision
+	findCtx, findCancel = context.WithCancel(ctx)
+	ch, errCh = watch(revision.WithStart(findCtx, rev))
+
+	nse, err = receiveNSE(ctx, ch)
+	require.NoError(t, err)
+	require.Equal(t, "nse-2", nse.Name)
+
+	nextRev, ok := revision.Get(nse)
+	require.True(t, ok)
+	require.Equal(t, rev.Epoch, nextRev.Epoch)
+	require.Greater(t, nextRev.Index, rev.Index)
+
+	nse, err = receiveNSE(ctx, ch)
+	require.NoError(t, err)
+	require.Equal(t, "nse-1", nse.Name)
+	require.Equal(t, int64(-1), nse.ExpirationTime.Seconds)
+
+	findCancel()
+	require.NoError(t, <-errCh)
+
+	// 4. History is compacted
+	_, err = s.Register(ctx, &registry.NetworkServiceEndpoint{Name: "nse-3"})
+	require.NoError(t, err)
+
+	_, errCh = watch(revision.WithStart(ctx, rev))
+
+	err = <-errCh
+	require.Error(t, err)
+	require.True(t, revision.IsTooOld(err))
+}
+
+func TestNetworkServiceEndpointRegistryServer_WatchFromOtherEpoch(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	s := memory.NewNetworkServiceEndpointRegistryServer()
+
+	_, err := s.Register(ctx, &registry.NetworkServiceEndpoint{Name: "nse-1"})
+	require.NoError(t, err)
+
+	ch := make(chan *registry.NetworkServiceEndpoint, 10)
+	findCtx, findCancel := context.WithCancel(revision.WithRelist(ctx))
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.Find(&registry.NetworkServiceEndpointQuery{
+			NetworkServiceEndpoint: new(registry.NetworkServiceEndpoint),
+			Watch:                  true,
+		}, streamchannel.NewNetworkServiceEndpointFindServer(findCtx, ch))
+	}()
+
+	nse, err := receiveNSE(ctx, ch)
+	require.NoError(t, err)
+
+	rev, ok := revision.Get(nse)
+	require.True(t, ok)
+
+	findCancel()
+	require.NoError(t, <-errCh)
+
+	// Restarted registry has the same revision counter, but the other epoch
+	time.Sleep(time.Millisecond)
+	restarted := memory.NewNetworkServiceEndpointRegistryServer()
+
+	_, err = restarted.Register(ctx, &registry.NetworkServiceEndpoint{Name: "nse-2"})
+	require.NoError(t, err)
+
+	err = restarted.Find(&registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: new(registry.NetworkServiceEndpoint),
+		Watch:                  true,
+	}, streamchannel.NewNetworkServiceEndpointFindServer(revision.WithStart(ctx, rev), ch))
+	require.Error(t, err)
+	require.True(t, revision.IsTooOld(err))
+}
+
+func watchNSEs(ctx context.Context, s registry.NetworkServiceEndpointRegistryServer, size int) (<-chan *registry.NetworkServiceEndpoint, <-chan error) {
+	ch := make(chan *registry.NetworkServiceEndpoint, size)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.Find(&registry.NetworkServiceEndpointQuery{
+			NetworkServiceEndpoint: new(registry.NetworkServiceEndpoint),
+			Watch:                  true,
+		}, streamchannel.NewNetworkServiceEndpointFindServer(ctx, ch))
+	}()
+	return ch, errCh
+}
+
+func TestNetworkServiceEndpointRegistryServer_SlowWatcherIsDropped(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for name, policy := range map[string]memory.OverflowPolicy{
+		"drop":  memory.DropPolicy(),
+		"block": memory.BlockPolicy(ctx, 10*time.Millisecond),
+	} {
+		policy := policy
+		t.Run(name, func(t *testing.T) {
+			s := memory.NewNetworkServiceEndpointRegistryServer(
+				memory.WithEventChannelSize(2),
+				memory.WithOverflowPolicy(policy))
+
+			// Nobody reads from the slow watcher stream
+			_, slowErrCh := watchNSEs(ctx, s, 0)
+			ch, _ := watchNSEs(ctx, s, 0)
+
+			require.Eventually(t, func() bool {
+				return len(s.(memory.WatchersStatsProvider).WatchersStats()) == 2
+			}, 100*time.Millisecond, time.Millisecond)
+
+			for i := 0; i < 10; i++ {
+				_, err := s.Register(ctx, &registry.NetworkServiceEndpoint{Name: fmt.Sprintf("nse-%d", i)})
+				require.NoError(t, err)
+
+				nse, err := receiveNSE(ctx, ch)
+				require.NoError(t, err)
+				require.Equal(t, fmt.Sprintf("nse-%d", i), nse.Name)
+			}
+
+			select {
+			case err := <-slowErrCh:
+				require.Equal(t, memory.ErrResync, err)
+			case <-ctx.Done():
+				require.FailNow(t, "slow watcher is not dropped")
+			}
+			require.Len(t, s.(memory.WatchersStatsProvider).WatchersStats(), 1)
+		})
+	}
+}
+
+func TestNetworkServiceEndpointRegistryServer_SlowWatcherCoalesce(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	s := memory.NewNetworkServiceEndpointRegistryServer(
+		memory.WithEventChannelSize(1),
+		memory.WithOverflowPolicy(memory.CoalescePolicy()))
+
+	findCtx, findCancel := context.WithCancel(ctx)
+	defer findCancel()
+
+	ch, _ := watchNSEs(findCtx, s, 0)
+
+	require.Eventually(t, func() bool {
+		return len(s.(memory.WatchersStatsProvider).WatchersStats()) == 1
+	}, 100*time.Millisecond, time.Millisecond)
+
+	const count = 10
+	for i := 0; i < count; i++ {
+		_, err := s.Register(ctx, &registry.NetworkServiceEndpoint{
+			Name: "nse",
+			Url:  fmt.Sprintf("tcp://%d", i),
+		})
+		require.NoError(t, err)
+	}
+
+	// All the pending events are coalesced into the latest one
+	require.Eventually(t, func() bool {
+		for _, stats := range s.(memory.WatchersStatsProvider).WatchersStats() {
+			return stats.Queued <= 1 && stats.Lag > 0
+		}
+		return false
+	}, 100*time.Millisecond, time.Millisecond)
+
+	var received int
+	for {
+		nse, err := receiveNSE(ctx, ch)
+		require.NoError(t, err)
+		received++
+		if nse.Url == fmt.Sprintf("tcp://%d", count-1) {
+			break
+		}
+	}
+	require.LessOrEqual(t, received, 2)
+}
+
+func TestNetworkServiceEndpointRegistryServer_BlockPolicyClock(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+
+	s := memory.NewNetworkServiceEndpointRegistryServer(
+		memory.WithEventChannelSize(1),
+		memory.WithOverflowPolicy(memory.BlockPolicy(clock.WithClock(ctx, clockMock), time.Minute)))
+
+	// Nobody reads from the slow watcher stream
+	_, slowErrCh := watchNSEs(ctx, s, 0)
+
+	require.Eventually(t, func() bool {
+		return len(s.(memory.WatchersStatsProvider).WatchersStats()) == 1
+	}, 100*time.Millisecond, time.Millisecond)
+
+	registered := make(chan struct{})
+	go func() {
+		defer close(registered)
+		for i := 0; i < 3; i++ {
+			_, _ = s.Register(ctx, &registry.NetworkServiceEndpoint{Name: fmt.Sprintf("nse-%d", i)})
+		}
+	}()
+
+	// Fan-out is blocked until the timeout passes on the clock
+	require.Never(t, func() bool {
+		return len(slowErrCh) > 0
+	}, 50*time.Millisecond, 10*time.Millisecond)
+
+	require.Eventually(t, func() bool {
+		clockMock.Add(time.Minute)
+		return len(slowErrCh) > 0
+	}, 100*time.Millisecond, time.Millisecond)
+	require.Equal(t, memory.ErrResync, <-slowErrCh)
+
+	<-registered
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2020 Doc.ai and/or its affiliates.
+// Copyright (c) 2020-2021 Doc.ai and/or its affiliates.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -18,6 +18,8 @@ package memory
 
 type configurable interface {
 	setEventChannelSize(int)
+	setHistorySize(int)
+	setOverflowPolicy(OverflowPolicy)
 }
 
 // Option is memory registry configuration option
@@ -31,9 +33,24 @@ func (f applierFunc) apply(c configurable) {
 	f(c)
 }
 
-// WithEventChannelSize sets specific size of event channels
+// WithEventChannelSize sets specific size of the watcher event queues
 func WithEventChannelSize(l int) Option {
 	return applierFunc(func(c configurable) {
 		c.setEventChannelSize(l)
 	})
 }
+
+// WithHistorySize sets specific size of the event history used for the watches starting from the revision
+func WithHistorySize(l int) Option {
+	return applierFunc(func(c configurable) {
+		c.setHistorySize(l)
+	})
+}
+
+// WithOverflowPolicy sets the policy applied to the watchers with the full event queue, by default the events are
+// coalesced, so a slow watcher neither blocks the other ones nor is dropped
+func WithOverflowPolicy(p OverflowPolicy) Option {
+	return applierFunc(func(c configurable) {
+		c.setOverflowPolicy(p)
+	})
+}
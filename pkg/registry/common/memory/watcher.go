@@ -0,0 +1,270 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+)
+
+// ErrResync is returned to the watcher dropped because it is too slow to receive the events. Client should resume
+// the watch from the last received revision or relist.
+var ErrResync = status.Error(codes.ResourceExhausted, "watcher is too slow, resync is required")
+
+type overflowPolicyKind int
+
+const (
+	blockOverflowPolicy overflowPolicyKind = iota
+	dropOverflowPolicy
+	coalesceOverflowPolicy
+)
+
+// OverflowPolicy is a policy applied to the watcher with the full event queue
+type OverflowPolicy struct {
+	kind    overflowPolicyKind
+	timeout time.Duration
+	clock   clock.Clock
+}
+
+// DropPolicy drops the watcher with ErrResync
+func DropPolicy() OverflowPolicy {
+	return OverflowPolicy{kind: dropOverflowPolicy}
+}
+
+// CoalescePolicy keeps only the latest event per entity name in the queue, so the queue is limited by the number of
+// the entities instead of the queue size
+func CoalescePolicy() OverflowPolicy {
+	return OverflowPolicy{kind: coalesceOverflowPolicy}
+}
+
+// BlockPolicy blocks the events fan-out for the timeout waiting for the watcher and then drops it with ErrResync. The
+// timeout is measured with the clock from the ctx.
+func BlockPolicy(ctx context.Context, timeout time.Duration) OverflowPolicy {
+	return OverflowPolicy{kind: blockOverflowPolicy, timeout: timeout, clock: clock.FromContext(ctx)}
+}
+
+// WatcherStats is the statistics of the watcher
+type WatcherStats struct {
+	// Query is the query of the watch
+	Query proto.Message
+	// Queued is the number of the events waiting to be sent
+	Queued int
+	// Lag is the number of the revisions between the last sent and the last queued events, it includes the event
+	// being sent
+	Lag uint64
+}
+
+// WatchersStatsProvider is implemented by the memory registry servers
+type WatchersStatsProvider interface {
+	// WatchersStats returns the statistics of the active watchers by their IDs
+	WatchersStats() map[string]WatcherStats
+}
+
+type named interface {
+	GetName() string
+}
+
+// watcher is a per watch queue of the events, so the slow watcher cannot block the others
+type watcher struct {
+	query   proto.Message
+	size    int
+	policy  OverflowPolicy
+	match   func(proto.Message) bool
+	queue   []*event
+	queued  uint64
+	sent    uint64
+	err     error
+	ready   chan struct{}
+	space   chan struct{}
+	dropped chan struct{}
+	lock    sync.Mutex
+}
+
+func newWatcher(query proto.Message, size int, policy OverflowPolicy, match func(proto.Message) bool) *watcher {
+	if size < 1 {
+		size = 1
+	}
+	return &watcher{
+		query:   proto.Clone(query),
+		size:    size,
+		policy:  policy,
+		match:   match,
+		ready:   make(chan struct{}, 1),
+		space:   make(chan struct{}, 1),
+		dropped: make(chan struct{}),
+	}
+}
+
+// init sets the matching initial events regardless of the queue size
+func (w *watcher) init(rev uint64, events []*event) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.queued, w.sent = rev, rev
+	for _, e := range events {
+		if w.match(e.entity) {
+			w.queue = append(w.queue, e)
+		}
+	}
+	notify(w.ready)
+}
+
+// push adds the matching event to the queue applying the overflow policy, returns false if the watcher has been
+// dropped
+func (w *watcher) push(e *event) bool {
+	if !w.match(e.entity) {
+		return true
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.err != nil {
+		return false
+	}
+
+	if w.policy.kind == coalesceOverflowPolicy {
+		// Coalesced event is moved to the tail, so the revisions are still sent in the increasing order
+		if n, ok := e.entity.(named); ok {
+			for i := range w.queue {
+				if q, ok := w.queue[i].entity.(named); ok && q.GetName() == n.GetName() {
+					w.queue = append(w.queue[:i], w.queue[i+1:]...)
+					break
+				}
+			}
+		}
+		w.enqueue(e)
+		return true
+	}
+
+	if len(w.queue) >= w.size && w.policy.kind == blockOverflowPolicy {
+		timer := w.policy.clock.Timer(w.policy.timeout)
+		defer timer.Stop()
+
+		for len(w.queue) >= w.size && w.err == nil {
+			w.lock.Unlock()
+			select {
+			case <-w.space:
+			case <-timer.C():
+				w.lock.Lock()
+				return w.drop()
+			}
+			w.lock.Lock()
+		}
+		if w.err != nil {
+			return false
+		}
+	}
+
+	if len(w.queue) >= w.size {
+		return w.drop()
+	}
+
+	w.enqueue(e)
+	return true
+}
+
+func (w *watcher) enqueue(e *event) {
+	w.queue = append(w.queue, e)
+	w.queued = e.revision
+	notify(w.ready)
+}
+
+func (w *watcher) drop() bool {
+	if w.err == nil {
+		w.err = ErrResync
+		close(w.dropped)
+	}
+	notify(w.ready)
+	return false
+}
+
+// pop returns the next event waiting for it, io.EOF if the ctx is done
+func (w *watcher) pop(ctx context.Context) (*event, error) {
+	for {
+		w.lock.Lock()
+		if len(w.queue) > 0 {
+			e := w.queue[0]
+			w.queue = w.queue[1:]
+			w.lock.Unlock()
+
+			notify(w.space)
+			return e, nil
+		}
+		err := w.err
+		w.lock.Unlock()
+
+		if err != nil {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, io.EOF
+		case <-w.ready:
+		}
+	}
+}
+
+// ack marks the event as sent
+func (w *watcher) ack(e *event) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.sent = e.revision
+}
+
+// fail stops the watcher with the err after the queued events are sent
+func (w *watcher) fail(err error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.err == nil {
+		w.err = err
+	}
+	notify(w.ready)
+	notify(w.space)
+}
+
+func (w *watcher) stats() WatcherStats {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	stats := WatcherStats{
+		Query:  w.query,
+		Queued: len(w.queue),
+	}
+	if w.queued > w.sent {
+		stats.Lag = w.queued - w.sent
+	}
+	return stats
+}
+
+func notify(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
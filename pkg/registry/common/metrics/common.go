@@ -0,0 +1,105 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics provides registry chain elements recording the registry calls metrics: calls and errors by gRPC
+// code, calls latency and registry size. Use metrics.Handler from the tools to serve them.
+package metrics
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
+	"github.com/networkservicemesh/sdk/pkg/tools/metrics"
+)
+
+const (
+	serverSide = "server"
+	clientSide = "client"
+
+	networkServiceType         = "NetworkService"
+	networkServiceEndpointType = "NetworkServiceEndpoint"
+
+	registerMethod   = "Register"
+	findMethod       = "Find"
+	unregisterMethod = "Unregister"
+)
+
+// Metrics recorded by the chain elements
+const (
+	CallsMetric    = "nsm_registry_calls_total"
+	ErrorsMetric   = "nsm_registry_errors_total"
+	DurationMetric = "nsm_registry_call_duration_seconds"
+	SizeMetric     = "nsm_registry_size"
+)
+
+type recorder struct {
+	side, chainName, entityType string
+
+	calls    *metrics.CounterVec
+	errors   *metrics.CounterVec
+	duration *metrics.HistogramVec
+	size     *metrics.GaugeVec
+}
+
+func newRecorder(side, chainName, entityType string, options ...Option) *recorder {
+	o := &metricsOptions{
+		registry: metrics.Default(),
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+
+	return &recorder{
+		side:       side,
+		chainName:  chainName,
+		entityType: entityType,
+		calls: o.registry.Counter(CallsMetric, "Number of the registry calls by gRPC code",
+			"side", "chain", "type", "method", "network_service", "nse", "code"),
+		errors: o.registry.Counter(ErrorsMetric, "Number of the failed registry calls by gRPC code",
+			"side", "chain", "type", "method", "network_service", "nse", "code"),
+		duration: o.registry.Histogram(DurationMetric, "Duration of the registry calls in seconds, watches are not observed", o.buckets,
+			"side", "chain", "type", "method", "network_service", "nse"),
+		size: o.registry.Gauge(SizeMetric, "Number of the registered entities",
+			"chain", "type"),
+	}
+}
+
+// record records the call, duration is not observed for the watches
+func (r *recorder) record(ctx context.Context, method, networkService, nse string, start time.Time, watch bool, err error) {
+	code := grpcutils.UnwrapCode(err).String()
+
+	r.calls.With(r.side, r.chainName, r.entityType, method, networkService, nse, code).Inc()
+	if err != nil {
+		r.errors.With(r.side, r.chainName, r.entityType, method, networkService, nse, code).Inc()
+	}
+	if !watch {
+		r.duration.With(r.side, r.chainName, r.entityType, method, networkService, nse).Observe(clock.FromContext(ctx).Since(start).Seconds())
+	}
+}
+
+func (r *recorder) setSizeFunc(fn func() float64) {
+	r.size.With(r.chainName, r.entityType).SetFunc(fn)
+}
+
+func networkServicesLabel(networkServiceNames []string) string {
+	names := append([]string(nil), networkServiceNames...)
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
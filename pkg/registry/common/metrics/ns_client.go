@@ -0,0 +1,62 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+)
+
+type metricsNSClient struct {
+	*recorder
+}
+
+// NewNetworkServiceRegistryClient creates a NetworkServiceRegistryClient chain element recording the metrics labelled
+// by the chainName and Network Service. Find is recorded when the stream is opened.
+func NewNetworkServiceRegistryClient(chainName string, options ...Option) registry.NetworkServiceRegistryClient {
+	return &metricsNSClient{
+		recorder: newRecorder(clientSide, chainName, networkServiceType, options...),
+	}
+}
+
+func (c *metricsNSClient) Register(ctx context.Context, ns *registry.NetworkService, opts ...grpc.CallOption) (*registry.NetworkService, error) {
+	start := clock.FromContext(ctx).Now()
+	resp, err := next.NetworkServiceRegistryClient(ctx).Register(ctx, ns, opts...)
+	c.record(ctx, registerMethod, ns.GetName(), "", start, false, err)
+	return resp, err
+}
+
+func (c *metricsNSClient) Find(ctx context.Context, query *registry.NetworkServiceQuery, opts ...grpc.CallOption) (registry.NetworkServiceRegistry_FindClient, error) {
+	start := clock.FromContext(ctx).Now()
+	resp, err := next.NetworkServiceRegistryClient(ctx).Find(ctx, query, opts...)
+	c.record(ctx, findMethod, query.GetNetworkService().GetName(), "", start, query.GetWatch(), err)
+	return resp, err
+}
+
+func (c *metricsNSClient) Unregister(ctx context.Context, ns *registry.NetworkService, opts ...grpc.CallOption) (*empty.Empty, error) {
+	start := clock.FromContext(ctx).Now()
+	resp, err := next.NetworkServiceRegistryClient(ctx).Unregister(ctx, ns, opts...)
+	c.record(ctx, unregisterMethod, ns.GetName(), "", start, false, err)
+	return resp, err
+}
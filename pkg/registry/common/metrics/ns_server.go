@@ -0,0 +1,90 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"sync"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+)
+
+type metricsNSServer struct {
+	*recorder
+	nss  map[string]struct{}
+	lock sync.Mutex
+}
+
+// NewNetworkServiceRegistryServer creates a NetworkServiceRegistryServer chain element recording the metrics labelled
+// by the chainName and Network Service. Registry size counts the NSs registered through the element.
+func NewNetworkServiceRegistryServer(chainName string, options ...Option) registry.NetworkServiceRegistryServer {
+	s := &metricsNSServer{
+		recorder: newRecorder(serverSide, chainName, networkServiceType, options...),
+		nss:      make(map[string]struct{}),
+	}
+	s.setSizeFunc(s.registered)
+	return s
+}
+
+func (s *metricsNSServer) Register(ctx context.Context, ns *registry.NetworkService) (*registry.NetworkService, error) {
+	start := clock.FromContext(ctx).Now()
+	resp, err := next.NetworkServiceRegistryServer(ctx).Register(ctx, ns)
+	s.record(ctx, registerMethod, ns.GetName(), "", start, false, err)
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	s.nss[resp.GetName()] = struct{}{}
+	s.lock.Unlock()
+
+	return resp, nil
+}
+
+func (s *metricsNSServer) Find(query *registry.NetworkServiceQuery, server registry.NetworkServiceRegistry_FindServer) error {
+	start := clock.FromContext(server.Context()).Now()
+	err := next.NetworkServiceRegistryServer(server.Context()).Find(query, server)
+	s.record(server.Context(), findMethod, query.GetNetworkService().GetName(), "", start, query.GetWatch(), err)
+	return err
+}
+
+func (s *metricsNSServer) Unregister(ctx context.Context, ns *registry.NetworkService) (*empty.Empty, error) {
+	start := clock.FromContext(ctx).Now()
+	resp, err := next.NetworkServiceRegistryServer(ctx).Unregister(ctx, ns)
+	s.record(ctx, unregisterMethod, ns.GetName(), "", start, false, err)
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	delete(s.nss, ns.GetName())
+	s.lock.Unlock()
+
+	return resp, nil
+}
+
+func (s *metricsNSServer) registered() float64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return float64(len(s.nss))
+}
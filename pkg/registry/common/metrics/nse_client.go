@@ -0,0 +1,63 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+)
+
+type metricsNSEClient struct {
+	*recorder
+}
+
+// NewNetworkServiceEndpointRegistryClient creates a NetworkServiceEndpointRegistryClient chain element recording the
+// metrics labelled by the chainName, Network Services and NSE. Find is recorded when the stream is opened.
+func NewNetworkServiceEndpointRegistryClient(chainName string, options ...Option) registry.NetworkServiceEndpointRegistryClient {
+	return &metricsNSEClient{
+		recorder: newRecorder(clientSide, chainName, networkServiceEndpointType, options...),
+	}
+}
+
+func (c *metricsNSEClient) Register(ctx context.Context, nse *registry.NetworkServiceEndpoint, opts ...grpc.CallOption) (*registry.NetworkServiceEndpoint, error) {
+	start := clock.FromContext(ctx).Now()
+	resp, err := next.NetworkServiceEndpointRegistryClient(ctx).Register(ctx, nse, opts...)
+	c.record(ctx, registerMethod, networkServicesLabel(nse.GetNetworkServiceNames()), nse.GetName(), start, false, err)
+	return resp, err
+}
+
+func (c *metricsNSEClient) Find(ctx context.Context, query *registry.NetworkServiceEndpointQuery, opts ...grpc.CallOption) (registry.NetworkServiceEndpointRegistry_FindClient, error) {
+	start := clock.FromContext(ctx).Now()
+	resp, err := next.NetworkServiceEndpointRegistryClient(ctx).Find(ctx, query, opts...)
+	c.record(ctx, findMethod, networkServicesLabel(query.GetNetworkServiceEndpoint().GetNetworkServiceNames()),
+		query.GetNetworkServiceEndpoint().GetName(), start, query.GetWatch(), err)
+	return resp, err
+}
+
+func (c *metricsNSEClient) Unregister(ctx context.Context, nse *registry.NetworkServiceEndpoint, opts ...grpc.CallOption) (*empty.Empty, error) {
+	start := clock.FromContext(ctx).Now()
+	resp, err := next.NetworkServiceEndpointRegistryClient(ctx).Unregister(ctx, nse, opts...)
+	c.record(ctx, unregisterMethod, networkServicesLabel(nse.GetNetworkServiceNames()), nse.GetName(), start, false, err)
+	return resp, err
+}
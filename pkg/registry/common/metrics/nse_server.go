@@ -0,0 +1,106 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+)
+
+type metricsNSEServer struct {
+	*recorder
+	clock clock.Clock
+	nses  map[string]time.Time
+	lock  sync.Mutex
+}
+
+// NewNetworkServiceEndpointRegistryServer creates a NetworkServiceEndpointRegistryServer chain element recording the
+// metrics labelled by the chainName, Network Services and NSE. Registry size counts the NSEs registered through the
+// element and not expired yet.
+func NewNetworkServiceEndpointRegistryServer(ctx context.Context, chainName string, options ...Option) registry.NetworkServiceEndpointRegistryServer {
+	s := &metricsNSEServer{
+		recorder: newRecorder(serverSide, chainName, networkServiceEndpointType, options...),
+		clock:    clock.FromContext(ctx),
+		nses:     make(map[string]time.Time),
+	}
+	s.setSizeFunc(s.registered)
+	return s
+}
+
+func (s *metricsNSEServer) Register(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*registry.NetworkServiceEndpoint, error) {
+	start := clock.FromContext(ctx).Now()
+	resp, err := next.NetworkServiceEndpointRegistryServer(ctx).Register(ctx, nse)
+	s.record(ctx, registerMethod, networkServicesLabel(nse.GetNetworkServiceNames()), nse.GetName(), start, false, err)
+	if err != nil {
+		return nil, err
+	}
+
+	var expirationTime time.Time
+	if resp.GetExpirationTime() != nil {
+		expirationTime = resp.GetExpirationTime().AsTime().Local()
+	}
+
+	s.lock.Lock()
+	s.nses[resp.GetName()] = expirationTime
+	s.lock.Unlock()
+
+	return resp, nil
+}
+
+func (s *metricsNSEServer) Find(query *registry.NetworkServiceEndpointQuery, server registry.NetworkServiceEndpointRegistry_FindServer) error {
+	start := clock.FromContext(server.Context()).Now()
+	err := next.NetworkServiceEndpointRegistryServer(server.Context()).Find(query, server)
+	s.record(server.Context(), findMethod, networkServicesLabel(query.GetNetworkServiceEndpoint().GetNetworkServiceNames()),
+		query.GetNetworkServiceEndpoint().GetName(), start, query.GetWatch(), err)
+	return err
+}
+
+func (s *metricsNSEServer) Unregister(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*empty.Empty, error) {
+	start := clock.FromContext(ctx).Now()
+	resp, err := next.NetworkServiceEndpointRegistryServer(ctx).Unregister(ctx, nse)
+	s.record(ctx, unregisterMethod, networkServicesLabel(nse.GetNetworkServiceNames()), nse.GetName(), start, false, err)
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	delete(s.nses, nse.GetName())
+	s.lock.Unlock()
+
+	return resp, nil
+}
+
+func (s *metricsNSEServer) registered() float64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.clock.Now()
+	for name, expirationTime := range s.nses {
+		if !expirationTime.IsZero() && !now.Before(expirationTime) {
+			delete(s.nses, name)
+		}
+	}
+	return float64(len(s.nses))
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/networkservicemesh/sdk/pkg/tools/metrics"
+)
+
+type metricsOptions struct {
+	registry *metrics.Registry
+	buckets  []float64
+}
+
+// Option is an option pattern for the registry metrics chain elements
+type Option func(o *metricsOptions)
+
+// WithRegistry sets the registry of the metrics, metrics.Default() is used by default
+func WithRegistry(registry *metrics.Registry) Option {
+	return func(o *metricsOptions) {
+		o.registry = registry
+	}
+}
+
+// WithBuckets sets the latency histogram buckets in seconds, metrics.DefaultBuckets are used by default
+func WithBuckets(buckets ...float64) Option {
+	return func(o *metricsOptions) {
+		o.buckets = buckets
+	}
+}
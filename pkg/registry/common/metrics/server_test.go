@@ -0,0 +1,130 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/metrics"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+	metricstools "github.com/networkservicemesh/sdk/pkg/tools/metrics"
+)
+
+func writeText(t *testing.T, r *metricstools.Registry) string {
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteText(&buf))
+	return buf.String()
+}
+
+func TestMetricsNSE(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	clockMock.Set(time.Now())
+	ctx = clock.WithClock(ctx, clockMock)
+
+	r := metricstools.NewRegistry()
+
+	c := next.NewNetworkServiceEndpointRegistryClient(
+		metrics.NewNetworkServiceEndpointRegistryClient("nse", metrics.WithRegistry(r)),
+		adapters.NetworkServiceEndpointServerToClient(next.NewNetworkServiceEndpointRegistryServer(
+			metrics.NewNetworkServiceEndpointRegistryServer(ctx, "registry", metrics.WithRegistry(r)),
+			memory.NewNetworkServiceEndpointRegistryServer(),
+		)),
+	)
+
+	// 1. Register
+	for _, name := range []string{"nse-1", "nse-2"} {
+		_, err := c.Register(ctx, &registry.NetworkServiceEndpoint{
+			Name:                name,
+			NetworkServiceNames: []string{"ns-2", "ns-1"},
+			ExpirationTime:      timestamppb.New(clockMock.Now().Add(time.Minute)),
+		})
+		require.NoError(t, err)
+	}
+
+	text := writeText(t, r)
+	require.Contains(t, text, metrics.CallsMetric+`{side="client",chain="nse",type="NetworkServiceEndpoint",method="Register",network_service="ns-1,ns-2",nse="nse-1",code="OK"} 1`)
+	require.Contains(t, text, metrics.CallsMetric+`{side="server",chain="registry",type="NetworkServiceEndpoint",method="Register",network_service="ns-1,ns-2",nse="nse-1",code="OK"} 1`)
+	require.Contains(t, text, metrics.SizeMetric+`{chain="registry",type="NetworkServiceEndpoint"} 2`)
+
+	// 2. Find
+	stream, err := c.Find(ctx, &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{Name: "nse-1"},
+	})
+	require.NoError(t, err)
+	require.Len(t, registry.ReadNetworkServiceEndpointList(stream), 1)
+
+	text = writeText(t, r)
+	require.Contains(t, text, metrics.DurationMetric+`_count{side="server",chain="registry",type="NetworkServiceEndpoint",method="Find",network_service="",nse="nse-1"} 1`)
+
+	// 3. Unregister
+	_, err = c.Unregister(ctx, &registry.NetworkServiceEndpoint{Name: "nse-1"})
+	require.NoError(t, err)
+
+	require.Contains(t, writeText(t, r), metrics.SizeMetric+`{chain="registry",type="NetworkServiceEndpoint"} 1`)
+
+	// 4. Expire
+	clockMock.Add(time.Minute)
+
+	require.Contains(t, writeText(t, r), metrics.SizeMetric+`{chain="registry",type="NetworkServiceEndpoint"} 0`)
+}
+
+func TestMetricsNS(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := metricstools.NewRegistry()
+
+	c := next.NewNetworkServiceRegistryClient(
+		metrics.NewNetworkServiceRegistryClient("ns", metrics.WithRegistry(r)),
+		adapters.NetworkServiceServerToClient(next.NewNetworkServiceRegistryServer(
+			metrics.NewNetworkServiceRegistryServer("registry", metrics.WithRegistry(r)),
+			memory.NewNetworkServiceRegistryServer(),
+		)),
+	)
+
+	_, err := c.Register(ctx, &registry.NetworkService{Name: "ns-1"})
+	require.NoError(t, err)
+
+	require.Contains(t, writeText(t, r), metrics.SizeMetric+`{chain="registry",type="NetworkService"} 1`)
+
+	_, err = c.Unregister(ctx, &registry.NetworkService{Name: "ns-1"})
+	require.NoError(t, err)
+
+	text := writeText(t, r)
+	require.Contains(t, text, metrics.CallsMetric+`{side="server",chain="registry",type="NetworkService",method="Unregister",network_service="ns-1",nse="",code="OK"} 1`)
+	require.Contains(t, text, metrics.SizeMetric+`{chain="registry",type="NetworkService"} 0`)
+}
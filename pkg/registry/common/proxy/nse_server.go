@@ -1,4 +1,4 @@
-// Copyright (c) 2020 Doc.ai and/or its affiliates.
+// Copyright (c) 2020-2021 Doc.ai and/or its affiliates.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -28,6 +28,7 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/streamcontext"
 	"github.com/networkservicemesh/sdk/pkg/tools/interdomain"
+	"github.com/networkservicemesh/sdk/pkg/tools/matchutils"
 )
 
 type nseServer struct {
@@ -53,6 +54,8 @@ func (n nseServer) Find(q *registry.NetworkServiceEndpointQuery, s registry.Netw
 		return urlToProxyNotPassedErr
 	}
 	ctx := clienturlctx.WithClientURL(s.Context(), n.proxyRegistryURL)
+	// Match mode should be passed to the proxy registry
+	ctx = matchutils.WithMode(ctx, matchutils.ModeFromContext(ctx))
 	return next.NetworkServiceEndpointRegistryServer(ctx).Find(q, streamcontext.NetworkServiceEndpointRegistryFindServer(ctx, s))
 }
 
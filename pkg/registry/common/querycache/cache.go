@@ -17,45 +17,92 @@
 package querycache
 
 import (
+	"container/list"
 	"context"
+	"sort"
 	"sync"
 	"time"
 
-	"github.com/networkservicemesh/api/pkg/api/registry"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+)
+
+const (
+	defaultExpireTimeout = time.Minute
+	defaultMaxSize       = 1000
 )
 
+// Stats is the statistics of the cache
+type Stats struct {
+	// Hits is the number of the queries served from the cache
+	Hits uint64
+	// Misses is the number of the cacheable queries not found in the cache
+	Misses uint64
+	// Size is the number of the cache entries
+	Size int
+}
+
+// StatsProvider is implemented by the querycache clients
+type StatsProvider interface {
+	Stats() Stats
+}
+
+// Entry is the cache entry
+type Entry struct {
+	// Key is the cache key of the query
+	Key string
+	// Names are the names of the cached entities
+	Names []string
+	// ExpirationTime is the time when the entry is removed from the cache if it is not used
+	ExpirationTime time.Time
+}
+
+// ContentsProvider is implemented by the querycache clients
+type ContentsProvider interface {
+	// Contents returns the cache entries from the most to the least recently used
+	Contents() []Entry
+}
+
+type named interface {
+	proto.Message
+	GetName() string
+}
+
+// cache is a LRU cache of the query results. Every entry is kept up to date by its own watch, the watch is canceled
+// on the entry eviction or expiration.
 type cache struct {
+	clock         clock.Clock
 	expireTimeout time.Duration
-	entries       cacheEntryMap
+	maxSize       int
+	entries       map[string]*list.Element
+	lru           *list.List
+	hits, misses  uint64
+	lock          sync.Mutex
 }
 
 func newCache(ctx context.Context, opts ...Option) *cache {
 	c := &cache{
-		expireTimeout: time.Minute,
+		clock:         clock.FromContext(ctx),
+		expireTimeout: defaultExpireTimeout,
+		maxSize:       defaultMaxSize,
+		entries:       make(map[string]*list.Element),
+		lru:           list.New(),
 	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
-	ticker := time.NewTicker(c.expireTimeout)
+	ticker := c.clock.Ticker(c.expireTimeout)
 	go func() {
+		defer ticker.Stop()
 		for {
 			select {
 			case <-ctx.Done():
-				ticker.Stop()
 				return
-			case <-ticker.C:
-				c.entries.Range(func(_ string, e *cacheEntry) bool {
-					e.lock.Lock()
-					defer e.lock.Unlock()
-
-					if time.Until(e.expirationTime) < 0 {
-						e.cleanup()
-					}
-
-					return true
-				})
+			case <-ticker.C():
+				c.removeExpired()
 			}
 		}
 	}()
@@ -63,56 +110,174 @@ func newCache(ctx context.Context, opts ...Option) *cache {
 	return c
 }
 
-func (c *cache) LoadOrStore(key string, nse *registry.NetworkServiceEndpoint, cancel context.CancelFunc) (*cacheEntry, bool) {
-	var once sync.Once
-	return c.entries.LoadOrStore(key, &cacheEntry{
-		nse:            nse,
-		expirationTime: time.Now().Add(c.expireTimeout),
-		cleanup: func() {
-			once.Do(func() {
-				c.entries.Delete(key)
-				cancel()
-			})
-		},
-	})
-}
+// Load returns the entities of the entry found by the key
+func (c *cache) Load(key string) ([]proto.Message, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
 
-func (c *cache) Load(key string) (*registry.NetworkServiceEndpoint, bool) {
-	e, ok := c.entries.Load(key)
-	if !ok {
+	elem, ok := c.entries[key]
+	if !ok || c.expired(elem.Value.(*cacheEntry)) {
+		if ok {
+			c.remove(elem)
+		}
+		c.misses++
 		return nil, false
 	}
+	c.hits++
 
-	e.lock.Lock()
-	defer e.lock.Unlock()
+	e := elem.Value.(*cacheEntry)
+	e.expirationTime = c.clock.Now().Add(c.expireTimeout)
+	c.lru.MoveToFront(elem)
 
-	if time.Until(e.expirationTime) < 0 {
-		e.cleanup()
-		return nil, false
+	names := make([]string, 0, len(e.entities))
+	for name := range e.entities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	entities := make([]proto.Message, 0, len(names))
+	for _, name := range names {
+		entities = append(entities, proto.Clone(e.entities[name]))
+	}
+	return entities, true
+}
+
+// LoadOrStore returns the entry found by the key or stores a new one with the entities. The least recently used
+// entries are evicted if the cache is full.
+func (c *cache) LoadOrStore(key string, entities []named, cancel context.CancelFunc) (*cacheEntry, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		return elem.Value.(*cacheEntry), true
 	}
 
-	e.expirationTime = time.Now().Add(c.expireTimeout)
+	e := &cacheEntry{
+		cache:          c,
+		key:            key,
+		entities:       make(map[string]proto.Message),
+		expirationTime: c.clock.Now().Add(c.expireTimeout),
+		cancel:         cancel,
+	}
+	for _, entity := range entities {
+		e.entities[entity.GetName()] = proto.Clone(entity)
+	}
+	c.entries[key] = c.lru.PushFront(e)
 
-	return e.nse, true
+	for c.maxSize > 0 && c.lru.Len() > c.maxSize {
+		c.remove(c.lru.Back())
+	}
+
+	return e, false
+}
+
+// Stats returns the cache statistics
+func (c *cache) Stats() Stats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return Stats{
+		Hits:   c.hits,
+		Misses: c.misses,
+		Size:   c.lru.Len(),
+	}
+}
+
+// Contents returns the cache entries from the most to the least recently used
+func (c *cache) Contents() []Entry {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entries := make([]Entry, 0, c.lru.Len())
+	for elem := c.lru.Front(); elem != nil; elem = elem.Next() {
+		e := elem.Value.(*cacheEntry)
+		entry := Entry{
+			Key:            e.key,
+			Names:          make([]string, 0, len(e.entities)),
+			ExpirationTime: e.expirationTime,
+		}
+		for name := range e.entities {
+			entry.Names = append(entry.Names, name)
+		}
+		sort.Strings(entry.Names)
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func (c *cache) removeExpired() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for elem := c.lru.Back(); elem != nil; {
+		prev := elem.Prev()
+		if c.expired(elem.Value.(*cacheEntry)) {
+			c.remove(elem)
+		}
+		elem = prev
+	}
+}
+
+func (c *cache) expired(e *cacheEntry) bool {
+	return !c.clock.Now().Before(e.expirationTime)
+}
+
+func (c *cache) remove(elem *list.Element) {
+	e := elem.Value.(*cacheEntry)
+	if c.entries[e.key] == elem {
+		delete(c.entries, e.key)
+		c.lru.Remove(elem)
+	}
+	e.cancel()
 }
 
 type cacheEntry struct {
-	nse            *registry.NetworkServiceEndpoint
+	cache          *cache
+	key            string
+	entities       map[string]proto.Message
 	expirationTime time.Time
-	lock           sync.Mutex
-	cleanup        func()
+	cancel         context.CancelFunc
+}
+
+// Update stores the entity in the entry
+func (e *cacheEntry) Update(entity named) {
+	e.cache.lock.Lock()
+	defer e.cache.lock.Unlock()
+
+	e.entities[entity.GetName()] = proto.Clone(entity)
 }
 
-func (e *cacheEntry) Update(nse *registry.NetworkServiceEndpoint) {
-	e.lock.Lock()
-	defer e.lock.Unlock()
+// Delete deletes the entity from the entry, returns false if there are no more entities in the entry
+func (e *cacheEntry) Delete(name string) bool {
+	e.cache.lock.Lock()
+	defer e.cache.lock.Unlock()
 
-	e.nse = nse
+	delete(e.entities, name)
+	return len(e.entities) > 0
 }
 
+// Retain deletes the entities not found in the names from the entry, returns false if there are no more entities in
+// the entry
+func (e *cacheEntry) Retain(names map[string]bool) bool {
+	e.cache.lock.Lock()
+	defer e.cache.lock.Unlock()
+
+	for name := range e.entities {
+		if !names[name] {
+			delete(e.entities, name)
+		}
+	}
+	return len(e.entities) > 0
+}
+
+// Cleanup removes the entry from the cache
 func (e *cacheEntry) Cleanup() {
-	e.lock.Lock()
-	defer e.lock.Unlock()
+	e.cache.lock.Lock()
+	defer e.cache.lock.Unlock()
 
-	e.cleanup()
+	if elem, ok := e.cache.entries[e.key]; ok && elem.Value == e {
+		e.cache.remove(elem)
+		return
+	}
+	e.cancel()
 }
@@ -0,0 +1,146 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querycache
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/streamchannel"
+	"github.com/networkservicemesh/sdk/pkg/tools/matchutils"
+)
+
+type queryCacheNSClient struct {
+	ctx   context.Context
+	cache *cache
+}
+
+// NewNetworkServiceClient creates new querycache NS registry client that caches all resolved NSs by their names
+func NewNetworkServiceClient(ctx context.Context, opts ...Option) registry.NetworkServiceRegistryClient {
+	return &queryCacheNSClient{
+		ctx:   ctx,
+		cache: newCache(ctx, opts...),
+	}
+}
+
+func (q *queryCacheNSClient) Register(ctx context.Context, ns *registry.NetworkService, opts ...grpc.CallOption) (*registry.NetworkService, error) {
+	return next.NetworkServiceRegistryClient(ctx).Register(ctx, ns, opts...)
+}
+
+func (q *queryCacheNSClient) Find(ctx context.Context, query *registry.NetworkServiceQuery, opts ...grpc.CallOption) (registry.NetworkServiceRegistry_FindClient, error) {
+	if query.Watch {
+		return next.NetworkServiceRegistryClient(ctx).Find(ctx, query, opts...)
+	}
+
+	// Cache entries are stored per NS name, so they can only be used for the exact name queries
+	mode := matchutils.ModeFromContext(ctx)
+	cacheable := query.GetNetworkService().GetName() != "" && (mode == matchutils.SubstringMode || mode == matchutils.ExactMode)
+	if cacheable {
+		if client, ok := q.findInCache(ctx, query.String()); ok {
+			return client, nil
+		}
+	}
+
+	client, err := next.NetworkServiceRegistryClient(ctx).Find(ctx, query, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	nss := registry.ReadNetworkServiceList(client)
+
+	resultCh := make(chan *registry.NetworkService, len(nss))
+	for _, ns := range nss {
+		resultCh <- ns
+		q.storeInCache(ctx, ns, opts...)
+	}
+	close(resultCh)
+
+	return streamchannel.NewNetworkServiceFindClient(ctx, resultCh), nil
+}
+
+// Stats returns the cache statistics
+func (q *queryCacheNSClient) Stats() Stats {
+	return q.cache.Stats()
+}
+
+// Contents returns the cache entries from the most to the least recently used
+func (q *queryCacheNSClient) Contents() []Entry {
+	return q.cache.Contents()
+}
+
+func (q *queryCacheNSClient) findInCache(ctx context.Context, key string) (registry.NetworkServiceRegistry_FindClient, bool) {
+	entities, ok := q.cache.Load(key)
+	if !ok {
+		return nil, false
+	}
+
+	resultCh := make(chan *registry.NetworkService, len(entities))
+	for _, entity := range entities {
+		resultCh <- entity.(*registry.NetworkService)
+	}
+	close(resultCh)
+
+	return streamchannel.NewNetworkServiceFindClient(ctx, resultCh), true
+}
+
+func (q *queryCacheNSClient) storeInCache(ctx context.Context, ns *registry.NetworkService, opts ...grpc.CallOption) {
+	nsQuery := &registry.NetworkServiceQuery{
+		NetworkService: &registry.NetworkService{
+			Name: ns.Name,
+		},
+	}
+
+	key := nsQuery.String()
+
+	findCtx, cancel := context.WithCancel(matchutils.WithMode(q.ctx, matchutils.ExactMode))
+
+	entry, loaded := q.cache.LoadOrStore(key, []named{ns}, cancel)
+	if loaded {
+		cancel()
+		return
+	}
+
+	nsQuery.Watch = true
+	go func() {
+		defer entry.Cleanup()
+
+		// NS unregister is not sent to the watchers, so the deleted NS entry is removed only on expiration
+		watch(findCtx, func(findCtx context.Context) (func() (named, error), error) {
+			stream, err := next.NetworkServiceRegistryClient(ctx).Find(findCtx, nsQuery, opts...)
+			if err != nil {
+				return nil, err
+			}
+			return func() (named, error) {
+				return stream.Recv()
+			}, nil
+		}, func(entity named) bool {
+			if ns := entity.(*registry.NetworkService); ns.Name == nsQuery.NetworkService.Name {
+				entry.Update(ns)
+			}
+			return true
+		}, entry.Retain)
+	}()
+}
+
+func (q *queryCacheNSClient) Unregister(ctx context.Context, ns *registry.NetworkService, opts ...grpc.CallOption) (*empty.Empty, error) {
+	return next.NetworkServiceRegistryClient(ctx).Unregister(ctx, ns, opts...)
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querycache_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/querycache"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+)
+
+func testNSQuery(nsName string) *registry.NetworkServiceQuery {
+	return &registry.NetworkServiceQuery{
+		NetworkService: &registry.NetworkService{
+			Name: nsName,
+		},
+	}
+}
+
+func Test_QueryCacheNSClient_ShouldCacheNSs(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	clockMock.Set(time.Now())
+	ctx = clock.WithClock(ctx, clockMock)
+
+	mem := memory.NewNetworkServiceRegistryServer()
+
+	failureClient := new(failureNSClient)
+	qc := querycache.NewNetworkServiceClient(ctx, querycache.WithExpireTimeout(time.Minute))
+	c := next.NewNetworkServiceRegistryClient(
+		qc,
+		failureClient,
+		adapters.NetworkServiceServerToClient(mem),
+	)
+
+	reg, err := mem.Register(ctx, &registry.NetworkService{
+		Name:    "ns",
+		Payload: "IP",
+	})
+	require.NoError(t, err)
+
+	// Goroutines should be cleaned up on cache entry expiration
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	find := func() (*registry.NetworkService, error) {
+		stream, err := c.Find(ctx, testNSQuery("ns"))
+		if err != nil {
+			return nil, err
+		}
+		return stream.Recv()
+	}
+
+	// 1. Find from memory
+	ns, err := find()
+	require.NoError(t, err)
+	require.Equal(t, "IP", ns.Payload)
+
+	// 2. Find from cache
+	atomic.StoreInt32(&failureClient.shouldFail, 1)
+
+	ns, err = find()
+	require.NoError(t, err)
+	require.Equal(t, "IP", ns.Payload)
+
+	// 3. Update NS in memory
+	reg.Payload = "ETHERNET"
+
+	_, err = mem.Register(ctx, reg)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		ns, err = find()
+		return err == nil && ns.Payload == "ETHERNET"
+	}, 100*time.Millisecond, time.Millisecond)
+
+	stats := qc.(querycache.StatsProvider).Stats()
+	require.Equal(t, uint64(1), stats.Misses)
+	require.GreaterOrEqual(t, stats.Hits, uint64(2))
+	require.Equal(t, 1, stats.Size)
+
+	// 4. Wait for the expire to happen
+	clockMock.Add(time.Minute)
+
+	_, err = find()
+	require.Error(t, err)
+}
+
+type failureNSClient struct {
+	shouldFail int32
+}
+
+func (c *failureNSClient) Register(ctx context.Context, ns *registry.NetworkService, opts ...grpc.CallOption) (*registry.NetworkService, error) {
+	return next.NetworkServiceRegistryClient(ctx).Register(ctx, ns, opts...)
+}
+
+func (c *failureNSClient) Find(ctx context.Context, query *registry.NetworkServiceQuery, opts ...grpc.CallOption) (registry.NetworkServiceRegistry_FindClient, error) {
+	if atomic.LoadInt32(&c.shouldFail) == 1 && !query.Watch {
+		return nil, errors.New("find error")
+	}
+	return next.NetworkServiceRegistryClient(ctx).Find(ctx, query, opts...)
+}
+
+func (c *failureNSClient) Unregister(ctx context.Context, ns *registry.NetworkService, opts ...grpc.CallOption) (*empty.Empty, error) {
+	return next.NetworkServiceRegistryClient(ctx).Unregister(ctx, ns, opts...)
+}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/streamchannel"
+	"github.com/networkservicemesh/sdk/pkg/tools/matchutils"
 )
 
 type queryCacheNSEClient struct {
@@ -34,7 +35,8 @@ type queryCacheNSEClient struct {
 	cache *cache
 }
 
-// NewClient creates new querycache NSE registry client that caches all resolved NSEs
+// NewClient creates new querycache NSE registry client that caches all resolved NSEs by their names and the results
+// of the label based queries
 func NewClient(ctx context.Context, opts ...Option) registry.NetworkServiceEndpointRegistryClient {
 	return &queryCacheNSEClient{
 		ctx:   ctx,
@@ -51,8 +53,11 @@ func (q *queryCacheNSEClient) Find(ctx context.Context, query *registry.NetworkS
 		return next.NetworkServiceEndpointRegistryClient(ctx).Find(ctx, query, opts...)
 	}
 
-	if client, ok := q.findInCache(ctx, query.String()); ok {
-		return client, nil
+	key, cacheable := q.cacheKey(ctx, query)
+	if cacheable {
+		if client, ok := q.findInCache(ctx, key); ok {
+			return client, nil
+		}
 	}
 
 	client, err := next.NetworkServiceEndpointRegistryClient(ctx).Find(ctx, query, opts...)
@@ -69,17 +74,55 @@ func (q *queryCacheNSEClient) Find(ctx context.Context, query *registry.NetworkS
 	}
 	close(resultCh)
 
+	// Empty result is not cached, so the entry is removed with the watch when all the matching NSEs are unregistered
+	if cacheable && isLabelQuery(query) && len(nses) > 0 {
+		q.storeQueryInCache(ctx, key, query, nses, opts...)
+	}
+
 	return streamchannel.NewNetworkServiceEndpointFindClient(ctx, resultCh), nil
 }
 
+// Stats returns the cache statistics
+func (q *queryCacheNSEClient) Stats() Stats {
+	return q.cache.Stats()
+}
+
+// Contents returns the cache entries from the most to the least recently used
+func (q *queryCacheNSEClient) Contents() []Entry {
+	return q.cache.Contents()
+}
+
+func (q *queryCacheNSEClient) cacheKey(ctx context.Context, query *registry.NetworkServiceEndpointQuery) (string, bool) {
+	mode := matchutils.ModeFromContext(ctx)
+
+	// Label based query entries are kept up to date by the watch in the same mode, so they can be used in any mode
+	if isLabelQuery(query) {
+		return mode.String() + ":" + query.String(), true
+	}
+
+	// Cache entries are stored per NSE name, so they can only be used for the exact name queries
+	if query.GetNetworkServiceEndpoint().GetName() != "" && (mode == matchutils.SubstringMode || mode == matchutils.ExactMode) {
+		return query.String(), true
+	}
+
+	return "", false
+}
+
+func isLabelQuery(query *registry.NetworkServiceEndpointQuery) bool {
+	nse := query.GetNetworkServiceEndpoint()
+	return nse.GetName() == "" && (len(nse.GetNetworkServiceNames()) > 0 || len(nse.GetNetworkServiceLabels()) > 0)
+}
+
 func (q *queryCacheNSEClient) findInCache(ctx context.Context, key string) (registry.NetworkServiceEndpointRegistry_FindClient, bool) {
-	nse, ok := q.cache.Load(key)
+	entities, ok := q.cache.Load(key)
 	if !ok {
 		return nil, false
 	}
 
-	resultCh := make(chan *registry.NetworkServiceEndpoint, 1)
-	resultCh <- nse.Clone()
+	resultCh := make(chan *registry.NetworkServiceEndpoint, len(entities))
+	for _, entity := range entities {
+		resultCh <- entity.(*registry.NetworkServiceEndpoint)
+	}
 	close(resultCh)
 
 	return streamchannel.NewNetworkServiceEndpointFindClient(ctx, resultCh), true
@@ -94,37 +137,84 @@ func (q *queryCacheNSEClient) storeInCache(ctx context.Context, nse *registry.Ne
 
 	key := nseQuery.String()
 
-	findCtx, cancel := context.WithCancel(q.ctx)
+	findCtx, cancel := context.WithCancel(matchutils.WithMode(q.ctx, matchutils.ExactMode))
 
-	entry, loaded := q.cache.LoadOrStore(key, nse, cancel)
+	entry, loaded := q.cache.LoadOrStore(key, []named{nse}, cancel)
 	if loaded {
 		cancel()
 		return
 	}
 
+	nseQuery.Watch = true
 	go func() {
 		defer entry.Cleanup()
 
-		nseQuery.Watch = true
-
-		stream, err := next.NetworkServiceEndpointRegistryClient(ctx).Find(findCtx, nseQuery, opts...)
-		if err != nil {
-			return
-		}
-
-		for nse, err = stream.Recv(); err == nil; nse, err = stream.Recv() {
+		watch(findCtx, q.findFunc(ctx, nseQuery, opts...), func(entity named) bool {
+			nse := entity.(*registry.NetworkServiceEndpoint)
 			if nse.Name != nseQuery.NetworkServiceEndpoint.Name {
-				continue
+				return true
 			}
 			if nse.ExpirationTime != nil && nse.ExpirationTime.Seconds < 0 {
-				break
+				return false
 			}
 
 			entry.Update(nse)
-		}
+			return true
+		}, entry.Retain)
 	}()
 }
 
+func (q *queryCacheNSEClient) storeQueryInCache(
+	ctx context.Context,
+	key string,
+	query *registry.NetworkServiceEndpointQuery,
+	nses []*registry.NetworkServiceEndpoint,
+	opts ...grpc.CallOption,
+) {
+	findCtx, cancel := context.WithCancel(matchutils.WithMode(q.ctx, matchutils.ModeFromContext(ctx)))
+
+	entities := make([]named, 0, len(nses))
+	for _, nse := range nses {
+		entities = append(entities, nse)
+	}
+
+	entry, loaded := q.cache.LoadOrStore(key, entities, cancel)
+	if loaded {
+		cancel()
+		return
+	}
+
+	watchQuery := &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: query.NetworkServiceEndpoint,
+		Watch:                  true,
+	}
+	go func() {
+		defer entry.Cleanup()
+
+		watch(findCtx, q.findFunc(ctx, watchQuery, opts...), func(entity named) bool {
+			nse := entity.(*registry.NetworkServiceEndpoint)
+			if nse.ExpirationTime != nil && nse.ExpirationTime.Seconds < 0 {
+				return entry.Delete(nse.Name)
+			}
+
+			entry.Update(nse)
+			return true
+		}, entry.Retain)
+	}()
+}
+
+func (q *queryCacheNSEClient) findFunc(ctx context.Context, query *registry.NetworkServiceEndpointQuery, opts ...grpc.CallOption) findFunc {
+	return func(findCtx context.Context) (func() (named, error), error) {
+		stream, err := next.NetworkServiceEndpointRegistryClient(ctx).Find(findCtx, query, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return func() (named, error) {
+			return stream.Recv()
+		}, nil
+	}
+}
+
 func (q *queryCacheNSEClient) Unregister(ctx context.Context, in *registry.NetworkServiceEndpoint, opts ...grpc.CallOption) (*empty.Empty, error) {
 	return next.NetworkServiceEndpointRegistryClient(ctx).Unregister(ctx, in, opts...)
 }
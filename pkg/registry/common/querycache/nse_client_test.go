@@ -18,6 +18,7 @@ package querycache_test
 
 import (
 	"context"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -33,6 +34,11 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/registry/common/querycache"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
 	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/streamchannel"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+	"github.com/networkservicemesh/sdk/pkg/tools/matchutils"
+	"github.com/networkservicemesh/sdk/pkg/tools/revision"
 )
 
 const (
@@ -197,3 +203,455 @@ func (c *failureNSEClient) Find(ctx context.Context, query *registry.NetworkServ
 func (c *failureNSEClient) Unregister(ctx context.Context, nse *registry.NetworkServiceEndpoint, opts ...grpc.CallOption) (*empty.Empty, error) {
 	return next.NetworkServiceEndpointRegistryClient(ctx).Unregister(ctx, nse, opts...)
 }
+
+func Test_QueryCacheClient_ShouldNotUseCacheForPatternModes(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mem := memory.NewNetworkServiceEndpointRegistryServer()
+
+	failureClient := new(failureNSEClient)
+	c := next.NewNetworkServiceEndpointRegistryClient(
+		querycache.NewClient(ctx, querycache.WithExpireTimeout(time.Minute)),
+		failureClient,
+		adapters.NetworkServiceEndpointServerToClient(mem),
+	)
+
+	for _, nseName := range []string{name, name + "-2"} {
+		_, err := mem.Register(ctx, &registry.NetworkServiceEndpoint{
+			Name: nseName,
+		})
+		require.NoError(t, err)
+	}
+
+	// 1. Find from memory
+	stream, err := c.Find(ctx, testNSEQuery(""))
+	require.NoError(t, err)
+	require.Len(t, registry.ReadNetworkServiceEndpointList(stream), 2)
+
+	// 2. Find from cache in exact mode
+	atomic.StoreInt32(&failureClient.shouldFail, 1)
+
+	exactCtx := matchutils.WithMode(ctx, matchutils.ExactMode)
+	require.Eventually(t, func() bool {
+		if stream, err = c.Find(exactCtx, testNSEQuery(name)); err != nil {
+			return false
+		}
+		nses := registry.ReadNetworkServiceEndpointList(stream)
+		return len(nses) == 1 && nses[0].Name == name
+	}, 100*time.Millisecond, time.Millisecond)
+
+	// 3. Prefix mode query can match more NSEs than cached for the name, so it should not be served from cache
+	_, err = c.Find(matchutils.WithMode(ctx, matchutils.PrefixMode), testNSEQuery(name))
+	require.Error(t, err)
+
+	// 4. Delete NSEs to clean up the cache
+	for _, nseName := range []string{name, name + "-2"} {
+		_, err = mem.Unregister(ctx, &registry.NetworkServiceEndpoint{
+			Name: nseName,
+		})
+		require.NoError(t, err)
+	}
+}
+
+func Test_QueryCacheClient_ShouldResumeWatch(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mem := memory.NewNetworkServiceEndpointRegistryServer()
+	breaking := &breakingNSEServer{
+		resumeCh: make(chan struct{}),
+	}
+
+	failureClient := new(failureNSEClient)
+	c := next.NewNetworkServiceEndpointRegistryClient(
+		querycache.NewClient(ctx, querycache.WithExpireTimeout(time.Minute)),
+		failureClient,
+		adapters.NetworkServiceEndpointServerToClient(next.NewNetworkServiceEndpointRegistryServer(breaking, mem)),
+	)
+
+	reg, err := mem.Register(ctx, &registry.NetworkServiceEndpoint{
+		Name: name,
+		Url:  url1,
+	})
+	require.NoError(t, err)
+
+	// 1. Find from memory, watch stream breaks after the first event
+	stream, err := c.Find(ctx, testNSEQuery(name))
+	require.NoError(t, err)
+	require.Len(t, registry.ReadNetworkServiceEndpointList(stream), 1)
+
+	// 2. NSE is updated before the watch is resumed
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&breaking.watches) == 2
+	}, time.Second, time.Millisecond)
+
+	reg.Url = url2
+
+	reg, err = mem.Register(ctx, reg)
+	require.NoError(t, err)
+
+	close(breaking.resumeCh)
+
+	// 3. Resumed watch receives the missed update
+	atomic.StoreInt32(&failureClient.shouldFail, 1)
+
+	require.Eventually(t, func() bool {
+		if stream, err = c.Find(ctx, testNSEQuery(name)); err != nil {
+			return false
+		}
+		nse, recvErr := stream.Recv()
+		return recvErr == nil && nse.Url == url2
+	}, time.Second, time.Millisecond)
+
+	// 4. Resumed watch receives the unregister, so the cache entry is removed
+	_, err = mem.Unregister(ctx, reg)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		_, err = c.Find(ctx, testNSEQuery(name))
+		return err != nil
+	}, time.Second, time.Millisecond)
+}
+
+type breakingNSEServer struct {
+	watches  int32
+	resumeCh chan struct{}
+}
+
+func (s *breakingNSEServer) Register(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*registry.NetworkServiceEndpoint, error) {
+	return next.NetworkServiceEndpointRegistryServer(ctx).Register(ctx, nse)
+}
+
+func (s *breakingNSEServer) Find(query *registry.NetworkServiceEndpointQuery, server registry.NetworkServiceEndpointRegistry_FindServer) error {
+	if !query.Watch {
+		return next.NetworkServiceEndpointRegistryServer(server.Context()).Find(query, server)
+	}
+
+	switch atomic.AddInt32(&s.watches, 1) {
+	case 1:
+		ctx, cancel := context.WithCancel(server.Context())
+		defer cancel()
+
+		_ = next.NetworkServiceEndpointRegistryServer(ctx).Find(query, &breakingFindServer{
+			NetworkServiceEndpointRegistry_FindServer: server,
+			ctx:    ctx,
+			cancel: cancel,
+		})
+		return errors.New("stream is broken")
+	default:
+		watch, err := revision.FromContext(server.Context())
+		if err != nil {
+			return err
+		}
+		if watch == nil || watch.Start == nil {
+			return errors.New("watch is not resumed")
+		}
+
+		select {
+		case <-server.Context().Done():
+			return nil
+		case <-s.resumeCh:
+		}
+		return next.NetworkServiceEndpointRegistryServer(server.Context()).Find(query, server)
+	}
+}
+
+func (s *breakingNSEServer) Unregister(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*empty.Empty, error) {
+	return next.NetworkServiceEndpointRegistryServer(ctx).Unregister(ctx, nse)
+}
+
+type breakingFindServer struct {
+	registry.NetworkServiceEndpointRegistry_FindServer
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (s *breakingFindServer) Send(nse *registry.NetworkServiceEndpoint) error {
+	defer s.cancel()
+	return s.NetworkServiceEndpointRegistry_FindServer.Send(nse)
+}
+
+func (s *breakingFindServer) Context() context.Context {
+	return s.ctx
+}
+
+func Test_QueryCacheClient_ShouldCacheLabelQueries(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mem := memory.NewNetworkServiceEndpointRegistryServer()
+
+	failureClient := new(failureNSEClient)
+	qc := querycache.NewClient(ctx, querycache.WithExpireTimeout(time.Minute))
+	c := next.NewNetworkServiceEndpointRegistryClient(
+		qc,
+		failureClient,
+		adapters.NetworkServiceEndpointServerToClient(mem),
+	)
+
+	query := &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{
+			NetworkServiceNames: []string{"ns"},
+		},
+	}
+
+	reg, err := mem.Register(ctx, &registry.NetworkServiceEndpoint{
+		Name:                name,
+		NetworkServiceNames: []string{"ns"},
+	})
+	require.NoError(t, err)
+
+	// 1. Find from memory
+	stream, err := c.Find(ctx, query)
+	require.NoError(t, err)
+	require.Len(t, registry.ReadNetworkServiceEndpointList(stream), 1)
+
+	// 2. Find from cache
+	atomic.StoreInt32(&failureClient.shouldFail, 1)
+
+	findNames := func() (names []string) {
+		stream, err := c.Find(ctx, query)
+		require.NoError(t, err)
+		for _, nse := range registry.ReadNetworkServiceEndpointList(stream) {
+			names = append(names, nse.Name)
+		}
+		return names
+	}
+
+	require.Equal(t, []string{name}, findNames())
+
+	// 3. Register new NSE for the same NS
+	reg2, err := mem.Register(ctx, &registry.NetworkServiceEndpoint{
+		Name:                name + "-2",
+		NetworkServiceNames: []string{"ns"},
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return len(findNames()) == 2
+	}, 100*time.Millisecond, time.Millisecond)
+
+	// 4. Unregister the first NSE
+	_, err = mem.Unregister(ctx, reg)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		names := findNames()
+		return len(names) == 1 && names[0] == name+"-2"
+	}, 100*time.Millisecond, time.Millisecond)
+
+	// 5. Query in the other mode is not served from the cache
+	_, err = c.Find(matchutils.WithMode(ctx, matchutils.PrefixMode), query)
+	require.Error(t, err)
+
+	stats := qc.(querycache.StatsProvider).Stats()
+	require.Equal(t, uint64(2), stats.Misses)
+	require.Greater(t, stats.Hits, uint64(2))
+
+	// 6. Unregister the last NSE, the entry should be removed
+	_, err = mem.Unregister(ctx, reg2)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		_, err = c.Find(ctx, query)
+		return err != nil
+	}, 100*time.Millisecond, time.Millisecond)
+}
+
+type staleListNSEClient struct {
+	registry.NetworkServiceEndpointRegistryClient
+	stale *registry.NetworkServiceEndpoint
+}
+
+func (c *staleListNSEClient) Find(ctx context.Context, query *registry.NetworkServiceEndpointQuery, opts ...grpc.CallOption) (registry.NetworkServiceEndpointRegistry_FindClient, error) {
+	if query.Watch {
+		return next.NetworkServiceEndpointRegistryClient(ctx).Find(ctx, query, opts...)
+	}
+
+	stream, err := next.NetworkServiceEndpointRegistryClient(ctx).Find(ctx, query, opts...)
+	if err != nil {
+		return nil, err
+	}
+	nses := append(registry.ReadNetworkServiceEndpointList(stream), c.stale.Clone())
+
+	ch := make(chan *registry.NetworkServiceEndpoint, len(nses))
+	for _, nse := range nses {
+		ch <- nse
+	}
+	close(ch)
+	return streamchannel.NewNetworkServiceEndpointFindClient(ctx, ch), nil
+}
+
+func Test_QueryCacheClient_ShouldReplaceOnRelist(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mem := memory.NewNetworkServiceEndpointRegistryServer()
+
+	qc := querycache.NewClient(ctx, querycache.WithExpireTimeout(time.Minute))
+	c := next.NewNetworkServiceEndpointRegistryClient(
+		qc,
+		// NSE is unregistered between the list and the watch relist
+		&staleListNSEClient{stale: &registry.NetworkServiceEndpoint{
+			Name:                "stale",
+			NetworkServiceNames: []string{"ns"},
+		}},
+		adapters.NetworkServiceEndpointServerToClient(mem),
+	)
+
+	query := &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{
+			NetworkServiceNames: []string{"ns"},
+		},
+	}
+
+	_, err := mem.Register(ctx, &registry.NetworkServiceEndpoint{
+		Name:                name,
+		NetworkServiceNames: []string{"ns"},
+	})
+	require.NoError(t, err)
+
+	stream, err := c.Find(ctx, query)
+	require.NoError(t, err)
+	require.Len(t, registry.ReadNetworkServiceEndpointList(stream), 2)
+
+	require.Eventually(t, func() bool {
+		for _, stats := range mem.(memory.WatchersStatsProvider).WatchersStats() {
+			if len(stats.Query.(*registry.NetworkServiceEndpointQuery).GetNetworkServiceEndpoint().GetNetworkServiceNames()) > 0 {
+				return true
+			}
+		}
+		return false
+	}, 100*time.Millisecond, time.Millisecond)
+
+	// Next event completes the relist, so the entities missing from it are removed
+	_, err = mem.Register(ctx, &registry.NetworkServiceEndpoint{
+		Name:                name + "-2",
+		NetworkServiceNames: []string{"ns"},
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		for _, entry := range qc.(querycache.ContentsProvider).Contents() {
+			if strings.Contains(entry.Key, "network_service_names") {
+				return strings.Join(entry.Names, ",") == name+","+name+"-2"
+			}
+		}
+		return false
+	}, 100*time.Millisecond, time.Millisecond)
+}
+
+func Test_QueryCacheClient_ShouldEvictLeastRecentlyUsed(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mem := memory.NewNetworkServiceEndpointRegistryServer()
+
+	failureClient := new(failureNSEClient)
+	qc := querycache.NewClient(ctx, querycache.WithExpireTimeout(time.Minute), querycache.WithMaxSize(2))
+	c := next.NewNetworkServiceEndpointRegistryClient(
+		qc,
+		failureClient,
+		adapters.NetworkServiceEndpointServerToClient(mem),
+	)
+
+	for _, nseName := range []string{"a", "b", "c"} {
+		_, err := mem.Register(ctx, &registry.NetworkServiceEndpoint{
+			Name: nseName,
+		})
+		require.NoError(t, err)
+	}
+
+	// Goroutines of the evicted entries should be cleaned up
+	ignoreCurrent := goleak.IgnoreCurrent()
+
+	find := func(nseName string) error {
+		stream, err := c.Find(ctx, testNSEQuery(nseName))
+		if err != nil {
+			return err
+		}
+		require.Len(t, registry.ReadNetworkServiceEndpointList(stream), 1)
+		return nil
+	}
+
+	// 1. Find "a", "b" from memory and "a" from cache, so "b" becomes the least recently used
+	require.NoError(t, find("a"))
+	require.NoError(t, find("b"))
+	require.NoError(t, find("a"))
+
+	// 2. Find "c" from memory, "b" should be evicted
+	require.NoError(t, find("c"))
+
+	stats := qc.(querycache.StatsProvider).Stats()
+	require.Equal(t, querycache.Stats{Hits: 1, Misses: 3, Size: 2}, stats)
+
+	contents := qc.(querycache.ContentsProvider).Contents()
+	require.Len(t, contents, 2)
+	require.Equal(t, []string{"c"}, contents[0].Names)
+	require.Equal(t, []string{"a"}, contents[1].Names)
+
+	// 3. Find from cache
+	atomic.StoreInt32(&failureClient.shouldFail, 1)
+
+	require.NoError(t, find("a"))
+	require.NoError(t, find("c"))
+	require.Error(t, find("b"))
+
+	cancel()
+
+	require.Eventually(t, func() bool {
+		return goleak.Find(ignoreCurrent) == nil
+	}, time.Second, 10*time.Millisecond)
+}
+
+func Test_QueryCacheClient_ShouldExpireWithClock(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	clockMock.Set(time.Now())
+	ctx = clock.WithClock(ctx, clockMock)
+
+	mem := memory.NewNetworkServiceEndpointRegistryServer()
+
+	qc := querycache.NewClient(ctx, querycache.WithExpireTimeout(time.Minute))
+	c := next.NewNetworkServiceEndpointRegistryClient(
+		qc,
+		adapters.NetworkServiceEndpointServerToClient(mem),
+	)
+
+	_, err := mem.Register(ctx, &registry.NetworkServiceEndpoint{
+		Name: name,
+	})
+	require.NoError(t, err)
+
+	// Goroutines should be cleaned up on cache entry expiration
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	stream, err := c.Find(ctx, testNSEQuery(name))
+	require.NoError(t, err)
+	require.Len(t, registry.ReadNetworkServiceEndpointList(stream), 1)
+
+	require.Equal(t, 1, qc.(querycache.StatsProvider).Stats().Size)
+
+	clockMock.Add(time.Minute / 2)
+	require.Equal(t, 1, qc.(querycache.StatsProvider).Stats().Size)
+
+	clockMock.Add(time.Minute / 2)
+	require.Eventually(t, func() bool {
+		return qc.(querycache.StatsProvider).Stats().Size == 0
+	}, 100*time.Millisecond, time.Millisecond)
+}
@@ -27,3 +27,11 @@ func WithExpireTimeout(expireTimeout time.Duration) Option {
 		c.expireTimeout = expireTimeout
 	}
 }
+
+// WithMaxSize sets cache max size, the least recently used entries are evicted and their watches are canceled if the
+// cache is full. Zero max size means unbounded cache.
+func WithMaxSize(maxSize int) Option {
+	return func(c *cache) {
+		c.maxSize = maxSize
+	}
+}
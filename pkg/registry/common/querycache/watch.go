@@ -0,0 +1,70 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querycache
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/revision"
+)
+
+// findFunc starts the watch and returns the function receiving its events
+type findFunc func(ctx context.Context) (func() (named, error), error)
+
+// watch keeps the cache entry up to date until handle returns false or the ctx is done. The watch is resumed from the
+// last received revision if the stream breaks. If it is not possible, watch returns, so the caller should remove the
+// entry to not miss any update.
+// Relisted entities replace the entry contents: all of them have the same revision, so once the first event with the
+// other revision is received, retain is called with the relisted names to remove the entities missing from the relist.
+func watch(ctx context.Context, find findFunc, handle func(entity named) bool, retain func(names map[string]bool) bool) {
+	watchCtx, resumable := revision.WithRelist(ctx), false
+	for {
+		recv, err := find(watchCtx)
+		if err != nil {
+			return
+		}
+
+		var received bool
+		var relisted *revision.Revision
+		relisting, names := !resumable, make(map[string]bool)
+		for entity, err := recv(); err == nil; entity, err = recv() {
+			received = true
+			rev, ok := revision.Get(entity)
+			if relisting && ok && (relisted == nil || *relisted == rev) {
+				relisted = &rev
+				names[entity.GetName()] = true
+			} else if relisting {
+				relisting = false
+				if relisted != nil && !retain(names) {
+					return
+				}
+			}
+			if ok {
+				watchCtx, resumable = revision.WithStart(ctx, rev), true
+				revision.Clear(entity)
+			}
+
+			if !handle(entity) {
+				return
+			}
+		}
+
+		if !received || !resumable || ctx.Err() != nil {
+			return
+		}
+	}
+}
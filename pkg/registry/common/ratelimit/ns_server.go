@@ -0,0 +1,60 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ratelimit provides registry chain elements limiting the rate of the registry calls
+package ratelimit
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/ratelimit"
+)
+
+type rateLimitNSServer struct {
+	limiter *ratelimit.Limiter
+}
+
+// NewNetworkServiceRegistryServer creates a NetworkServiceRegistryServer chain element limiting the rate of the
+// registry calls. Register is limited by the peer and by the registered Network Service, Find is limited by the peer.
+// Rejected calls fail with codes.ResourceExhausted. Unregister is not limited.
+func NewNetworkServiceRegistryServer(limiter *ratelimit.Limiter) registry.NetworkServiceRegistryServer {
+	return &rateLimitNSServer{
+		limiter: limiter,
+	}
+}
+
+func (s *rateLimitNSServer) Register(ctx context.Context, ns *registry.NetworkService) (*registry.NetworkService, error) {
+	if err := s.limiter.Allow(ctx, ns.GetName()); err != nil {
+		return nil, err
+	}
+	return next.NetworkServiceRegistryServer(ctx).Register(ctx, ns)
+}
+
+func (s *rateLimitNSServer) Find(query *registry.NetworkServiceQuery, server registry.NetworkServiceRegistry_FindServer) error {
+	if err := s.limiter.Allow(server.Context()); err != nil {
+		return err
+	}
+	return next.NetworkServiceRegistryServer(server.Context()).Find(query, server)
+}
+
+func (s *rateLimitNSServer) Unregister(ctx context.Context, ns *registry.NetworkService) (*empty.Empty, error) {
+	return next.NetworkServiceRegistryServer(ctx).Unregister(ctx, ns)
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/ratelimit"
+)
+
+type registrant struct {
+	peer           string
+	expirationTime time.Time
+}
+
+type rateLimitNSEServer struct {
+	limiter *ratelimit.Limiter
+
+	lock        sync.Mutex
+	registrants map[string]*registrant
+}
+
+// NewNetworkServiceEndpointRegistryServer creates a NetworkServiceEndpointRegistryServer chain element limiting the
+// rate of the registry calls. Register is limited by the peer and by the Network Services the NSE is registered under,
+// Find is limited by the peer. Refreshes of the registered NSEs by the same peer are not limited until the NSEs
+// expire. Rejected calls fail with codes.ResourceExhausted. Unregister is not limited.
+func NewNetworkServiceEndpointRegistryServer(limiter *ratelimit.Limiter) registry.NetworkServiceEndpointRegistryServer {
+	return &rateLimitNSEServer{
+		limiter:     limiter,
+		registrants: make(map[string]*registrant),
+	}
+}
+
+func (s *rateLimitNSEServer) Register(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*registry.NetworkServiceEndpoint, error) {
+	peer := ratelimit.PeerIdentity(ctx)
+
+	if !s.isRefresh(ctx, nse.GetName(), peer) {
+		if err := s.limiter.Allow(ctx, nse.GetNetworkServiceNames()...); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := next.NetworkServiceEndpointRegistryServer(ctx).Register(ctx, nse)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.GetName() == "" {
+		return resp, nil
+	}
+
+	r := &registrant{
+		peer: peer,
+	}
+	if resp.GetExpirationTime() != nil {
+		r.expirationTime = resp.GetExpirationTime().AsTime().Local()
+	}
+
+	s.lock.Lock()
+	s.registrants[resp.GetName()] = r
+	s.lock.Unlock()
+
+	return resp, nil
+}
+
+func (s *rateLimitNSEServer) Find(query *registry.NetworkServiceEndpointQuery, server registry.NetworkServiceEndpointRegistry_FindServer) error {
+	if err := s.limiter.Allow(server.Context()); err != nil {
+		return err
+	}
+	return next.NetworkServiceEndpointRegistryServer(server.Context()).Find(query, server)
+}
+
+func (s *rateLimitNSEServer) Unregister(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*empty.Empty, error) {
+	s.lock.Lock()
+	delete(s.registrants, nse.GetName())
+	s.lock.Unlock()
+
+	return next.NetworkServiceEndpointRegistryServer(ctx).Unregister(ctx, nse)
+}
+
+// isRefresh returns true if the NSE is registered by the peer and is not expired yet
+func (s *rateLimitNSEServer) isRefresh(ctx context.Context, name, peer string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	r, ok := s.registrants[name]
+	if !ok {
+		return false
+	}
+	// The registrant is not tracked anymore when the NSE has expired
+	if !r.expirationTime.IsZero() && !clock.FromContext(ctx).Now().Before(r.expirationTime) {
+		delete(s.registrants, name)
+		return false
+	}
+	return r.peer == peer
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/ratelimit"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+	ratelimittools "github.com/networkservicemesh/sdk/pkg/tools/ratelimit"
+	"github.com/networkservicemesh/sdk/pkg/tools/sandbox"
+)
+
+func requireResourceExhausted(t *testing.T, err error) {
+	require.Error(t, err)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestRateLimitNSEServer(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	limiter := ratelimittools.NewLimiter(ctx, ratelimittools.WithConfig(&ratelimittools.Config{
+		Peers: map[string]ratelimittools.Limit{
+			"spiffe://test.com/a": {Rate: 0.001, Burst: 2},
+		},
+		NetworkServices: map[string]ratelimittools.Limit{
+			"ns-1": {Rate: 0.001, Burst: 1},
+		},
+	}))
+
+	s := next.NewNetworkServiceEndpointRegistryServer(
+		ratelimit.NewNetworkServiceEndpointRegistryServer(limiter),
+		memory.NewNetworkServiceEndpointRegistryServer(),
+	)
+	c := adapters.NetworkServiceEndpointServerToClient(s)
+
+	ctxA := sandbox.WithPeerSpiffeID(ctx, t, "spiffe://test.com/a")
+	ctxB := sandbox.WithPeerSpiffeID(ctx, t, "spiffe://test.com/b")
+
+	// 1. NS limit is shared by all the peers
+	nse, err := c.Register(ctxA, &registry.NetworkServiceEndpoint{Name: "nse-1", NetworkServiceNames: []string{"ns-1"}})
+	require.NoError(t, err)
+
+	_, err = c.Register(ctxB, &registry.NetworkServiceEndpoint{Name: "nse-2", NetworkServiceNames: []string{"ns-1"}})
+	requireResourceExhausted(t, err)
+
+	_, err = c.Register(ctxB, &registry.NetworkServiceEndpoint{Name: "nse-2", NetworkServiceNames: []string{"ns-2"}})
+	require.NoError(t, err)
+
+	// 2. Refresh of the registered NSE is not limited only for its registrant
+	nse, err = c.Register(ctxA, nse.Clone())
+	require.NoError(t, err)
+
+	_, err = c.Register(ctxB, nse.Clone())
+	requireResourceExhausted(t, err)
+
+	// 3. Find is limited by the peer
+	stream, err := c.Find(ctxA, &registry.NetworkServiceEndpointQuery{NetworkServiceEndpoint: new(registry.NetworkServiceEndpoint)})
+	require.NoError(t, err)
+	require.Len(t, registry.ReadNetworkServiceEndpointList(stream), 2)
+
+	_, err = c.Find(ctxA, &registry.NetworkServiceEndpointQuery{NetworkServiceEndpoint: new(registry.NetworkServiceEndpoint)})
+	requireResourceExhausted(t, err)
+
+	_, err = c.Find(ctxB, &registry.NetworkServiceEndpointQuery{NetworkServiceEndpoint: new(registry.NetworkServiceEndpoint)})
+	require.NoError(t, err)
+
+	// 4. Unregister is not limited
+	_, err = c.Unregister(ctxA, nse)
+	require.NoError(t, err)
+}
+
+func TestRateLimitNSEServer_RefreshExpired(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	clockMock.Set(time.Now())
+	ctx = clock.WithClock(ctx, clockMock)
+
+	limiter := ratelimittools.NewLimiter(ctx, ratelimittools.WithConfig(&ratelimittools.Config{
+		NetworkService: ratelimittools.Limit{Rate: 0.001, Burst: 1},
+	}))
+
+	c := adapters.NetworkServiceEndpointServerToClient(next.NewNetworkServiceEndpointRegistryServer(
+		ratelimit.NewNetworkServiceEndpointRegistryServer(limiter),
+		memory.NewNetworkServiceEndpointRegistryServer(),
+	))
+
+	nse, err := c.Register(ctx, &registry.NetworkServiceEndpoint{
+		Name:                "nse",
+		NetworkServiceNames: []string{"ns"},
+		ExpirationTime:      timestamppb.New(clockMock.Now().Add(time.Minute)),
+	})
+	require.NoError(t, err)
+
+	_, err = c.Register(ctx, nse.Clone())
+	require.NoError(t, err)
+
+	// Expired NSE is registered as a new one
+	clockMock.Add(time.Minute)
+
+	_, err = c.Register(ctx, nse.Clone())
+	requireResourceExhausted(t, err)
+}
+
+func TestRateLimitNSServer(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	limiter := ratelimittools.NewLimiter(ctx, ratelimittools.WithConfig(&ratelimittools.Config{
+		NetworkService: ratelimittools.Limit{Rate: 0.001, Burst: 1},
+	}))
+
+	s := next.NewNetworkServiceRegistryServer(
+		ratelimit.NewNetworkServiceRegistryServer(limiter),
+		memory.NewNetworkServiceRegistryServer(),
+	)
+	c := adapters.NetworkServiceServerToClient(s)
+
+	ns, err := c.Register(ctx, &registry.NetworkService{Name: "ns-1"})
+	require.NoError(t, err)
+
+	_, err = c.Register(ctx, &registry.NetworkService{Name: "ns-1"})
+	requireResourceExhausted(t, err)
+
+	_, err = c.Register(ctx, &registry.NetworkService{Name: "ns-2"})
+	require.NoError(t, err)
+
+	_, err = c.Unregister(ctx, ns)
+	require.NoError(t, err)
+}
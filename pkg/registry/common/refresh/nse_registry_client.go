@@ -35,13 +35,17 @@ type refreshNSEClient struct {
 	chainContext          context.Context
 	nseCancels            cancelsMap
 	defaultExpiryDuration time.Duration
+	retryInitialBackoff   time.Duration
+	retryMaxBackoff       time.Duration
 }
 
 // NewNetworkServiceEndpointRegistryClient creates new NetworkServiceEndpointRegistryClient that will refresh expiration
-// time for registered NSEs
+// time for registered NSEs. Failed refresh is retried with backoff until the registration expires.
 func NewNetworkServiceEndpointRegistryClient(options ...Option) registry.NetworkServiceEndpointRegistryClient {
 	c := &refreshNSEClient{
 		defaultExpiryDuration: time.Minute * 30,
+		retryInitialBackoff:   100 * time.Millisecond,
+		retryMaxBackoff:       5 * time.Second,
 		chainContext:          context.Background(),
 	}
 
@@ -62,22 +66,38 @@ func (c *refreshNSEClient) startRefresh(
 
 	t := time.Unix(nse.ExpirationTime.Seconds, int64(nse.ExpirationTime.Nanos))
 	go func() {
+		delay := 2 * time.Until(t) / 3
+		backoff := c.retryInitialBackoff
 		for {
 			select {
 			case <-ctx.Done():
 				return
-			case <-time.After(2 * time.Until(t) / 3):
+			case <-time.After(delay):
 				nse.ExpirationTime = timestamppb.New(time.Now().Add(expiryDuration))
 
 				res, err := client.Register(ctx, nse.Clone())
 				if err != nil {
-					logger.Errorf("failed to update registration: %s", err.Error())
-					return
+					// Registration is still alive until t, so it is worth retrying till then
+					if ctx.Err() != nil || time.Until(t) <= 0 {
+						logger.Errorf("failed to update registration: %s", err.Error())
+						return
+					}
+					delay = backoff
+					if until := time.Until(t); delay > until {
+						delay = until
+					}
+					logger.Warnf("failed to update registration, retrying in %s: %s", delay, err.Error())
+					if backoff *= 2; backoff > c.retryMaxBackoff {
+						backoff = c.retryMaxBackoff
+					}
+					continue
 				}
 
 				nse.ExpirationTime = res.ExpirationTime
 
 				t = nse.ExpirationTime.AsTime().Local()
+				delay = 2 * time.Until(t) / 3
+				backoff = c.retryInitialBackoff
 			}
 		}
 	}()
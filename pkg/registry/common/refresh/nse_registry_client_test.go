@@ -24,6 +24,7 @@ import (
 
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 	"google.golang.org/grpc"
@@ -172,6 +173,54 @@ func Test_RefreshNSEClient_SetsCorrectExpireTime(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func Test_RefreshNSEClient_RetriesFailedRefresh(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	countClient := new(requestCountClient)
+	failClient := &failRefreshClient{failures: 3}
+	client := next.NewNetworkServiceEndpointRegistryClient(
+		refresh.NewNetworkServiceEndpointRegistryClient(
+			refresh.WithChainContext(ctx),
+			refresh.WithDefaultExpiryDuration(time.Second),
+			refresh.WithRetryBackoff(testExpiryDuration/10, testExpiryDuration/10),
+		),
+		countClient,
+		failClient,
+	)
+
+	reg, err := client.Register(ctx, testNSE())
+	require.NoError(t, err)
+
+	// 1 registration + 3 failed refreshes + 1 successful refresh
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&countClient.requestCount) >= 5
+	}, 2*time.Second, testExpiryDuration/10)
+
+	_, err = client.Unregister(ctx, reg)
+	require.NoError(t, err)
+}
+
+type failRefreshClient struct {
+	calls    int32
+	failures int32
+
+	registry.NetworkServiceEndpointRegistryClient
+}
+
+func (c *failRefreshClient) Register(ctx context.Context, nse *registry.NetworkServiceEndpoint, opts ...grpc.CallOption) (*registry.NetworkServiceEndpoint, error) {
+	if call := atomic.AddInt32(&c.calls, 1); call > 1 && call <= c.failures+1 {
+		return nil, errors.New("refresh failed")
+	}
+	return next.NetworkServiceEndpointRegistryClient(ctx).Register(ctx, nse, opts...)
+}
+
+func (c *failRefreshClient) Unregister(ctx context.Context, nse *registry.NetworkServiceEndpoint, opts ...grpc.CallOption) (*empty.Empty, error) {
+	return next.NetworkServiceEndpointRegistryClient(ctx).Unregister(ctx, nse, opts...)
+}
+
 type requestCountClient struct {
 	requestCount int32
 
@@ -45,3 +45,12 @@ func WithChainContext(ctx context.Context) Option {
 		c.chainContext = ctx
 	})
 }
+
+// WithRetryBackoff sets the backoff before the first retry of the failed refresh and the max backoff, the backoff is
+// doubled after every retry
+func WithRetryBackoff(initialBackoff, maxBackoff time.Duration) Option {
+	return applierFunc(func(c *refreshNSEClient) {
+		c.retryInitialBackoff = initialBackoff
+		c.retryMaxBackoff = maxBackoff
+	})
+}
@@ -0,0 +1,21 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package replicate provides NSM registry chain elements replicating Network Services and Network Service Endpoints
+// between the peer registries. Changes are resolved with last-writer-wins versions, so all the replicas converge to
+// the same state. Replicated requests are accepted only from the peer registries with the verified identities set by
+// WithPeerIdentities.
+package replicate
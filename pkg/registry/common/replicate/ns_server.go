@@ -0,0 +1,110 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replicate
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+)
+
+type replicateNSServer struct {
+	replicator *replicator
+}
+
+// NewNetworkServiceRegistryServer creates a new NetworkServiceRegistryServer replicating NS registrations and
+// unregistrations between the replica with the name and its peers. It should be placed right before the memory
+// element, so it receives all the changes including the ones made by expire.
+func NewNetworkServiceRegistryServer(ctx context.Context, name string, peers []*url.URL, options ...Option) registry.NetworkServiceRegistryServer {
+	return &replicateNSServer{
+		replicator: newReplicator(ctx, name, peers, pushNS, newReplicateOptions(options...)),
+	}
+}
+
+func (s *replicateNSServer) Register(ctx context.Context, ns *registry.NetworkService) (*registry.NetworkService, error) {
+	v, replicated, err := s.replicator.replicatedVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !replicated {
+		resp, err := next.NetworkServiceRegistryServer(ctx).Register(ctx, ns)
+		if err != nil {
+			return nil, err
+		}
+		s.replicator.local(resp.Name, resp, false)
+		return resp, nil
+	}
+
+	if err := s.replicator.check(ns.Name, v); err != nil {
+		return nil, err
+	}
+
+	resp, err := next.NetworkServiceRegistryServer(ctx).Register(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+	s.replicator.replicated(resp.Name, v, resp, false)
+	return resp, nil
+}
+
+func (s *replicateNSServer) Find(query *registry.NetworkServiceQuery, server registry.NetworkServiceRegistry_FindServer) error {
+	return next.NetworkServiceRegistryServer(server.Context()).Find(query, server)
+}
+
+func (s *replicateNSServer) Unregister(ctx context.Context, ns *registry.NetworkService) (*empty.Empty, error) {
+	v, replicated, err := s.replicator.replicatedVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if replicated {
+		if err := s.replicator.check(ns.Name, v); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := next.NetworkServiceRegistryServer(ctx).Unregister(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	if replicated {
+		s.replicator.replicated(ns.Name, v, ns, true)
+	} else {
+		s.replicator.local(ns.Name, ns, true)
+	}
+	return resp, nil
+}
+
+func pushNS(ctx context.Context, cc grpc.ClientConnInterface, entity proto.Message, deleted bool) (err error) {
+	client := registry.NewNetworkServiceRegistryClient(cc)
+	ns := entity.(*registry.NetworkService)
+	if deleted {
+		_, err = client.Unregister(ctx, ns)
+	} else {
+		_, err = client.Register(ctx, ns)
+	}
+	return err
+}
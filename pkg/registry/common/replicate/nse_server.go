@@ -0,0 +1,112 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replicate
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/setid"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+)
+
+type replicateNSEServer struct {
+	replicator *replicator
+}
+
+// NewNetworkServiceEndpointRegistryServer creates a new NetworkServiceEndpointRegistryServer replicating NSE
+// registrations and unregistrations between the replica with the name and its peers. It should be placed right
+// before the memory element, so it receives all the changes including the ones made by expire.
+func NewNetworkServiceEndpointRegistryServer(ctx context.Context, name string, peers []*url.URL, options ...Option) registry.NetworkServiceEndpointRegistryServer {
+	return &replicateNSEServer{
+		replicator: newReplicator(ctx, name, peers, pushNSE, newReplicateOptions(options...)),
+	}
+}
+
+func (s *replicateNSEServer) Register(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*registry.NetworkServiceEndpoint, error) {
+	v, replicated, err := s.replicator.replicatedVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !replicated {
+		resp, err := next.NetworkServiceEndpointRegistryServer(ctx).Register(ctx, nse)
+		if err != nil {
+			return nil, err
+		}
+		s.replicator.local(resp.Name, resp, false)
+		return resp, nil
+	}
+
+	if err := s.replicator.check(nse.Name, v); err != nil {
+		return nil, err
+	}
+
+	// Replicated NSE already has the unique name set by the origin replica
+	resp, err := next.NetworkServiceEndpointRegistryServer(ctx).Register(setid.WithRegisteredName(ctx), nse)
+	if err != nil {
+		return nil, err
+	}
+	s.replicator.replicated(resp.Name, v, resp, false)
+	return resp, nil
+}
+
+func (s *replicateNSEServer) Find(query *registry.NetworkServiceEndpointQuery, server registry.NetworkServiceEndpointRegistry_FindServer) error {
+	return next.NetworkServiceEndpointRegistryServer(server.Context()).Find(query, server)
+}
+
+func (s *replicateNSEServer) Unregister(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*empty.Empty, error) {
+	v, replicated, err := s.replicator.replicatedVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if replicated {
+		if err := s.replicator.check(nse.Name, v); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := next.NetworkServiceEndpointRegistryServer(ctx).Unregister(ctx, nse)
+	if err != nil {
+		return nil, err
+	}
+
+	if replicated {
+		s.replicator.replicated(nse.Name, v, nse, true)
+	} else {
+		s.replicator.local(nse.Name, nse, true)
+	}
+	return resp, nil
+}
+
+func pushNSE(ctx context.Context, cc grpc.ClientConnInterface, entity proto.Message, deleted bool) (err error) {
+	client := registry.NewNetworkServiceEndpointRegistryClient(cc)
+	nse := entity.(*registry.NetworkServiceEndpoint)
+	if deleted {
+		_, err = client.Unregister(ctx, nse)
+	} else {
+		_, err = client.Register(ctx, nse)
+	}
+	return err
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replicate
+
+import (
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	defaultAntiEntropyInterval = 30 * time.Second
+	defaultTombstoneTTL        = 10 * time.Minute
+	defaultPushTimeout         = 5 * time.Second
+	defaultMaxClockSkew        = time.Minute
+)
+
+type replicateOptions struct {
+	dialOptions         []grpc.DialOption
+	antiEntropyInterval time.Duration
+	tombstoneTTL        time.Duration
+	pushTimeout         time.Duration
+	peerIdentities      map[string]bool
+	insecurePeers       bool
+	maxClockSkew        time.Duration
+}
+
+// Option is an option pattern for NewNetworkServiceRegistryServer, NewNetworkServiceEndpointRegistryServer
+type Option func(o *replicateOptions)
+
+// WithDialOptions sets gRPC Dial Options used to connect the peer registries
+func WithDialOptions(dialOptions ...grpc.DialOption) Option {
+	return func(o *replicateOptions) {
+		o.dialOptions = dialOptions
+	}
+}
+
+// WithAntiEntropyInterval sets how often the full replica state is pushed to the peers to repair the missed changes
+func WithAntiEntropyInterval(antiEntropyInterval time.Duration) Option {
+	return func(o *replicateOptions) {
+		o.antiEntropyInterval = antiEntropyInterval
+	}
+}
+
+// WithTombstoneTTL sets how long the unregistered entities are remembered to reject their stale registrations from
+// the peers. It should be greater than the time needed to deliver a change to all the peers.
+func WithTombstoneTTL(tombstoneTTL time.Duration) Option {
+	return func(o *replicateOptions) {
+		o.tombstoneTTL = tombstoneTTL
+	}
+}
+
+// WithPushTimeout sets timeout for a single request to the peer
+func WithPushTimeout(pushTimeout time.Duration) Option {
+	return func(o *replicateOptions) {
+		o.pushTimeout = pushTimeout
+	}
+}
+
+// WithPeerIdentities sets the SPIFFE IDs of the peer registries. Replicated requests are accepted only from the
+// callers with these verified identities, all the other ones are rejected.
+func WithPeerIdentities(spiffeIDs ...string) Option {
+	return func(o *replicateOptions) {
+		for _, spiffeID := range spiffeIDs {
+			o.peerIdentities[spiffeID] = true
+		}
+	}
+}
+
+// WithInsecurePeers accepts replicated requests from any caller. It should be used only when the peers are
+// connected without TLS, e.g. for testing.
+func WithInsecurePeers() Option {
+	return func(o *replicateOptions) {
+		o.insecurePeers = true
+	}
+}
+
+// WithMaxClockSkew sets how far in the future the replicated versions can be. Versions exceeding it are rejected, so
+// a peer with the broken clock can't make its changes permanent.
+func WithMaxClockSkew(maxClockSkew time.Duration) Option {
+	return func(o *replicateOptions) {
+		o.maxClockSkew = maxClockSkew
+	}
+}
+
+func newReplicateOptions(options ...Option) *replicateOptions {
+	o := &replicateOptions{
+		antiEntropyInterval: defaultAntiEntropyInterval,
+		tombstoneTTL:        defaultTombstoneTTL,
+		pushTimeout:         defaultPushTimeout,
+		peerIdentities:      make(map[string]bool),
+		maxClockSkew:        defaultMaxClockSkew,
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+	return o
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replicate
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/edwarnicke/serialize"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/spiffejwt"
+)
+
+// pushFunc sends the entity change to the peer registry
+type pushFunc func(ctx context.Context, cc grpc.ClientConnInterface, entity proto.Message, deleted bool) error
+
+type entry struct {
+	version version
+	entity  proto.Message
+	deleted bool
+	updated time.Time
+}
+
+type peer struct {
+	url      *url.URL
+	cc       *grpc.ClientConn
+	executor serialize.Executor
+	pending  int32
+}
+
+// replicator keeps the versions of the entities and pushes their changes to the peers
+type replicator struct {
+	ctx     context.Context
+	clock   clock.Clock
+	name    string
+	push    pushFunc
+	options *replicateOptions
+	peers   []*peer
+
+	lock    sync.Mutex
+	entries map[string]*entry
+}
+
+func newReplicator(ctx context.Context, name string, peerURLs []*url.URL, push pushFunc, options *replicateOptions) *replicator {
+	r := &replicator{
+		ctx:     ctx,
+		clock:   clock.FromContext(ctx),
+		name:    name,
+		push:    push,
+		options: options,
+		entries: make(map[string]*entry),
+	}
+	for _, u := range peerURLs {
+		// URL is copied, since the caller can still modify it, e.g. grpcutils.ListenAndServe sets the real address
+		peerURL := *u
+		r.peers = append(r.peers, &peer{url: &peerURL})
+	}
+
+	go r.antiEntropy()
+
+	return r
+}
+
+// local stores the local change of the entity and pushes it to the peers
+func (r *replicator) local(name string, entity proto.Message, deleted bool) {
+	r.lock.Lock()
+
+	v := version{
+		timestamp: r.clock.Now().UnixNano(),
+		replica:   r.name,
+	}
+	if e, ok := r.entries[name]; ok && e.version.timestamp >= v.timestamp {
+		v.timestamp = e.version.timestamp + 1
+	}
+	e := r.store(name, v, entity, deleted)
+
+	r.lock.Unlock()
+
+	for _, p := range r.peers {
+		r.pushToPeer(p, e)
+	}
+}
+
+// replicatedVersion returns the version of the request replicated from the peer registry. Version is accepted only
+// from the peers and only if it is not too far in the future.
+func (r *replicator) replicatedVersion(ctx context.Context) (v version, replicated bool, err error) {
+	v, replicated, err = replicatedVersion(ctx)
+	if err != nil {
+		return version{}, false, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if !replicated {
+		return version{}, false, nil
+	}
+
+	if err := r.authorizePeer(ctx); err != nil {
+		return version{}, false, status.Errorf(codes.PermissionDenied, "replicated request is not allowed: %s", err.Error())
+	}
+	if maxTimestamp := r.clock.Now().Add(r.options.maxClockSkew).UnixNano(); v.timestamp > maxTimestamp {
+		return version{}, false, status.Errorf(codes.InvalidArgument, "version is too far in the future: %s", v)
+	}
+	return v, true, nil
+}
+
+func (r *replicator) authorizePeer(ctx context.Context) error {
+	if r.options.insecurePeers {
+		return nil
+	}
+	spiffeID, err := spiffejwt.PeerSpiffeID(ctx)
+	if err != nil {
+		return err
+	}
+	if !r.options.peerIdentities[spiffeID.String()] {
+		return errors.Errorf("%s is not a peer registry", spiffeID)
+	}
+	return nil
+}
+
+// check returns an error if the local version of the entity is newer or the same as v
+func (r *replicator) check(name string, v version) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if e, ok := r.entries[name]; ok && !v.after(e.version) {
+		return status.Errorf(codes.Aborted, "stale version of %s: %s, actual: %s", name, v, e.version)
+	}
+	return nil
+}
+
+// replicated stores the change of the entity received from the peer
+func (r *replicator) replicated(name string, v version, entity proto.Message, deleted bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if e, ok := r.entries[name]; ok && !v.after(e.version) {
+		return
+	}
+	r.store(name, v, entity, deleted)
+}
+
+func (r *replicator) store(name string, v version, entity proto.Message, deleted bool) *entry {
+	e := &entry{
+		version: v,
+		entity:  proto.Clone(entity),
+		deleted: deleted,
+		updated: r.clock.Now(),
+	}
+	r.entries[name] = e
+	return e
+}
+
+func (r *replicator) pushToPeer(p *peer, e *entry) {
+	atomic.AddInt32(&p.pending, 1)
+	p.executor.AsyncExec(func() {
+		defer atomic.AddInt32(&p.pending, -1)
+
+		if r.ctx.Err() != nil {
+			return
+		}
+
+		logger := log.FromContext(r.ctx).WithField("replicator", r.name)
+		if p.cc == nil {
+			cc, err := grpc.DialContext(r.ctx, grpcutils.URLToTarget(p.url), r.options.dialOptions...)
+			if err != nil {
+				logger.Warnf("failed to dial peer %s: %s", p.url, err.Error())
+				return
+			}
+			p.cc = cc
+			go func() {
+				<-r.ctx.Done()
+				_ = cc.Close()
+			}()
+		}
+
+		ctx, cancel := r.clock.WithTimeout(withReplicatedVersion(r.ctx, e.version), r.options.pushTimeout)
+		defer cancel()
+
+		if err := r.push(ctx, p.cc, e.entity, e.deleted); err != nil && status.Code(errors.Cause(err)) != codes.Aborted {
+			logger.Warnf("failed to push %s to peer %s: %s", e.version, p.url, err.Error())
+		}
+	})
+}
+
+// antiEntropy periodically pushes all the entries to the peers, so the changes missed by the peers are repaired
+func (r *replicator) antiEntropy() {
+	ticker := r.clock.Ticker(r.options.antiEntropyInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C():
+			entries := r.collect()
+			for _, p := range r.peers {
+				// Peer is not able to handle the previous pushes yet, there is no need to overload it
+				if atomic.LoadInt32(&p.pending) > 0 {
+					continue
+				}
+				for _, e := range entries {
+					r.pushToPeer(p, e)
+				}
+			}
+		}
+	}
+}
+
+// collect removes the outdated tombstones and returns all the rest entries
+func (r *replicator) collect() []*entry {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	entries := make([]*entry, 0, len(r.entries))
+	for name, e := range r.entries {
+		if e.deleted && r.clock.Since(e.updated) > r.options.tombstoneTTL {
+			delete(r.entries, name)
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
@@ -0,0 +1,255 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replicate_test
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	registryserver "github.com/networkservicemesh/sdk/pkg/registry"
+	memorychain "github.com/networkservicemesh/sdk/pkg/registry/chains/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/replicate"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
+	"github.com/networkservicemesh/sdk/pkg/tools/sandbox"
+)
+
+const (
+	peerSpiffeID   = "spiffe://test.com/registry"
+	clientSpiffeID = "spiffe://test.com/nse"
+	expiryDuration = time.Minute
+	testWait       = 2 * time.Second
+	testTick       = 10 * time.Millisecond
+)
+
+func freeURLs(t *testing.T, count int) (urls []*url.URL) {
+	for i := 0; i < count; i++ {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		urls = append(urls, grpcutils.AddressToURL(listener.Addr()))
+		require.NoError(t, listener.Close())
+	}
+	return urls
+}
+
+func newReplica(ctx context.Context, urls []*url.URL, i int, options ...replicate.Option) registryserver.Registry {
+	var peers []*url.URL
+	for j, u := range urls {
+		if j != i {
+			peers = append(peers, u)
+		}
+	}
+	return memorychain.NewServer(ctx, expiryDuration, nil,
+		memorychain.WithDialOptions(grpc.WithInsecure()),
+		memorychain.WithReplication(fmt.Sprint("replica-", i), peers, append([]replicate.Option{replicate.WithInsecurePeers()}, options...)...))
+}
+
+func serve(ctx context.Context, u *url.URL, r registryserver.Registry) {
+	server := grpc.NewServer()
+	r.Register(server)
+	_ = grpcutils.ListenAndServe(ctx, u, server)
+}
+
+func findNSEs(t *testing.T, r registryserver.Registry) map[string]*registry.NetworkServiceEndpoint {
+	stream, err := adapters.NetworkServiceEndpointServerToClient(r.NetworkServiceEndpointRegistryServer()).Find(
+		context.Background(), &registry.NetworkServiceEndpointQuery{
+			NetworkServiceEndpoint: new(registry.NetworkServiceEndpoint),
+		})
+	require.NoError(t, err)
+
+	nses := make(map[string]*registry.NetworkServiceEndpoint)
+	for _, nse := range registry.ReadNetworkServiceEndpointList(stream) {
+		nses[nse.Name] = nse
+	}
+	return nses
+}
+
+func TestReplicate_RegisterUnregister(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	urls := freeURLs(t, 3)
+	var replicas []registryserver.Registry
+	for i, u := range urls {
+		replicas = append(replicas, newReplica(ctx, urls, i))
+		serve(ctx, u, replicas[i])
+	}
+
+	reg, err := replicas[0].NetworkServiceEndpointRegistryServer().Register(ctx, &registry.NetworkServiceEndpoint{
+		Name:                "nse",
+		NetworkServiceNames: []string{"ns"},
+	})
+	require.NoError(t, err)
+
+	// Registration should be replicated with the same name
+	for _, r := range replicas {
+		r := r
+		require.Eventually(t, func() bool {
+			_, ok := findNSEs(t, r)[reg.Name]
+			return ok
+		}, testWait, testTick)
+	}
+
+	// Refresh on the other replica
+	reg.Url = "tcp://1.1.1.1"
+	_, err = replicas[1].NetworkServiceEndpointRegistryServer().Register(ctx, reg.Clone())
+	require.NoError(t, err)
+
+	for _, r := range replicas {
+		r := r
+		require.Eventually(t, func() bool {
+			return findNSEs(t, r)[reg.Name].GetUrl() == reg.Url
+		}, testWait, testTick)
+	}
+
+	_, err = replicas[2].NetworkServiceEndpointRegistryServer().Unregister(ctx, reg.Clone())
+	require.NoError(t, err)
+
+	for _, r := range replicas {
+		r := r
+		require.Eventually(t, func() bool {
+			return len(findNSEs(t, r)) == 0
+		}, testWait, testTick)
+	}
+}
+
+func TestReplicate_AntiEntropy(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	urls := freeURLs(t, 2)
+
+	replica := newReplica(ctx, urls, 0,
+		replicate.WithAntiEntropyInterval(100*time.Millisecond),
+		replicate.WithPushTimeout(50*time.Millisecond))
+	serve(ctx, urls[0], replica)
+
+	reg, err := replica.NetworkServiceEndpointRegistryServer().Register(ctx, &registry.NetworkServiceEndpoint{
+		Name: "nse",
+	})
+	require.NoError(t, err)
+
+	// Peer starts after the registration has been pushed
+	time.Sleep(100 * time.Millisecond)
+
+	peer := newReplica(ctx, urls, 1)
+	serve(ctx, urls[1], peer)
+
+	require.Eventually(t, func() bool {
+		_, ok := findNSEs(t, peer)[reg.Name]
+		return ok
+	}, testWait, testTick)
+}
+
+func TestReplicate_StaleVersion(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mem := memory.NewNetworkServiceEndpointRegistryServer()
+	server := next.NewNetworkServiceEndpointRegistryServer(
+		replicate.NewNetworkServiceEndpointRegistryServer(ctx, "replica", nil, replicate.WithInsecurePeers()),
+		mem,
+	)
+
+	replicated := func(version string) context.Context {
+		return metadata.NewIncomingContext(ctx, metadata.Pairs("nsm-registry-replica-version", version))
+	}
+
+	_, err := server.Register(replicated("2/replica-1"), &registry.NetworkServiceEndpoint{Name: "nse", Url: "tcp://2.2.2.2"})
+	require.NoError(t, err)
+
+	_, err = server.Register(replicated("1/replica-2"), &registry.NetworkServiceEndpoint{Name: "nse", Url: "tcp://1.1.1.1"})
+	require.Error(t, err)
+	require.Equal(t, codes.Aborted, status.Code(err))
+
+	_, err = server.Unregister(replicated("2/replica-0"), &registry.NetworkServiceEndpoint{Name: "nse"})
+	require.Error(t, err)
+
+	// Same timestamp is resolved by the replica name
+	_, err = server.Register(replicated("2/replica-2"), &registry.NetworkServiceEndpoint{Name: "nse", Url: "tcp://3.3.3.3"})
+	require.NoError(t, err)
+
+	stream, err := adapters.NetworkServiceEndpointServerToClient(mem).Find(ctx, &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: new(registry.NetworkServiceEndpoint),
+	})
+	require.NoError(t, err)
+
+	nses := registry.ReadNetworkServiceEndpointList(stream)
+	require.Len(t, nses, 1)
+	require.Equal(t, "tcp://3.3.3.3", nses[0].Url)
+}
+
+func TestReplicate_PeerIdentities(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := next.NewNetworkServiceEndpointRegistryServer(
+		replicate.NewNetworkServiceEndpointRegistryServer(ctx, "replica", nil, replicate.WithPeerIdentities(peerSpiffeID)),
+		memory.NewNetworkServiceEndpointRegistryServer(),
+	)
+
+	replicated := func(ctx context.Context, spiffeID string, timestamp int64) context.Context {
+		md := metadata.Pairs("nsm-registry-replica-version", strconv.FormatInt(timestamp, 10)+"/replica-1")
+		if spiffeID != "" {
+			ctx = sandbox.WithPeerSpiffeID(ctx, t, spiffeID)
+			peerMD, _ := metadata.FromIncomingContext(ctx)
+			md = metadata.Join(peerMD, md)
+		}
+		return metadata.NewIncomingContext(ctx, md)
+	}
+	nse := &registry.NetworkServiceEndpoint{Name: "nse"}
+
+	// Caller without the verified identity
+	_, err := server.Register(replicated(ctx, "", time.Now().UnixNano()), nse.Clone())
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Caller with the verified identity other than the peer one
+	_, err = server.Register(replicated(ctx, clientSpiffeID, time.Now().UnixNano()), nse.Clone())
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Peer version too far in the future
+	_, err = server.Register(replicated(ctx, peerSpiffeID, math.MaxInt64), nse.Clone())
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = server.Register(replicated(ctx, peerSpiffeID, time.Now().UnixNano()), nse.Clone())
+	require.NoError(t, err)
+}
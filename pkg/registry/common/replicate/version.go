@@ -0,0 +1,88 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replicate
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	versionMDKey  = "nsm-registry-replica-version"
+	versionFormat = 10
+)
+
+// version is a last-writer-wins version of the registry entity. Versions with equal timestamps are ordered by the
+// replica name.
+type version struct {
+	timestamp int64
+	replica   string
+}
+
+func (v version) after(o version) bool {
+	if v.timestamp != o.timestamp {
+		return v.timestamp > o.timestamp
+	}
+	return v.replica > o.replica
+}
+
+func (v version) String() string {
+	return strconv.FormatInt(v.timestamp, versionFormat) + "/" + v.replica
+}
+
+func parseVersion(s string) (version, error) {
+	split := strings.SplitN(s, "/", 2)
+	if len(split) != 2 {
+		return version{}, errors.Errorf("invalid version: %s", s)
+	}
+	timestamp, err := strconv.ParseInt(split[0], versionFormat, 64)
+	if err != nil {
+		return version{}, errors.Wrapf(err, "invalid version: %s", s)
+	}
+	return version{
+		timestamp: timestamp,
+		replica:   split[1],
+	}, nil
+}
+
+// withReplicatedVersion returns a new context with the outgoing metadata marking the request as replicated with the
+// version v
+func withReplicatedVersion(parent context.Context, v version) context.Context {
+	return metadata.AppendToOutgoingContext(parent, versionMDKey, v.String())
+}
+
+// replicatedVersion returns the version of the request replicated from the peer registry. Requests from the
+// clients have no version.
+func replicatedVersion(ctx context.Context) (v version, ok bool, err error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return version{}, false, nil
+	}
+	values := md.Get(versionMDKey)
+	if len(values) == 0 {
+		return version{}, false, nil
+	}
+	v, err = parseVersion(values[len(values)-1])
+	if err != nil {
+		return version{}, false, err
+	}
+	return v, true, nil
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package setid
+
+import (
+	"context"
+)
+
+type registeredNameKeyType struct{}
+
+// WithRegisteredName returns a new context marking the registration as a repeated registration of the already
+// registered NSE (e.g. restored after the registry restart), so its name is kept as is
+func WithRegisteredName(parent context.Context) context.Context {
+	if parent == nil {
+		panic("cannot create context from nil parent")
+	}
+	return context.WithValue(parent, registeredNameKeyType{}, true)
+}
+
+// IsRegisteredName returns true if the registration is marked with WithRegisteredName
+func IsRegisteredName(ctx context.Context) bool {
+	registered, _ := ctx.Value(registeredNameKeyType{}).(bool)
+	return registered
+}
@@ -46,7 +46,7 @@ func (s *setIDServer) Register(ctx context.Context, nse *registry.NetworkService
 		return nil, err
 	}
 
-	if _, ok := s.names.Load(reg.Name); !ok && reg.Name == name {
+	if _, ok := s.names.Load(reg.Name); !ok && reg.Name == name && !IsRegisteredName(ctx) {
 		if reg.Name == "" {
 			reg.Name = strings.Join(reg.NetworkServiceNames, "-")
 		}
@@ -28,6 +28,8 @@ import (
 
 	"github.com/networkservicemesh/api/pkg/api/registry"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
 )
 
 func testNSE() *registry.NetworkServiceEndpoint {
@@ -101,6 +103,29 @@ func TestSetIDServer_RemoteRegistry(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestSetIDServer_IgnoreUnknownFields(t *testing.T) {
+	server := setid.NewNetworkServiceEndpointRegistryServer()
+
+	// Remote client can't skip the name generation with the unknown fields
+	nse := testNSE()
+	m := nse.ProtoReflect()
+	m.SetUnknown(protowire.AppendVarint(protowire.AppendTag(nil, 2045, protowire.VarintType), 1))
+
+	b, err := proto.Marshal(nse)
+	require.NoError(t, err)
+
+	received := new(registry.NetworkServiceEndpoint)
+	require.NoError(t, proto.Unmarshal(b, received))
+
+	reg, err := server.Register(context.Background(), received)
+	require.NoError(t, err)
+
+	require.NotEqual(t, nse.Name, reg.Name)
+
+	_, err = server.Unregister(context.Background(), reg)
+	require.NoError(t, err)
+}
+
 type captureNameRegistryServer struct {
 	name string
 
@@ -0,0 +1,175 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/spiffejwt"
+	"github.com/networkservicemesh/sdk/pkg/tools/token"
+)
+
+// tombstoneTTL is a time the owner of the unregistered object is kept, so the unregister events are still sent to the
+// watchers of the owner tenant and the name is not taken by the other tenant in the meantime
+const tombstoneTTL = time.Minute
+
+// TenantFunc returns the tenant of the caller
+type TenantFunc func(ctx context.Context) (string, error)
+
+// FromSPIFFEIDPathSegment returns TenantFunc taking the tenant from the path segment of the caller SPIFFE ID, e.g.
+// "team-a" is the segment 0 of "spiffe://example.org/team-a/nsc". SPIFFE ID is the verified identity of the direct
+// caller, see spiffejwt.PeerSpiffeID.
+func FromSPIFFEIDPathSegment(index int) TenantFunc {
+	return func(ctx context.Context) (string, error) {
+		spiffeID, err := spiffejwt.PeerSpiffeID(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		segments := strings.Split(strings.Trim(spiffeID.Path(), "/"), "/")
+		if index < 0 || index >= len(segments) || segments[index] == "" {
+			return "", errors.Errorf("SPIFFE ID has no path segment %d: %s", index, spiffeID)
+		}
+		return segments[index], nil
+	}
+}
+
+// FromClaim returns TenantFunc taking the tenant from the string claim of the caller token. Token should be signed by
+// the verified direct caller, see spiffejwt.PeerSpiffeID.
+func FromClaim(claim string) TenantFunc {
+	return func(ctx context.Context) (string, error) {
+		if _, err := spiffejwt.PeerSpiffeID(ctx); err != nil {
+			return "", err
+		}
+
+		tok, _, err := token.FromContext(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		// Token signature is already verified by spiffejwt.PeerSpiffeID
+		claims := make(jwt.MapClaims)
+		if _, _, err := new(jwt.Parser).ParseUnverified(tok, claims); err != nil {
+			return "", errors.Wrap(err, "failed to parse token")
+		}
+		tenant, ok := claims[claim].(string)
+		if !ok || tenant == "" {
+			return "", errors.Errorf("token has no string claim: %s", claim)
+		}
+		return tenant, nil
+	}
+}
+
+func tenantFromContext(ctx context.Context, tenantFunc TenantFunc) (string, error) {
+	tenant, err := tenantFunc(ctx)
+	if err != nil {
+		return "", status.Errorf(codes.PermissionDenied, "failed to get tenant: %s", err.Error())
+	}
+	return tenant, nil
+}
+
+type owner struct {
+	tenant  string
+	shared  bool
+	expires time.Time
+	deleted time.Time
+}
+
+// isStale returns true if the object has been deleted or expired more than tombstoneTTL ago
+func (o *owner) isStale(now time.Time) bool {
+	deleted := o.deleted
+	if deleted.IsZero() {
+		deleted = o.expires
+	}
+	return !deleted.IsZero() && now.Sub(deleted) > tombstoneTTL
+}
+
+// visible returns true if the object is visible for the tenant
+func (o owner) visible(tenant string) bool {
+	return o.shared || o.tenant == tenant
+}
+
+// owners keeps the tenants of the registry objects by the object names
+type owners struct {
+	lock    sync.Mutex
+	entries map[string]*owner
+}
+
+func (o *owners) load(name string, now time.Time) (owner, bool) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	e, ok := o.entries[name]
+	if !ok {
+		return owner{}, false
+	}
+	if e.isStale(now) {
+		delete(o.entries, name)
+		return owner{}, false
+	}
+	return *e, true
+}
+
+// check returns an error if the object is owned by the other tenant
+func (o *owners) check(name, tenant string, now time.Time) error {
+	if name == "" {
+		return nil
+	}
+	if e, ok := o.load(name, now); ok && e.tenant != tenant {
+		return status.Errorf(codes.PermissionDenied, "%s is owned by the other tenant", name)
+	}
+	return nil
+}
+
+// store sets the owner of the object. The object with non-zero expires is considered deleted after the expiration
+// time, so the expired objects unregistered bypassing this element (e.g. by expire) are released by the owner.
+func (o *owners) store(name, tenant string, shared bool, expires time.Time) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if o.entries == nil {
+		o.entries = make(map[string]*owner)
+	}
+	o.entries[name] = &owner{
+		tenant:  tenant,
+		shared:  shared,
+		expires: expires,
+	}
+}
+
+// delete keeps the owner as a tombstone for the tombstoneTTL and removes the expired tombstones
+func (o *owners) delete(name string, now time.Time) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	for n, e := range o.entries {
+		if e.isStale(now) {
+			delete(o.entries, n)
+		}
+	}
+	if e, ok := o.entries[name]; ok {
+		e.deleted = now
+	}
+}
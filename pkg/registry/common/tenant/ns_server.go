@@ -0,0 +1,119 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+)
+
+type tenantNSServer struct {
+	*tenantOptions
+	owners owners
+}
+
+// NewNetworkServiceRegistryServer creates a NetworkServiceRegistryServer chain element scoping the Network Services
+// to the tenant of the caller. Network Service registered by one tenant cannot be registered or unregistered by the
+// other tenants and is found only by the tenant itself, unless it is shared. Network Services registered bypassing
+// this element are visible for all the tenants.
+func NewNetworkServiceRegistryServer(options ...Option) registry.NetworkServiceRegistryServer {
+	return &tenantNSServer{
+		tenantOptions: newTenantOptions(options...),
+	}
+}
+
+func (s *tenantNSServer) Register(ctx context.Context, ns *registry.NetworkService) (*registry.NetworkService, error) {
+	tenant, err := s.check(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := next.NetworkServiceRegistryServer(ctx).Register(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	s.owners.store(resp.Name, tenant, s.shared[resp.Name] == tenant, time.Time{})
+
+	return resp, nil
+}
+
+func (s *tenantNSServer) Find(query *registry.NetworkServiceQuery, server registry.NetworkServiceRegistry_FindServer) error {
+	tenant, err := tenantFromContext(server.Context(), s.tenantFunc)
+	if err != nil {
+		return err
+	}
+
+	return next.NetworkServiceRegistryServer(server.Context()).Find(query, &tenantNSFindServer{
+		NetworkServiceRegistry_FindServer: server,
+		server:                            s,
+		tenant:                            tenant,
+	})
+}
+
+func (s *tenantNSServer) Unregister(ctx context.Context, ns *registry.NetworkService) (*empty.Empty, error) {
+	if _, err := s.check(ctx, ns); err != nil {
+		return nil, err
+	}
+
+	resp, err := next.NetworkServiceRegistryServer(ctx).Unregister(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	s.owners.delete(ns.Name, clock.FromContext(ctx).Now())
+
+	return resp, nil
+}
+
+func (s *tenantNSServer) check(ctx context.Context, ns *registry.NetworkService) (string, error) {
+	tenant, err := tenantFromContext(ctx, s.tenantFunc)
+	if err != nil {
+		return "", err
+	}
+
+	if sharedBy, ok := s.shared[ns.Name]; ok && sharedBy != tenant {
+		return "", status.Errorf(codes.PermissionDenied, "%s is shared by the other tenant", ns.Name)
+	}
+	if err := s.owners.check(ns.Name, tenant, clock.FromContext(ctx).Now()); err != nil {
+		return "", err
+	}
+
+	return tenant, nil
+}
+
+type tenantNSFindServer struct {
+	registry.NetworkServiceRegistry_FindServer
+	server *tenantNSServer
+	tenant string
+}
+
+func (s *tenantNSFindServer) Send(ns *registry.NetworkService) error {
+	if o, ok := s.server.owners.load(ns.Name, clock.FromContext(s.Context()).Now()); ok && !o.visible(s.tenant) {
+		return nil
+	}
+	return s.NetworkServiceRegistry_FindServer.Send(ns)
+}
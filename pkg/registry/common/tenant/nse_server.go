@@ -0,0 +1,129 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+)
+
+type tenantNSEServer struct {
+	*tenantOptions
+	owners owners
+}
+
+// NewNetworkServiceEndpointRegistryServer creates a NetworkServiceEndpointRegistryServer chain element scoping the
+// NSEs to the tenant of the caller. NSE registered by one tenant cannot be registered or unregistered by the other
+// tenants and is found only by the tenant itself, unless it is registered by the tenant sharing one of its Network
+// Services. NSEs registered bypassing this element are visible for all the tenants. Names of the expired NSEs are
+// released the same way as of the unregistered ones, so the element can be placed before the expire element.
+func NewNetworkServiceEndpointRegistryServer(options ...Option) registry.NetworkServiceEndpointRegistryServer {
+	return &tenantNSEServer{
+		tenantOptions: newTenantOptions(options...),
+	}
+}
+
+func (s *tenantNSEServer) Register(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*registry.NetworkServiceEndpoint, error) {
+	tenant, err := s.check(ctx, nse)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := next.NetworkServiceEndpointRegistryServer(ctx).Register(ctx, nse)
+	if err != nil {
+		return nil, err
+	}
+
+	var expires time.Time
+	if resp.GetExpirationTime() != nil {
+		expires = resp.GetExpirationTime().AsTime()
+	}
+	s.owners.store(resp.Name, tenant, s.isShared(resp, tenant), expires)
+
+	return resp, nil
+}
+
+func (s *tenantNSEServer) Find(query *registry.NetworkServiceEndpointQuery, server registry.NetworkServiceEndpointRegistry_FindServer) error {
+	tenant, err := tenantFromContext(server.Context(), s.tenantFunc)
+	if err != nil {
+		return err
+	}
+
+	return next.NetworkServiceEndpointRegistryServer(server.Context()).Find(query, &tenantNSEFindServer{
+		NetworkServiceEndpointRegistry_FindServer: server,
+		server: s,
+		tenant: tenant,
+	})
+}
+
+func (s *tenantNSEServer) Unregister(ctx context.Context, nse *registry.NetworkServiceEndpoint) (*empty.Empty, error) {
+	if _, err := s.check(ctx, nse); err != nil {
+		return nil, err
+	}
+
+	resp, err := next.NetworkServiceEndpointRegistryServer(ctx).Unregister(ctx, nse)
+	if err != nil {
+		return nil, err
+	}
+
+	s.owners.delete(nse.Name, clock.FromContext(ctx).Now())
+
+	return resp, nil
+}
+
+func (s *tenantNSEServer) check(ctx context.Context, nse *registry.NetworkServiceEndpoint) (string, error) {
+	tenant, err := tenantFromContext(ctx, s.tenantFunc)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.owners.check(nse.Name, tenant, clock.FromContext(ctx).Now()); err != nil {
+		return "", err
+	}
+
+	return tenant, nil
+}
+
+// isShared returns true if the NSE is registered by the tenant under any of the Network Services shared by the tenant
+func (s *tenantNSEServer) isShared(nse *registry.NetworkServiceEndpoint, tenant string) bool {
+	for _, ns := range nse.NetworkServiceNames {
+		if sharedBy, ok := s.shared[ns]; ok && sharedBy == tenant {
+			return true
+		}
+	}
+	return false
+}
+
+type tenantNSEFindServer struct {
+	registry.NetworkServiceEndpointRegistry_FindServer
+	server *tenantNSEServer
+	tenant string
+}
+
+func (s *tenantNSEFindServer) Send(nse *registry.NetworkServiceEndpoint) error {
+	if o, ok := s.server.owners.load(nse.Name, clock.FromContext(s.Context()).Now()); ok && !o.visible(s.tenant) {
+		return nil
+	}
+	return s.NetworkServiceEndpointRegistry_FindServer.Send(nse)
+}
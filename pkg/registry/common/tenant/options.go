@@ -0,0 +1,54 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant
+
+type tenantOptions struct {
+	tenantFunc TenantFunc
+	shared     map[string]string
+}
+
+// Option is an option pattern for NewNetworkServiceRegistryServer, NewNetworkServiceEndpointRegistryServer
+type Option func(o *tenantOptions)
+
+// WithTenantFunc sets the function getting the tenant of the caller. By default, the tenant is the first path segment
+// of the caller SPIFFE ID.
+func WithTenantFunc(tenantFunc TenantFunc) Option {
+	return func(o *tenantOptions) {
+		o.tenantFunc = tenantFunc
+	}
+}
+
+// WithSharedNetworkServices shares the Network Services of the tenant with all the other tenants. Only the tenant can
+// register the shared Network Services, their NSEs registered by the tenant are also visible for all the tenants.
+func WithSharedNetworkServices(tenant string, networkServices ...string) Option {
+	return func(o *tenantOptions) {
+		for _, ns := range networkServices {
+			o.shared[ns] = tenant
+		}
+	}
+}
+
+func newTenantOptions(options ...Option) *tenantOptions {
+	o := &tenantOptions{
+		tenantFunc: FromSPIFFEIDPathSegment(0),
+		shared:     make(map[string]string),
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+	return o
+}
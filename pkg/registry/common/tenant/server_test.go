@@ -0,0 +1,297 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/expire"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/tenant"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+	"github.com/networkservicemesh/sdk/pkg/tools/sandbox"
+)
+
+const (
+	spiffeIDA = "spiffe://test.com/a/nse"
+	spiffeIDB = "spiffe://test.com/b/nse"
+)
+
+// withToken returns ctx of the caller having the TLS certificate for the claims subject and the token with the claims
+// signed by the certificate key
+func withToken(ctx context.Context, t *testing.T, claims jwt.MapClaims) context.Context {
+	svid, err := sandbox.NewTestSVID(claims["sub"].(string))
+	require.NoError(t, err)
+
+	ctx, err = sandbox.WithPeerSVID(ctx, svid)
+	require.NoError(t, err)
+
+	tok, err := jwt.NewWithClaims(jwt.SigningMethodES256, claims).SignedString(svid.PrivateKey)
+	require.NoError(t, err)
+
+	return metadata.NewIncomingContext(ctx, metadata.Pairs(
+		"nsm-client-token", tok,
+		"nsm-client-token-expires", time.Now().Add(time.Hour).Format(time.RFC3339Nano),
+	))
+}
+
+func requirePermissionDenied(t *testing.T, err error) {
+	require.Error(t, err)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func findNSs(ctx context.Context, t *testing.T, server registry.NetworkServiceRegistryServer) (names []string) {
+	stream, err := adapters.NetworkServiceServerToClient(server).Find(ctx, &registry.NetworkServiceQuery{
+		NetworkService: new(registry.NetworkService),
+	})
+	require.NoError(t, err)
+
+	for _, ns := range registry.ReadNetworkServiceList(stream) {
+		names = append(names, ns.Name)
+	}
+	return names
+}
+
+func findNSEs(ctx context.Context, t *testing.T, server registry.NetworkServiceEndpointRegistryServer) (names []string) {
+	stream, err := adapters.NetworkServiceEndpointServerToClient(server).Find(ctx, &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: new(registry.NetworkServiceEndpoint),
+	})
+	require.NoError(t, err)
+
+	for _, nse := range registry.ReadNetworkServiceEndpointList(stream) {
+		names = append(names, nse.Name)
+	}
+	return names
+}
+
+func TestTenantNSServer(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := next.NewNetworkServiceRegistryServer(
+		tenant.NewNetworkServiceRegistryServer(tenant.WithSharedNetworkServices("a", "dns")),
+		memory.NewNetworkServiceRegistryServer(),
+	)
+
+	ctxA, ctxB := sandbox.WithPeerSpiffeID(ctx, t, spiffeIDA), sandbox.WithPeerSpiffeID(ctx, t, spiffeIDB)
+
+	_, err := server.Register(ctxA, &registry.NetworkService{Name: "ns"})
+	require.NoError(t, err)
+
+	_, err = server.Register(ctxA, &registry.NetworkService{Name: "dns"})
+	require.NoError(t, err)
+
+	// NS cannot be shadowed by the other tenant
+	_, err = server.Register(ctxB, &registry.NetworkService{Name: "ns"})
+	requirePermissionDenied(t, err)
+
+	_, err = server.Register(ctxB, &registry.NetworkService{Name: "dns"})
+	requirePermissionDenied(t, err)
+
+	_, err = server.Unregister(ctxB, &registry.NetworkService{Name: "ns"})
+	requirePermissionDenied(t, err)
+
+	// Shared NS is visible for the other tenant
+	require.ElementsMatch(t, []string{"ns", "dns"}, findNSs(ctxA, t, server))
+	require.ElementsMatch(t, []string{"dns"}, findNSs(ctxB, t, server))
+
+	// Caller without a tenant is denied
+	_, err = server.Register(ctx, &registry.NetworkService{Name: "ns-2"})
+	requirePermissionDenied(t, err)
+
+	_, err = adapters.NetworkServiceServerToClient(server).Find(ctx, &registry.NetworkServiceQuery{
+		NetworkService: new(registry.NetworkService),
+	})
+	requirePermissionDenied(t, err)
+}
+
+func TestTenantNSEServer(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	clockMock.Set(time.Now())
+	ctx = clock.WithClock(ctx, clockMock)
+
+	server := next.NewNetworkServiceEndpointRegistryServer(
+		tenant.NewNetworkServiceEndpointRegistryServer(tenant.WithSharedNetworkServices("a", "dns")),
+		memory.NewNetworkServiceEndpointRegistryServer(),
+	)
+
+	ctxA, ctxB := sandbox.WithPeerSpiffeID(ctx, t, spiffeIDA), sandbox.WithPeerSpiffeID(ctx, t, spiffeIDB)
+
+	_, err := server.Register(ctxA, &registry.NetworkServiceEndpoint{
+		Name:                "nse-a",
+		NetworkServiceNames: []string{"ns"},
+	})
+	require.NoError(t, err)
+
+	_, err = server.Register(ctxA, &registry.NetworkServiceEndpoint{
+		Name:                "nse-dns",
+		NetworkServiceNames: []string{"dns"},
+	})
+	require.NoError(t, err)
+
+	// NSE registered by the other tenant under the shared NS is not shared
+	_, err = server.Register(ctxB, &registry.NetworkServiceEndpoint{
+		Name:                "nse-b",
+		NetworkServiceNames: []string{"ns", "dns"},
+	})
+	require.NoError(t, err)
+
+	_, err = server.Register(ctxB, &registry.NetworkServiceEndpoint{Name: "nse-a"})
+	requirePermissionDenied(t, err)
+
+	require.ElementsMatch(t, []string{"nse-a", "nse-dns"}, findNSEs(ctxA, t, server))
+	require.ElementsMatch(t, []string{"nse-dns", "nse-b"}, findNSEs(ctxB, t, server))
+
+	// Name of the unregistered NSE is kept by the tenant for a while
+	_, err = server.Unregister(ctxA, &registry.NetworkServiceEndpoint{Name: "nse-a"})
+	require.NoError(t, err)
+
+	_, err = server.Register(ctxB, &registry.NetworkServiceEndpoint{Name: "nse-a"})
+	requirePermissionDenied(t, err)
+
+	clockMock.Add(time.Hour)
+
+	_, err = server.Register(ctxB, &registry.NetworkServiceEndpoint{Name: "nse-a"})
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, []string{"nse-dns"}, findNSEs(ctxA, t, server))
+}
+
+func TestTenant_FromClaim(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := next.NewNetworkServiceRegistryServer(
+		tenant.NewNetworkServiceRegistryServer(tenant.WithTenantFunc(tenant.FromClaim("tenant"))),
+		memory.NewNetworkServiceRegistryServer(),
+	)
+
+	ctxA := withToken(ctx, t, jwt.MapClaims{"sub": spiffeIDB, "tenant": "a"})
+	ctxB := withToken(ctx, t, jwt.MapClaims{"sub": spiffeIDA, "tenant": "b"})
+
+	_, err := server.Register(ctxA, &registry.NetworkService{Name: "ns"})
+	require.NoError(t, err)
+
+	_, err = server.Register(ctxB, &registry.NetworkService{Name: "ns"})
+	requirePermissionDenied(t, err)
+
+	require.Equal(t, []string{"ns"}, findNSs(ctxA, t, server))
+	require.Empty(t, findNSs(ctxB, t, server))
+
+	// Token without the claim is denied
+	_, err = server.Register(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDA), &registry.NetworkService{Name: "ns-2"})
+	requirePermissionDenied(t, err)
+}
+
+func TestTenant_ForgedToken(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := next.NewNetworkServiceRegistryServer(
+		tenant.NewNetworkServiceRegistryServer(),
+		memory.NewNetworkServiceRegistryServer(),
+	)
+
+	_, err := server.Register(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDA), &registry.NetworkService{Name: "ns"})
+	require.NoError(t, err)
+
+	tok, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.StandardClaims{Subject: spiffeIDA}).SignedString([]byte("super secret"))
+	require.NoError(t, err)
+	md := metadata.Pairs(
+		"nsm-client-token", tok,
+		"nsm-client-token-expires", time.Now().Add(time.Hour).Format(time.RFC3339Nano),
+	)
+
+	// Forged token without the peer certificate
+	_, err = server.Unregister(metadata.NewIncomingContext(ctx, md), &registry.NetworkService{Name: "ns"})
+	requirePermissionDenied(t, err)
+
+	// Forged token sent by the other tenant
+	ctxB := metadata.NewIncomingContext(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDB), md)
+	_, err = server.Unregister(ctxB, &registry.NetworkService{Name: "ns"})
+	requirePermissionDenied(t, err)
+
+	require.Equal(t, []string{"ns"}, findNSs(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDA), t, server))
+}
+
+func TestTenantNSEServer_Expire(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	clockMock.Set(time.Now())
+	ctx = clock.WithClock(ctx, clockMock)
+
+	server := next.NewNetworkServiceEndpointRegistryServer(
+		tenant.NewNetworkServiceEndpointRegistryServer(),
+		expire.NewNetworkServiceEndpointRegistryServer(ctx, time.Hour),
+		memory.NewNetworkServiceEndpointRegistryServer(),
+	)
+
+	ctxA, ctxB := sandbox.WithPeerSpiffeID(ctx, t, spiffeIDA), sandbox.WithPeerSpiffeID(ctx, t, spiffeIDB)
+
+	_, err := server.Register(ctxA, &registry.NetworkServiceEndpoint{
+		Name:           "nse-a",
+		ExpirationTime: timestamppb.New(time.Now().Add(100 * time.Millisecond)),
+	})
+	require.NoError(t, err)
+
+	// expire unregisters the NSE bypassing the tenant element
+	require.Eventually(t, func() bool {
+		return len(findNSEs(ctxA, t, server)) == 0
+	}, time.Second, 10*time.Millisecond)
+
+	// Name of the expired NSE is kept by the tenant for a while
+	_, err = server.Register(ctxB, &registry.NetworkServiceEndpoint{Name: "nse-a"})
+	requirePermissionDenied(t, err)
+
+	clockMock.Add(time.Hour)
+
+	_, err = server.Register(ctxB, &registry.NetworkServiceEndpoint{Name: "nse-a"})
+	require.NoError(t, err)
+
+	require.Empty(t, findNSEs(ctxA, t, server))
+	require.Equal(t, []string{"nse-a"}, findNSEs(ctxB, t, server))
+}
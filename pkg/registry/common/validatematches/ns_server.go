@@ -0,0 +1,65 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package validatematches provides a NetworkServiceRegistryServer chain element rejecting the Network Services with
+// the invalid match selectors
+package validatematches
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/labelselector"
+)
+
+type validateMatchesNSServer struct{}
+
+// NewNetworkServiceRegistryServer - creates a NetworkServiceRegistryServer parsing the source and destination
+// selectors of the Network Service matches on Register. Network Service with the invalid selector is rejected with
+// codes.InvalidArgument, so it doesn't fail every Request later.
+func NewNetworkServiceRegistryServer() registry.NetworkServiceRegistryServer {
+	return new(validateMatchesNSServer)
+}
+
+func (s *validateMatchesNSServer) Register(ctx context.Context, ns *registry.NetworkService) (*registry.NetworkService, error) {
+	for i, match := range ns.GetMatches() {
+		if _, err := labelselector.Parse(match.GetSourceSelector()); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid source selector in the match %d of the Network Service %s: %s",
+				i, ns.GetName(), err.Error())
+		}
+		for j, route := range match.GetRoutes() {
+			if _, err := labelselector.Parse(route.GetDestinationSelector()); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid destination selector in the route %d of the match %d of the Network Service %s: %s",
+					j, i, ns.GetName(), err.Error())
+			}
+		}
+	}
+	return next.NetworkServiceRegistryServer(ctx).Register(ctx, ns)
+}
+
+func (s *validateMatchesNSServer) Find(query *registry.NetworkServiceQuery, server registry.NetworkServiceRegistry_FindServer) error {
+	return next.NetworkServiceRegistryServer(server.Context()).Find(query, server)
+}
+
+func (s *validateMatchesNSServer) Unregister(ctx context.Context, ns *registry.NetworkService) (*empty.Empty, error) {
+	return next.NetworkServiceRegistryServer(ctx).Unregister(ctx, ns)
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validatematches_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/validatematches"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+)
+
+func TestValidateMatchesNSServer(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	server := next.NewNetworkServiceRegistryServer(
+		validatematches.NewNetworkServiceRegistryServer(),
+		memory.NewNetworkServiceRegistryServer(),
+	)
+
+	_, err := server.Register(context.Background(), &registry.NetworkService{
+		Name: "valid",
+		Matches: []*registry.Match{
+			{
+				SourceSelector: map[string]string{"app": "in (firewall, vpn)"},
+				Routes: []*registry.Destination{
+					{DestinationSelector: map[string]string{"app": "*", "!canary": ""}},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = server.Register(context.Background(), &registry.NetworkService{
+		Name: "invalid-source",
+		Matches: []*registry.Match{
+			{SourceSelector: map[string]string{"app": "in ()"}},
+		},
+	})
+	require.Error(t, err)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = server.Register(context.Background(), &registry.NetworkService{
+		Name: "invalid-destination",
+		Matches: []*registry.Match{
+			{
+				Routes: []*registry.Destination{
+					{DestinationSelector: map[string]string{"!app": "firewall"}},
+				},
+			},
+		},
+	})
+	require.Error(t, err)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -28,6 +28,7 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/networkservicemesh/sdk/pkg/registry"
+	memorychain "github.com/networkservicemesh/sdk/pkg/registry/chains/memory"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/setid"
 	registrychain "github.com/networkservicemesh/sdk/pkg/registry/core/chain"
@@ -219,7 +220,7 @@ func TestInterdomainFloatingNetworkServiceRegistry(t *testing.T) {
 
 	domain3 := sandbox.NewBuilder(t).
 		SetNodesCount(0).
-		SetRegistrySupplier(func(context.Context, time.Duration, *url.URL, ...grpc.DialOption) registry.Registry {
+		SetRegistrySupplier(func(context.Context, time.Duration, *url.URL, ...memorychain.Option) registry.Registry {
 			return registry.NewServer(
 				memory.NewNetworkServiceRegistryServer(),
 				registrychain.NewNetworkServiceEndpointRegistryServer(
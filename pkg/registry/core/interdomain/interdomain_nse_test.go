@@ -31,6 +31,7 @@ import (
 	registryapi "github.com/networkservicemesh/api/pkg/api/registry"
 
 	"github.com/networkservicemesh/sdk/pkg/registry"
+	memorychain "github.com/networkservicemesh/sdk/pkg/registry/chains/memory"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/setid"
 	registrychain "github.com/networkservicemesh/sdk/pkg/registry/core/chain"
@@ -227,7 +228,7 @@ func TestInterdomainFloatingNetworkServiceEndpointRegistry(t *testing.T) {
 
 	domain3 := sandbox.NewBuilder(t).
 		SetNodesCount(0).
-		SetRegistrySupplier(func(context.Context, time.Duration, *url.URL, ...grpc.DialOption) registry.Registry {
+		SetRegistrySupplier(func(context.Context, time.Duration, *url.URL, ...memorychain.Option) registry.Registry {
 			return registry.NewServer(
 				memory.NewNetworkServiceRegistryServer(),
 				registrychain.NewNetworkServiceEndpointRegistryServer(
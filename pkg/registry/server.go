@@ -1,4 +1,4 @@
-// Copyright (c) 2020 Doc.ai and/or its affiliates.
+// Copyright (c) 2020-2021 Doc.ai and/or its affiliates.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -21,6 +21,7 @@ import (
 	"github.com/networkservicemesh/api/pkg/api/registry"
 	"google.golang.org/grpc"
 
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/admin"
 	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
 )
 
@@ -36,8 +37,19 @@ type Registry interface {
 }
 
 type registryImpl struct {
-	nsChain  registry.NetworkServiceRegistryServer
-	nseChain registry.NetworkServiceEndpointRegistryServer
+	nsChain     registry.NetworkServiceRegistryServer
+	nseChain    registry.NetworkServiceEndpointRegistryServer
+	adminServer admin.AdminServer
+}
+
+// Option is an option pattern for NewServer
+type Option func(r *registryImpl)
+
+// WithAdminServer sets the admin gRPC service registered next to the registry servers
+func WithAdminServer(adminServer admin.AdminServer) Option {
+	return func(r *registryImpl) {
+		r.adminServer = adminServer
+	}
 }
 
 func (r *registryImpl) NetworkServiceRegistryServer() registry.NetworkServiceRegistryServer {
@@ -52,12 +64,19 @@ func (r *registryImpl) Register(server *grpc.Server) {
 	grpcutils.RegisterHealthServices(server, r.nsChain, r.nseChain)
 	registry.RegisterNetworkServiceRegistryServer(server, r.nsChain)
 	registry.RegisterNetworkServiceEndpointRegistryServer(server, r.nseChain)
+	if r.adminServer != nil {
+		admin.RegisterAdminServer(server, r.adminServer)
+	}
 }
 
 // NewServer creates new Registry with specific NetworkServiceRegistryServer and NetworkServiceEndpointRegistryServer functionality
-func NewServer(nsChain registry.NetworkServiceRegistryServer, nseChain registry.NetworkServiceEndpointRegistryServer) Registry {
-	return &registryImpl{
+func NewServer(nsChain registry.NetworkServiceRegistryServer, nseChain registry.NetworkServiceEndpointRegistryServer, options ...Option) Registry {
+	r := &registryImpl{
 		nseChain: nseChain,
 		nsChain:  nsChain,
 	}
+	for _, opt := range options {
+		opt(r)
+	}
+	return r
 }
@@ -0,0 +1,21 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package admin provides the registry introspection gRPC service. It lists the NSEs with their expiration times, the
+// pending expire timers, the active watchers and the query cache contents, and it allows to force the NSE unregister.
+//
+// The service is defined in admin.proto. All the calls are denied unless the policies are set with WithPolicies.
+package admin
@@ -1,4 +1,4 @@
-// Copyright (c) 2020-2021 Doc.ai and/or its affiliates.
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -14,10 +14,6 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package querycache
+package admin
 
-import "sync"
-
-//go:generate go-syncmap -output cache_entry_map.gen.go -type cacheEntryMap<string,*cacheEntry>
-
-type cacheEntryMap sync.Map
+//go:generate protoc -I . -I ${GOPATH}/src --go_out=plugins=grpc,paths=source_relative:. admin.proto
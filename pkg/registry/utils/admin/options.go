@@ -0,0 +1,67 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/authorize"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/expire"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/querycache"
+)
+
+// Option is an option pattern for NewServer
+type Option func(s *adminServer)
+
+// WithMemory sets the memory registry servers used to list the NSEs and the watchers. Servers are expected to be the
+// memory registry servers, not the chains containing them.
+func WithMemory(nsServer registry.NetworkServiceRegistryServer, nseServer registry.NetworkServiceEndpointRegistryServer) Option {
+	return func(s *adminServer) {
+		s.nsServer = nsServer
+		s.nseServer = nseServer
+	}
+}
+
+// WithTimers sets the NSE expire server used to list the pending expire timers
+func WithTimers(timers expire.TimersProvider) Option {
+	return func(s *adminServer) {
+		s.timers = timers
+	}
+}
+
+// WithQueryCaches sets the query cache clients used to list the query cache contents
+func WithQueryCaches(caches ...querycache.ContentsProvider) Option {
+	return func(s *adminServer) {
+		s.caches = append(s.caches, caches...)
+	}
+}
+
+// WithUnregisterServer sets the NSE registry chain used to force the NSE unregister. The chain should not contain
+// the authorization chain elements, since the admin calls are already authorized by the admin server.
+func WithUnregisterServer(server registry.NetworkServiceEndpointRegistryServer) Option {
+	return func(s *adminServer) {
+		s.unregisterServer = server
+	}
+}
+
+// WithPolicies sets the policies checking the admin calls, by default all the calls are denied. Policies get
+// authorize.Input with the ListOperation or UnregisterOperation operation.
+func WithPolicies(policies ...authorize.Policy) Option {
+	return func(s *adminServer) {
+		s.policies = policies
+	}
+}
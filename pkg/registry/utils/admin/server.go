@@ -0,0 +1,270 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/authorize"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/expire"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/querycache"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/matchutils"
+)
+
+const (
+	// ListOperation is the operation of the admin list calls passed to the policies
+	ListOperation = "admin_list"
+	// UnregisterOperation is the operation of the admin unregister calls passed to the policies
+	UnregisterOperation = "admin_unregister"
+)
+
+const (
+	networkServiceType         = "NetworkService"
+	networkServiceEndpointType = "NetworkServiceEndpoint"
+)
+
+type adminServer struct {
+	clock            clock.Clock
+	nsServer         registry.NetworkServiceRegistryServer
+	nseServer        registry.NetworkServiceEndpointRegistryServer
+	timers           expire.TimersProvider
+	caches           []querycache.ContentsProvider
+	unregisterServer registry.NetworkServiceEndpointRegistryServer
+	policies         []authorize.Policy
+}
+
+// NewServer creates the registry admin gRPC service. Only the lists with the sources set by the options are not
+// empty, forced unregister is available only with WithUnregisterServer.
+func NewServer(ctx context.Context, options ...Option) AdminServer {
+	s := &adminServer{
+		clock: clock.FromContext(ctx),
+	}
+	for _, opt := range options {
+		opt(s)
+	}
+	return s
+}
+
+func (s *adminServer) ListNetworkServiceEndpoints(ctx context.Context, _ *empty.Empty) (*EndpointList, error) {
+	if err := s.check(ctx, authorize.NewInput(ctx, ListOperation)); err != nil {
+		return nil, err
+	}
+
+	nses, err := s.findNSEs(ctx, new(registry.NetworkServiceEndpoint), matchutils.SubstringMode)
+	if err != nil {
+		return nil, err
+	}
+
+	// Memory registry stores the NSEs before the expire sets the expiration time, so the timers are checked first
+	timers := make(map[string]time.Time)
+	if s.timers != nil {
+		timers = s.timers.Timers()
+	}
+
+	now := s.clock.Now()
+	endpoints := make([]*Endpoint, 0, len(nses))
+	for _, nse := range nses {
+		endpoint := &Endpoint{
+			Name:                nse.GetName(),
+			Url:                 nse.GetUrl(),
+			NetworkServiceNames: nse.GetNetworkServiceNames(),
+		}
+		if expirationTime, ok := timers[nse.GetName()]; ok {
+			endpoint.ExpirationTime = timestamppb.New(expirationTime)
+		} else if nse.GetExpirationTime() != nil {
+			endpoint.ExpirationTime = nse.GetExpirationTime()
+		}
+		if endpoint.ExpirationTime != nil {
+			endpoint.Ttl = ttl(now, endpoint.ExpirationTime.AsTime())
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].Name < endpoints[j].Name
+	})
+
+	return &EndpointList{Endpoints: endpoints}, nil
+}
+
+func (s *adminServer) ListTimers(ctx context.Context, _ *empty.Empty) (*TimerList, error) {
+	if err := s.check(ctx, authorize.NewInput(ctx, ListOperation)); err != nil {
+		return nil, err
+	}
+
+	timers := make([]*Timer, 0)
+	if s.timers != nil {
+		now := s.clock.Now()
+		for name, expirationTime := range s.timers.Timers() {
+			timers = append(timers, &Timer{
+				Name:           name,
+				ExpirationTime: timestamppb.New(expirationTime),
+				Ttl:            ttl(now, expirationTime),
+			})
+		}
+	}
+	sort.Slice(timers, func(i, j int) bool {
+		return timers[i].Name < timers[j].Name
+	})
+
+	return &TimerList{Timers: timers}, nil
+}
+
+func (s *adminServer) ListWatchers(ctx context.Context, _ *empty.Empty) (*WatcherList, error) {
+	if err := s.check(ctx, authorize.NewInput(ctx, ListOperation)); err != nil {
+		return nil, err
+	}
+
+	watchers := make([]*Watcher, 0)
+	for watcherType, server := range map[string]interface{}{
+		networkServiceType:         s.nsServer,
+		networkServiceEndpointType: s.nseServer,
+	} {
+		provider, ok := server.(memory.WatchersStatsProvider)
+		if !ok {
+			continue
+		}
+		for id, stats := range provider.WatchersStats() {
+			watcher := &Watcher{
+				Id:     id,
+				Type:   watcherType,
+				Queued: uint32(stats.Queued),
+				Lag:    stats.Lag,
+			}
+			if stats.Query != nil {
+				query, err := protojson.Marshal(stats.Query)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to encode the query of the watcher: %s", id)
+				}
+				watcher.Query = string(query)
+			}
+			watchers = append(watchers, watcher)
+		}
+	}
+	sort.Slice(watchers, func(i, j int) bool {
+		if watchers[i].Type != watchers[j].Type {
+			return watchers[i].Type < watchers[j].Type
+		}
+		return watchers[i].Id < watchers[j].Id
+	})
+
+	return &WatcherList{Watchers: watchers}, nil
+}
+
+func (s *adminServer) ListQueryCache(ctx context.Context, _ *empty.Empty) (*CacheEntryList, error) {
+	if err := s.check(ctx, authorize.NewInput(ctx, ListOperation)); err != nil {
+		return nil, err
+	}
+
+	entries := make([]*CacheEntry, 0)
+	for _, cache := range s.caches {
+		for _, entry := range cache.Contents() {
+			entries = append(entries, &CacheEntry{
+				Key:            entry.Key,
+				Names:          entry.Names,
+				ExpirationTime: timestamppb.New(entry.ExpirationTime),
+			})
+		}
+	}
+
+	return &CacheEntryList{Entries: entries}, nil
+}
+
+func (s *adminServer) Unregister(ctx context.Context, request *UnregisterRequest) (*empty.Empty, error) {
+	if request.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "NSE name is not set")
+	}
+
+	nses, err := s.findNSEs(ctx, &registry.NetworkServiceEndpoint{Name: request.GetName()}, matchutils.ExactMode)
+	if err != nil {
+		return nil, err
+	}
+
+	input := authorize.NewInput(ctx, UnregisterOperation)
+	if len(nses) > 0 {
+		input.NetworkServiceNames = nses[0].GetNetworkServiceNames()
+		input.NetworkServiceEndpoint = nses[0]
+	}
+	if err = s.check(ctx, input); err != nil {
+		return nil, err
+	}
+
+	if s.unregisterServer == nil {
+		return nil, status.Error(codes.Unimplemented, "forced unregister is not configured")
+	}
+	if len(nses) == 0 {
+		return nil, status.Errorf(codes.NotFound, "NSE not found: %s", request.GetName())
+	}
+
+	return s.unregisterServer.Unregister(ctx, nses[0])
+}
+
+func (s *adminServer) findNSEs(
+	ctx context.Context,
+	query *registry.NetworkServiceEndpoint,
+	mode matchutils.Mode,
+) ([]*registry.NetworkServiceEndpoint, error) {
+	if s.nseServer == nil {
+		return nil, nil
+	}
+
+	stream, err := adapters.NetworkServiceEndpointServerToClient(s.nseServer).Find(matchutils.WithMode(ctx, mode), &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: query,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find NSEs")
+	}
+	return registry.ReadNetworkServiceEndpointList(stream), nil
+}
+
+func (s *adminServer) check(ctx context.Context, input *authorize.Input) error {
+	checked := false
+	for _, p := range s.policies {
+		if p == nil {
+			continue
+		}
+		if err := p.Check(ctx, input); err != nil {
+			return err
+		}
+		checked = true
+	}
+	if !checked {
+		return status.Error(codes.PermissionDenied, "admin calls are not allowed without the policies")
+	}
+	return nil
+}
+
+func ttl(now, expirationTime time.Time) *durationpb.Duration {
+	if d := expirationTime.Sub(now); d > 0 {
+		return durationpb.New(d)
+	}
+	return durationpb.New(0)
+}
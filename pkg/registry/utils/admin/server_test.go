@@ -0,0 +1,264 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	memorychain "github.com/networkservicemesh/sdk/pkg/registry/chains/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/authorize"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/querycache"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/admin"
+	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
+)
+
+const (
+	expiryDuration = time.Minute
+	testWait       = time.Second
+	testTick       = 10 * time.Millisecond
+)
+
+type allowPolicy struct{}
+
+func (p *allowPolicy) Check(_ context.Context, _ interface{}) error {
+	return nil
+}
+
+type denyPolicy struct {
+	operation string
+}
+
+func (p *denyPolicy) Check(_ context.Context, input interface{}) error {
+	if input.(*authorize.Input).Operation == p.operation {
+		return status.Error(codes.PermissionDenied, "denied")
+	}
+	return nil
+}
+
+func serve(ctx context.Context, t *testing.T, options ...memorychain.Option) *grpc.ClientConn {
+	r := memorychain.NewServer(ctx, expiryDuration, nil, options...)
+
+	server := grpc.NewServer()
+	r.Register(server)
+
+	serveURL := &url.URL{Scheme: "tcp", Host: "127.0.0.1:0"}
+	require.Len(t, grpcutils.ListenAndServe(ctx, serveURL, server), 0)
+
+	cc, err := grpc.DialContext(ctx, grpcutils.URLToTarget(serveURL), grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+
+	return cc
+}
+
+func TestAdmin_MemoryRegistry(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cc := serve(ctx, t, memorychain.WithAdmin(admin.WithPolicies(new(allowPolicy))))
+	defer func() { _ = cc.Close() }()
+
+	nseClient := registry.NewNetworkServiceEndpointRegistryClient(cc)
+	adminClient := admin.NewAdminClient(cc)
+
+	var names []string
+	for _, nsName := range []string{"ns-1", "ns-2"} {
+		reg, err := nseClient.Register(ctx, &registry.NetworkServiceEndpoint{
+			Name:                "nse",
+			Url:                 "tcp://1.1.1.1",
+			NetworkServiceNames: []string{nsName},
+		})
+		require.NoError(t, err)
+		names = append(names, reg.Name)
+	}
+
+	watchCtx, cancelWatch := context.WithCancel(ctx)
+	defer cancelWatch()
+
+	stream, err := nseClient.Find(watchCtx, &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{Name: names[0]},
+		Watch:                  true,
+	})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	// 1. List NSEs, timers and watchers
+	endpoints, err := adminClient.ListNetworkServiceEndpoints(ctx, new(empty.Empty))
+	require.NoError(t, err)
+	require.Len(t, endpoints.GetEndpoints(), 2)
+	for _, endpoint := range endpoints.GetEndpoints() {
+		require.Equal(t, "tcp://1.1.1.1", endpoint.GetUrl())
+		require.NotNil(t, endpoint.GetExpirationTime())
+		require.True(t, endpoint.GetTtl().AsDuration() > 0 && endpoint.GetTtl().AsDuration() <= expiryDuration)
+	}
+
+	timers, err := adminClient.ListTimers(ctx, new(empty.Empty))
+	require.NoError(t, err)
+	require.Len(t, timers.GetTimers(), 2)
+	require.ElementsMatch(t, names, []string{timers.GetTimers()[0].GetName(), timers.GetTimers()[1].GetName()})
+
+	watchers, err := adminClient.ListWatchers(ctx, new(empty.Empty))
+	require.NoError(t, err)
+	require.Len(t, watchers.GetWatchers(), 1)
+	require.Equal(t, "NetworkServiceEndpoint", watchers.GetWatchers()[0].GetType())
+	require.True(t, strings.Contains(watchers.GetWatchers()[0].GetQuery(), names[0]))
+
+	// 2. Force unregister
+	_, err = adminClient.Unregister(ctx, &admin.UnregisterRequest{Name: names[0]})
+	require.NoError(t, err)
+
+	nse, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, int64(-1), nse.GetExpirationTime().GetSeconds())
+
+	endpoints, err = adminClient.ListNetworkServiceEndpoints(ctx, new(empty.Empty))
+	require.NoError(t, err)
+	require.Len(t, endpoints.GetEndpoints(), 1)
+	require.Equal(t, names[1], endpoints.GetEndpoints()[0].GetName())
+
+	timers, err = adminClient.ListTimers(ctx, new(empty.Empty))
+	require.NoError(t, err)
+	require.Len(t, timers.GetTimers(), 1)
+
+	_, err = adminClient.Unregister(ctx, &admin.UnregisterRequest{Name: names[0]})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	// 3. Watcher is removed on cancel
+	cancelWatch()
+
+	require.Eventually(t, func() bool {
+		watchers, err = adminClient.ListWatchers(ctx, new(empty.Empty))
+		require.NoError(t, err)
+		return len(watchers.GetWatchers()) == 0
+	}, testWait, testTick)
+}
+
+func TestAdmin_Policies(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cc := serve(ctx, t, memorychain.WithAdmin(
+		admin.WithPolicies(&denyPolicy{operation: admin.UnregisterOperation}),
+	))
+	defer func() { _ = cc.Close() }()
+
+	reg, err := registry.NewNetworkServiceEndpointRegistryClient(cc).Register(ctx, &registry.NetworkServiceEndpoint{
+		Name:                "nse",
+		NetworkServiceNames: []string{"ns"},
+	})
+	require.NoError(t, err)
+
+	adminClient := admin.NewAdminClient(cc)
+
+	endpoints, err := adminClient.ListNetworkServiceEndpoints(ctx, new(empty.Empty))
+	require.NoError(t, err)
+	require.Len(t, endpoints.GetEndpoints(), 1)
+
+	_, err = adminClient.Unregister(ctx, &admin.UnregisterRequest{Name: reg.Name})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	endpoints, err = adminClient.ListNetworkServiceEndpoints(ctx, new(empty.Empty))
+	require.NoError(t, err)
+	require.Len(t, endpoints.GetEndpoints(), 1)
+}
+
+func TestAdmin_DeniedByDefault(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cc := serve(ctx, t, memorychain.WithAdmin())
+	defer func() { _ = cc.Close() }()
+
+	reg, err := registry.NewNetworkServiceEndpointRegistryClient(cc).Register(ctx, &registry.NetworkServiceEndpoint{
+		Name:                "nse",
+		NetworkServiceNames: []string{"ns"},
+	})
+	require.NoError(t, err)
+
+	adminClient := admin.NewAdminClient(cc)
+
+	_, err = adminClient.ListNetworkServiceEndpoints(ctx, new(empty.Empty))
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = adminClient.Unregister(ctx, &admin.UnregisterRequest{Name: reg.Name})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestAdmin_NotRegisteredByDefault(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cc := serve(ctx, t)
+	defer func() { _ = cc.Close() }()
+
+	_, err := admin.NewAdminClient(cc).ListNetworkServiceEndpoints(ctx, new(empty.Empty))
+	require.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestAdmin_QueryCache(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mem := memory.NewNetworkServiceEndpointRegistryServer()
+	_, err := mem.Register(ctx, &registry.NetworkServiceEndpoint{Name: "nse"})
+	require.NoError(t, err)
+
+	qc := querycache.NewClient(ctx)
+	c := next.NewNetworkServiceEndpointRegistryClient(qc, adapters.NetworkServiceEndpointServerToClient(mem))
+
+	stream, err := c.Find(ctx, &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{Name: "nse"},
+	})
+	require.NoError(t, err)
+	require.Len(t, registry.ReadNetworkServiceEndpointList(stream), 1)
+
+	server := admin.NewServer(ctx,
+		admin.WithQueryCaches(qc.(querycache.ContentsProvider)),
+		admin.WithPolicies(new(allowPolicy)),
+	)
+
+	list, err := server.ListQueryCache(ctx, new(empty.Empty))
+	require.NoError(t, err)
+	require.Len(t, list.GetEntries(), 1)
+	require.Equal(t, []string{"nse"}, list.GetEntries()[0].GetNames())
+}
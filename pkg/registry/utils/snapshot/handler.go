@@ -0,0 +1,156 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/authorize"
+)
+
+const maxSnapshotSize = 64 * 1024 * 1024
+
+const (
+	// ExportOperation is the operation of the snapshot export requests passed to the policies
+	ExportOperation = "export"
+	// ImportOperation is the operation of the snapshot import requests passed to the policies
+	ImportOperation = "import"
+)
+
+type handler struct {
+	nsClient  registry.NetworkServiceRegistryClient
+	nseClient registry.NetworkServiceEndpointRegistryClient
+	policies  []authorize.Policy
+}
+
+// HandlerOption is an option pattern for NewHandler
+type HandlerOption func(h *handler)
+
+// WithPolicies sets the policies checking the snapshot requests. Policies get authorize.Input with the ExportOperation
+// or ImportOperation operation and the SPIFFE ID of the client TLS certificate. Without the policies all the requests
+// are denied.
+func WithPolicies(policies ...authorize.Policy) HandlerOption {
+	return func(h *handler) {
+		h.policies = policies
+	}
+}
+
+// NewHandler returns a HTTP handler exporting the registry snapshot on GET and importing the snapshot from the
+// request body on POST. Export format is set with the "format" query parameter, JSON is used by default. Clients are
+// expected to be the local registry chains wrapped with the adapters, see Import.
+func NewHandler(nsClient registry.NetworkServiceRegistryClient, nseClient registry.NetworkServiceEndpointRegistryClient, options ...HandlerOption) http.Handler {
+	h := &handler{
+		nsClient:  nsClient,
+		nseClient: nseClient,
+	}
+	for _, opt := range options {
+		opt(h)
+	}
+	return h
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		if err := h.check(r, ExportOperation); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		h.export(w, r)
+	case http.MethodPost:
+		if err := h.check(r, ImportOperation); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		h.load(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method is not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *handler) export(w http.ResponseWriter, r *http.Request) {
+	format := JSON
+	if name := r.URL.Query().Get("format"); name != "" {
+		var err error
+		if format, err = ParseFormat(name); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	s, err := Export(r.Context(), h.nsClient, h.nseClient)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	data, err := Marshal(s, format)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/"+format.String())
+	_, _ = w.Write(data)
+}
+
+func (h *handler) load(w http.ResponseWriter, r *http.Request) {
+	data, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxSnapshotSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s, err := Unmarshal(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := Import(r.Context(), s, h.nsClient, h.nseClient); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) check(r *http.Request, operation string) error {
+	if len(h.policies) == 0 {
+		return errors.Errorf("snapshot %s is not allowed without the policies", operation)
+	}
+
+	input := &authorize.Input{
+		Operation: operation,
+	}
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		if spiffeID, err := x509svid.IDFromCert(r.TLS.PeerCertificates[0]); err == nil {
+			input.SpiffeID = spiffeID.String()
+		}
+	}
+
+	for _, p := range h.policies {
+		if err := p.Check(r.Context(), input); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/setid"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/matchutils"
+)
+
+// Export returns the snapshot of all the NSs and NSEs found with the clients. Use the adapters to export the local
+// registry chain.
+func Export(
+	ctx context.Context,
+	nsClient registry.NetworkServiceRegistryClient,
+	nseClient registry.NetworkServiceEndpointRegistryClient,
+) (*Snapshot, error) {
+	s := &Snapshot{
+		Version: Version,
+		Time:    clock.FromContext(ctx).Now().UTC(),
+	}
+
+	// Empty query matches all the entities only in the substring mode
+	ctx = matchutils.WithMode(ctx, matchutils.SubstringMode)
+
+	nsStream, err := nsClient.Find(ctx, &registry.NetworkServiceQuery{
+		NetworkService: new(registry.NetworkService),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find NSs")
+	}
+	s.NetworkServices = registry.ReadNetworkServiceList(nsStream)
+	sort.Slice(s.NetworkServices, func(i, j int) bool {
+		return s.NetworkServices[i].Name < s.NetworkServices[j].Name
+	})
+
+	nseStream, err := nseClient.Find(ctx, &registry.NetworkServiceEndpointQuery{
+		NetworkServiceEndpoint: new(registry.NetworkServiceEndpoint),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find NSEs")
+	}
+	s.NetworkServiceEndpoints = registry.ReadNetworkServiceEndpointList(nseStream)
+	sort.Slice(s.NetworkServiceEndpoints, func(i, j int) bool {
+		return s.NetworkServiceEndpoints[i].Name < s.NetworkServiceEndpoints[j].Name
+	})
+
+	return s, nil
+}
+
+// Import registers all the NSEs and NSs from the snapshot with the clients. Registration is idempotent, so Import can
+// be safely repeated. Already expired NSEs are skipped.
+// Clients are expected to be the local registry chains wrapped with the adapters: the imported NSE names are kept by
+// the setid.WithRegisteredName context mark, it is not passed over gRPC to the remote registries.
+func Import(
+	ctx context.Context,
+	s *Snapshot,
+	nsClient registry.NetworkServiceRegistryClient,
+	nseClient registry.NetworkServiceEndpointRegistryClient,
+) error {
+	// Imported NSEs already have unique names set on the first registration
+	ctx = setid.WithRegisteredName(ctx)
+
+	// NSEs are imported first, so the NS expire gets them on the NS registration
+	for _, nse := range liveNSEs(ctx, s) {
+		if _, err := nseClient.Register(ctx, nse); err != nil {
+			return errors.Wrapf(err, "failed to import NSE: %s", nse.Name)
+		}
+	}
+	for _, ns := range s.NetworkServices {
+		if _, err := nsClient.Register(ctx, ns.Clone()); err != nil {
+			return errors.Wrapf(err, "failed to import NS: %s", ns.Name)
+		}
+	}
+	return nil
+}
+
+// Restore stores all the NSEs and NSs from the snapshot right to the servers skipping any registry chain. Servers are
+// expected to be the memory registry servers, not the chains containing them. Already expired NSEs are skipped.
+// Since the registry expire is skipped, the restored NSEs are unregistered from nseServer on their expiration time
+// with ctx, unless they have been registered again with another expiration time, e.g. refreshed by their owners.
+func Restore(
+	ctx context.Context,
+	s *Snapshot,
+	nsServer registry.NetworkServiceRegistryServer,
+	nseServer registry.NetworkServiceEndpointRegistryServer,
+) error {
+	for _, nse := range liveNSEs(ctx, s) {
+		if _, err := nseServer.Register(ctx, nse.Clone()); err != nil {
+			return errors.Wrapf(err, "failed to restore NSE: %s", nse.Name)
+		}
+		if nse.ExpirationTime != nil {
+			expireRestored(ctx, nse, nseServer)
+		}
+	}
+	for _, ns := range s.NetworkServices {
+		if _, err := nsServer.Register(ctx, ns.Clone()); err != nil {
+			return errors.Wrapf(err, "failed to restore NS: %s", ns.Name)
+		}
+	}
+	return nil
+}
+
+func expireRestored(ctx context.Context, nse *registry.NetworkServiceEndpoint, nseServer registry.NetworkServiceEndpointRegistryServer) {
+	clockTime := clock.FromContext(ctx)
+	clockTime.AfterFunc(clockTime.Until(nse.ExpirationTime.AsTime()), func() {
+		if ctx.Err() != nil {
+			return
+		}
+
+		stream, err := adapters.NetworkServiceEndpointServerToClient(nseServer).Find(
+			matchutils.WithMode(ctx, matchutils.ExactMode),
+			&registry.NetworkServiceEndpointQuery{
+				NetworkServiceEndpoint: &registry.NetworkServiceEndpoint{Name: nse.Name},
+			})
+		if err != nil {
+			log.FromContext(ctx).Warnf("failed to find restored NSE %s: %s", nse.Name, err.Error())
+			return
+		}
+		for _, stored := range registry.ReadNetworkServiceEndpointList(stream) {
+			if stored.Name != nse.Name || !proto.Equal(stored.ExpirationTime, nse.ExpirationTime) {
+				continue
+			}
+			if _, err := nseServer.Unregister(ctx, stored); err != nil {
+				log.FromContext(ctx).Warnf("failed to unregister expired NSE %s: %s", nse.Name, err.Error())
+			}
+		}
+	})
+}
+
+func liveNSEs(ctx context.Context, s *Snapshot) (nses []*registry.NetworkServiceEndpoint) {
+	clk := clock.FromContext(ctx)
+	for _, nse := range s.NetworkServiceEndpoints {
+		if nse.ExpirationTime != nil && clk.Until(nse.ExpirationTime.AsTime()) <= 0 {
+			continue
+		}
+		nses = append(nses, nse.Clone())
+	}
+	return nses
+}
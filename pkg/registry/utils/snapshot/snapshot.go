@@ -0,0 +1,153 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+)
+
+// Version is the current version of the snapshot format
+const Version = "v1"
+
+// Format is a snapshot encoding format
+type Format int
+
+const (
+	// JSON is a JSON snapshot format
+	JSON Format = iota
+	// YAML is a YAML snapshot format
+	YAML
+)
+
+var formatNames = map[Format]string{
+	JSON: "json",
+	YAML: "yaml",
+}
+
+func (f Format) String() string {
+	if name, ok := formatNames[f]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseFormat parses the format name
+func ParseFormat(name string) (Format, error) {
+	for f, fName := range formatNames {
+		if strings.EqualFold(name, fName) {
+			return f, nil
+		}
+	}
+	return JSON, errors.Errorf("unknown snapshot format: %s", name)
+}
+
+// Snapshot is the registry contents. NSEs keep their expiration times.
+type Snapshot struct {
+	Version                 string
+	Time                    time.Time
+	NetworkServices         []*registry.NetworkService
+	NetworkServiceEndpoints []*registry.NetworkServiceEndpoint
+}
+
+type snapshotJSON struct {
+	Version                 string            `json:"version"`
+	Time                    time.Time         `json:"time"`
+	NetworkServices         []json.RawMessage `json:"networkServices,omitempty"`
+	NetworkServiceEndpoints []json.RawMessage `json:"networkServiceEndpoints,omitempty"`
+}
+
+// Marshal encodes the snapshot in the format
+func Marshal(s *Snapshot, format Format) ([]byte, error) {
+	sj := &snapshotJSON{
+		Version: s.Version,
+		Time:    s.Time,
+	}
+	for _, ns := range s.NetworkServices {
+		data, err := protojson.Marshal(ns)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal NS: %s", ns.Name)
+		}
+		sj.NetworkServices = append(sj.NetworkServices, data)
+	}
+	for _, nse := range s.NetworkServiceEndpoints {
+		data, err := protojson.Marshal(nse)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal NSE: %s", nse.Name)
+		}
+		sj.NetworkServiceEndpoints = append(sj.NetworkServiceEndpoints, data)
+	}
+
+	data, err := json.MarshalIndent(sj, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal snapshot")
+	}
+
+	switch format {
+	case JSON:
+		return data, nil
+	case YAML:
+		data, err = yaml.JSONToYAML(data)
+		return data, errors.Wrap(err, "failed to convert snapshot to YAML")
+	default:
+		return nil, errors.Errorf("unknown snapshot format: %d", format)
+	}
+}
+
+// Unmarshal decodes the snapshot in any of the formats
+func Unmarshal(data []byte) (*Snapshot, error) {
+	// JSON is a subset of YAML, so the conversion works for both the formats
+	data, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse snapshot")
+	}
+
+	sj := new(snapshotJSON)
+	if err = json.Unmarshal(data, sj); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal snapshot")
+	}
+	if sj.Version != Version {
+		return nil, errors.Errorf("unsupported snapshot version: %q, expected: %q", sj.Version, Version)
+	}
+
+	s := &Snapshot{
+		Version: sj.Version,
+		Time:    sj.Time,
+	}
+	for _, data := range sj.NetworkServices {
+		ns := new(registry.NetworkService)
+		if err := protojson.Unmarshal(data, ns); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal NS")
+		}
+		s.NetworkServices = append(s.NetworkServices, ns)
+	}
+	for _, data := range sj.NetworkServiceEndpoints {
+		nse := new(registry.NetworkServiceEndpoint)
+		if err := protojson.Unmarshal(data, nse); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal NSE")
+		}
+		s.NetworkServiceEndpoints = append(s.NetworkServiceEndpoints, nse)
+	}
+	return s, nil
+}
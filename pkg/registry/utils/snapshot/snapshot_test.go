@@ -0,0 +1,357 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/networkservicemesh/api/pkg/api/registry"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/networkservicemesh/sdk/pkg/registry/common/authorize"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/setid"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/adapters"
+	"github.com/networkservicemesh/sdk/pkg/registry/core/next"
+	"github.com/networkservicemesh/sdk/pkg/registry/utils/snapshot"
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+)
+
+type testRegistry struct {
+	nsServer  registry.NetworkServiceRegistryServer
+	nseServer registry.NetworkServiceEndpointRegistryServer
+}
+
+func newTestRegistry() *testRegistry {
+	return &testRegistry{
+		nsServer:  memory.NewNetworkServiceRegistryServer(),
+		nseServer: memory.NewNetworkServiceEndpointRegistryServer(),
+	}
+}
+
+func (r *testRegistry) nsClient() registry.NetworkServiceRegistryClient {
+	return adapters.NetworkServiceServerToClient(r.nsServer)
+}
+
+func (r *testRegistry) nseClient() registry.NetworkServiceEndpointRegistryClient {
+	return adapters.NetworkServiceEndpointServerToClient(next.NewNetworkServiceEndpointRegistryServer(
+		setid.NewNetworkServiceEndpointRegistryServer(),
+		r.nseServer,
+	))
+}
+
+func testSnapshot(now time.Time) *snapshot.Snapshot {
+	return &snapshot.Snapshot{
+		Version: snapshot.Version,
+		Time:    now.UTC(),
+		NetworkServices: []*registry.NetworkService{
+			{Name: "ns-1", Payload: "IP"},
+			{Name: "ns-2", Payload: "ETHERNET"},
+		},
+		NetworkServiceEndpoints: []*registry.NetworkServiceEndpoint{
+			{
+				Name:                "nse-1",
+				Url:                 "tcp://1.1.1.1",
+				NetworkServiceNames: []string{"ns-1"},
+				NetworkServiceLabels: map[string]*registry.NetworkServiceLabels{
+					"ns-1": {Labels: map[string]string{"app": "firewall"}},
+				},
+				ExpirationTime: &timestamp.Timestamp{Seconds: now.Add(time.Hour).Unix()},
+			},
+			{
+				Name:                "nse-2",
+				Url:                 "tcp://2.2.2.2",
+				NetworkServiceNames: []string{"ns-2"},
+			},
+		},
+	}
+}
+
+func requireEqualSnapshots(t *testing.T, expected, actual *snapshot.Snapshot) {
+	require.Equal(t, expected.Version, actual.Version)
+	require.True(t, expected.Time.Equal(actual.Time))
+
+	require.Len(t, actual.NetworkServices, len(expected.NetworkServices))
+	for i := range expected.NetworkServices {
+		require.True(t, proto.Equal(expected.NetworkServices[i], actual.NetworkServices[i]))
+	}
+	require.Len(t, actual.NetworkServiceEndpoints, len(expected.NetworkServiceEndpoints))
+	for i := range expected.NetworkServiceEndpoints {
+		require.True(t, proto.Equal(expected.NetworkServiceEndpoints[i], actual.NetworkServiceEndpoints[i]))
+	}
+}
+
+func TestSnapshot_MarshalUnmarshal(t *testing.T) {
+	s := testSnapshot(time.Now())
+
+	for _, format := range []snapshot.Format{snapshot.JSON, snapshot.YAML} {
+		format := format
+		t.Run(format.String(), func(t *testing.T) {
+			data, err := snapshot.Marshal(s, format)
+			require.NoError(t, err)
+
+			actual, err := snapshot.Unmarshal(data)
+			require.NoError(t, err)
+
+			requireEqualSnapshots(t, s, actual)
+		})
+	}
+}
+
+func TestSnapshot_UnsupportedVersion(t *testing.T) {
+	_, err := snapshot.Unmarshal([]byte("version: v0\n"))
+	require.Error(t, err)
+
+	_, err = snapshot.Unmarshal([]byte(`{"networkServices": [{"name": "ns"}]}`))
+	require.Error(t, err)
+}
+
+func TestSnapshot_ExportImport(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	clockMock.Set(time.Now())
+	ctx = clock.WithClock(ctx, clockMock)
+
+	expected := testSnapshot(clockMock.Now())
+
+	source := newTestRegistry()
+	require.NoError(t, snapshot.Restore(ctx, expected, source.nsServer, source.nseServer))
+
+	s, err := snapshot.Export(ctx, source.nsClient(), source.nseClient())
+	require.NoError(t, err)
+	requireEqualSnapshots(t, expected, s)
+
+	// Import is idempotent
+	target := newTestRegistry()
+	for i := 0; i < 2; i++ {
+		require.NoError(t, snapshot.Import(ctx, s, target.nsClient(), target.nseClient()))
+
+		actual, err := snapshot.Export(ctx, target.nsClient(), target.nseClient())
+		require.NoError(t, err)
+		requireEqualSnapshots(t, expected, actual)
+	}
+}
+
+func TestSnapshot_ShouldSkipExpiredNSEs(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	clockMock.Set(time.Now())
+	ctx = clock.WithClock(ctx, clockMock)
+
+	s := testSnapshot(clockMock.Now())
+
+	clockMock.Add(time.Hour)
+
+	r := newTestRegistry()
+	require.NoError(t, snapshot.Restore(ctx, s, r.nsServer, r.nseServer))
+
+	actual, err := snapshot.Export(ctx, r.nsClient(), r.nseClient())
+	require.NoError(t, err)
+
+	require.Len(t, actual.NetworkServices, 2)
+	require.Len(t, actual.NetworkServiceEndpoints, 1)
+	require.Equal(t, "nse-2", actual.NetworkServiceEndpoints[0].Name)
+}
+
+func TestSnapshot_Handler(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	expected := testSnapshot(time.Now())
+
+	source := newTestRegistry()
+	require.NoError(t, snapshot.Restore(ctx, expected, source.nsServer, source.nseServer))
+
+	target := newTestRegistry()
+
+	sourceServer := httptest.NewServer(snapshot.NewHandler(source.nsClient(), source.nseClient(),
+		snapshot.WithPolicies(allowOperationPolicy(snapshot.ExportOperation))))
+	defer sourceServer.Close()
+
+	targetServer := httptest.NewServer(snapshot.NewHandler(target.nsClient(), target.nseClient(),
+		snapshot.WithPolicies(allowOperationPolicy(snapshot.ImportOperation))))
+	defer targetServer.Close()
+
+	// 1. Export YAML from the source
+	resp, err := http.Get(sourceServer.URL + "?format=yaml")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "application/yaml", resp.Header.Get("Content-Type"))
+
+	buf := new(bytes.Buffer)
+	_, err = buf.ReadFrom(resp.Body)
+	require.NoError(t, err)
+
+	// 2. Import it to the target
+	importResp, err := http.Post(targetServer.URL, "application/yaml", bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	defer func() { _ = importResp.Body.Close() }()
+
+	require.Equal(t, http.StatusNoContent, importResp.StatusCode)
+
+	actual, err := snapshot.Export(ctx, target.nsClient(), target.nseClient())
+	require.NoError(t, err)
+
+	require.Len(t, actual.NetworkServices, len(expected.NetworkServices))
+	require.Len(t, actual.NetworkServiceEndpoints, len(expected.NetworkServiceEndpoints))
+
+	// 3. Broken snapshot is rejected
+	badResp, err := http.Post(targetServer.URL, "application/yaml", bytes.NewReader([]byte("version: v0\n")))
+	require.NoError(t, err)
+	defer func() { _ = badResp.Body.Close() }()
+
+	require.Equal(t, http.StatusBadRequest, badResp.StatusCode)
+}
+
+func TestSnapshot_HandlerImportForbidden(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	source := newTestRegistry()
+	require.NoError(t, snapshot.Restore(ctx, testSnapshot(time.Now()), source.nsServer, source.nseServer))
+
+	exported, err := snapshot.Export(ctx, source.nsClient(), source.nseClient())
+	require.NoError(t, err)
+
+	data, err := snapshot.Marshal(exported, snapshot.JSON)
+	require.NoError(t, err)
+
+	target := newTestRegistry()
+
+	for _, options := range [][]snapshot.HandlerOption{
+		nil,
+		{snapshot.WithPolicies(allowOperationPolicy(snapshot.ExportOperation))},
+	} {
+		targetServer := httptest.NewServer(snapshot.NewHandler(target.nsClient(), target.nseClient(), options...))
+
+		resp, err := http.Post(targetServer.URL, "application/json", bytes.NewReader(data))
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+
+		targetServer.Close()
+
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	}
+
+	actual, err := snapshot.Export(ctx, target.nsClient(), target.nseClient())
+	require.NoError(t, err)
+
+	require.Empty(t, actual.NetworkServices)
+	require.Empty(t, actual.NetworkServiceEndpoints)
+}
+
+func TestSnapshot_HandlerExportForbidden(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	source := newTestRegistry()
+	require.NoError(t, snapshot.Restore(ctx, testSnapshot(time.Now()), source.nsServer, source.nseServer))
+
+	for _, options := range [][]snapshot.HandlerOption{
+		nil,
+		{snapshot.WithPolicies(allowOperationPolicy(snapshot.ImportOperation))},
+	} {
+		sourceServer := httptest.NewServer(snapshot.NewHandler(source.nsClient(), source.nseClient(), options...))
+
+		resp, err := http.Get(sourceServer.URL)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+
+		sourceServer.Close()
+
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	}
+}
+
+func TestSnapshot_RestoredNSEsExpire(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	clockMock.Set(time.Now())
+	ctx = clock.WithClock(ctx, clockMock)
+
+	s := testSnapshot(clockMock.Now())
+	s.NetworkServiceEndpoints = append(s.NetworkServiceEndpoints, &registry.NetworkServiceEndpoint{
+		Name:                "nse-3",
+		Url:                 "tcp://3.3.3.3",
+		NetworkServiceNames: []string{"ns-1"},
+		ExpirationTime:      &timestamp.Timestamp{Seconds: clockMock.Now().Add(time.Hour).Unix()},
+	})
+
+	r := newTestRegistry()
+	require.NoError(t, snapshot.Restore(ctx, s, r.nsServer, r.nseServer))
+
+	// nse-3 is refreshed by its owner
+	refreshed := s.NetworkServiceEndpoints[2].Clone()
+	refreshed.ExpirationTime = &timestamp.Timestamp{Seconds: clockMock.Now().Add(2 * time.Hour).Unix()}
+	_, err := r.nseServer.Register(ctx, refreshed)
+	require.NoError(t, err)
+
+	clockMock.Add(time.Hour - time.Second)
+
+	actual, err := snapshot.Export(ctx, r.nsClient(), r.nseClient())
+	require.NoError(t, err)
+	require.Len(t, actual.NetworkServiceEndpoints, 3)
+
+	clockMock.Add(time.Second)
+
+	require.Eventually(t, func() bool {
+		actual, err = snapshot.Export(ctx, r.nsClient(), r.nseClient())
+		require.NoError(t, err)
+		return len(actual.NetworkServiceEndpoints) == 2
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, "nse-2", actual.NetworkServiceEndpoints[0].Name)
+	require.Equal(t, "nse-3", actual.NetworkServiceEndpoints[1].Name)
+}
+
+type allowOperationPolicy string
+
+func (p allowOperationPolicy) Check(_ context.Context, input interface{}) error {
+	if input.(*authorize.Input).Operation != string(p) {
+		return errors.New("operation is not allowed")
+	}
+	return nil
+}
@@ -25,21 +25,25 @@ import (
 )
 
 const (
-	nodeNameEnv      = "NODE_NAME"
-	podNameEnv       = "POD_NAME"
-	clusterNameEnv   = "CLUSTER_NAME"
-	nodeNameLabel    = "NodeNameKey"
-	podNameLabel     = "PodNameKey"
-	clusterNameLabel = "ClusterNameKey"
+	nodeNameEnv    = "NODE_NAME"
+	podNameEnv     = "POD_NAME"
+	clusterNameEnv = "CLUSTER_NAME"
+
+	// NodeNameLabel is a label key for the node name
+	NodeNameLabel = "NodeNameKey"
+	// PodNameLabel is a label key for the pod name
+	PodNameLabel = "PodNameKey"
+	// ClusterNameLabel is a label key for the cluster name
+	ClusterNameLabel = "ClusterNameKey"
 )
 
 // AddClientInfo adds client info (node/pod/cluster names) to provided map, taking this info from corresponding
 // environment variables
 func AddClientInfo(ctx context.Context, labels map[string]string) {
 	names := map[string]string{
-		nodeNameEnv:    nodeNameLabel,
-		podNameEnv:     podNameLabel,
-		clusterNameEnv: clusterNameLabel,
+		nodeNameEnv:    NodeNameLabel,
+		podNameEnv:     PodNameLabel,
+		clusterNameEnv: ClusterNameLabel,
 	}
 	for envName, labelName := range names {
 		value, exists := os.LookupEnv(envName)
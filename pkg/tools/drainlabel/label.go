@@ -0,0 +1,57 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package drainlabel provides the NSE label marking the NSE as draining, so it is not selected for the new connections
+package drainlabel
+
+import (
+	"github.com/networkservicemesh/api/pkg/api/registry"
+)
+
+const (
+	// Key is the key of the NSE label marking the NSE as draining
+	Key = "draining"
+	// Value is the value of the NSE label marking the NSE as draining
+	Value = "true"
+)
+
+// IsDraining returns true if the NSE is marked as draining for any of its Network Services
+func IsDraining(nse *registry.NetworkServiceEndpoint) bool {
+	for _, labels := range nse.GetNetworkServiceLabels() {
+		if labels.GetLabels()[Key] == Value {
+			return true
+		}
+	}
+	return false
+}
+
+// Mark marks the NSE as draining for all of its Network Services
+func Mark(nse *registry.NetworkServiceEndpoint) {
+	if nse.NetworkServiceLabels == nil {
+		nse.NetworkServiceLabels = make(map[string]*registry.NetworkServiceLabels)
+	}
+	for _, name := range nse.GetNetworkServiceNames() {
+		labels, ok := nse.NetworkServiceLabels[name]
+		if !ok {
+			labels = new(registry.NetworkServiceLabels)
+			nse.NetworkServiceLabels[name] = labels
+		}
+		if labels.Labels == nil {
+			labels.Labels = make(map[string]string)
+		}
+		labels.Labels[Key] = Value
+	}
+}
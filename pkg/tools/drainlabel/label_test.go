@@ -0,0 +1,45 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drainlabel_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/drainlabel"
+)
+
+func TestIsDraining(t *testing.T) {
+	nse := &registry.NetworkServiceEndpoint{
+		NetworkServiceNames: []string{"ns-1", "ns-2"},
+	}
+	require.False(t, drainlabel.IsDraining(nse))
+
+	nse.NetworkServiceLabels = map[string]*registry.NetworkServiceLabels{
+		"ns-1": {Labels: map[string]string{drainlabel.Key: "false"}},
+	}
+	require.False(t, drainlabel.IsDraining(nse))
+
+	drainlabel.Mark(nse)
+	require.True(t, drainlabel.IsDraining(nse))
+	for _, name := range nse.NetworkServiceNames {
+		require.Equal(t, drainlabel.Value, nse.NetworkServiceLabels[name].Labels[drainlabel.Key])
+	}
+}
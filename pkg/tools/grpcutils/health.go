@@ -23,8 +23,16 @@ import (
 	"google.golang.org/grpc/health/grpc_health_v1"
 )
 
-// RegisterHealthServices registers grpc health probe for each passed service
+// RegisterHealthServices registers grpc health probe for each passed service. If the health service is already
+// registered on s, it is left as is, so the serving statuses are controlled by the one who registered it.
 func RegisterHealthServices(s grpc.ServiceRegistrar, services ...interface{}) {
+	if info, ok := s.(interface {
+		GetServiceInfo() map[string]grpc.ServiceInfo
+	}); ok {
+		if _, ok := info.GetServiceInfo()[grpc_health_v1.Health_ServiceDesc.ServiceName]; ok {
+			return
+		}
+	}
 	healthServer := health.NewServer()
 	grpc_health_v1.RegisterHealthServer(s, healthServer)
 	for _, service := range services {
@@ -43,32 +43,7 @@ func ListenAndServe(ctx context.Context, address *url.URL, server *grpc.Server)
 	errCh := make(chan error, 1)
 
 	// Create listener
-	network, target := urlToNetworkTarget(address)
-
-	if network == unixScheme {
-		err := os.Remove(target)
-		if err != nil && !errors.Is(err, os.ErrNotExist) {
-			errCh <- errors.Wrap(err, "Cannot delete exist socket file")
-			close(errCh)
-			return errCh
-		}
-		basePath := path.Dir(target)
-		if _, err = os.Stat(basePath); os.IsNotExist(err) {
-			log.FromContext(ctx).Infof("target folder %v not exists, Trying to create", basePath)
-			if err = os.MkdirAll(basePath, os.ModePerm); err != nil {
-				errCh <- errors.Wrapf(err, "Could not serve %v", target)
-				close(errCh)
-				return errCh
-			}
-		}
-	}
-
-	ln, err := net.Listen(network, target)
-
-	if ln != nil {
-		// We need to pass a real listener address into context, since we could specify random port.
-		*address = *AddressToURL(ln.Addr())
-	}
+	ln, err := Listen(ctx, address)
 
 	// Serve
 	go func() {
@@ -97,6 +72,36 @@ func ListenAndServe(ctx context.Context, address *url.URL, server *grpc.Server)
 	return errCh
 }
 
+// Listen creates a listener on address. For the unix sockets it removes the existing socket file and creates the
+// missing folders. address is updated with the real listener address, since a random port could be specified.
+func Listen(ctx context.Context, address *url.URL) (net.Listener, error) {
+	network, target := urlToNetworkTarget(address)
+
+	if network == unixScheme {
+		err := os.Remove(target)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, errors.Wrap(err, "Cannot delete exist socket file")
+		}
+		basePath := path.Dir(target)
+		if _, err = os.Stat(basePath); os.IsNotExist(err) {
+			log.FromContext(ctx).Infof("target folder %v not exists, Trying to create", basePath)
+			if err = os.MkdirAll(basePath, os.ModePerm); err != nil {
+				return nil, errors.Wrapf(err, "Could not serve %v", target)
+			}
+		}
+	}
+
+	ln, err := net.Listen(network, target)
+	if err != nil {
+		return nil, err
+	}
+
+	// We need to pass a real listener address into context, since we could specify random port.
+	*address = *AddressToURL(ln.Addr())
+
+	return ln, nil
+}
+
 func urlToNetworkTarget(u *url.URL) (network, target string) {
 	network = tcpScheme
 	target = u.Host
@@ -36,10 +36,15 @@ import (
 const (
 	opentracingEnv     = "TRACER_ENABLED"
 	opentracingDefault = true
+	tracerTypeEnv      = "TRACER_TYPE"
+	tracerType         = "jaeger"
 )
 
-// IsOpentracingEnabled returns true if opentracing enabled
+// IsOpentracingEnabled returns true if opentracing enabled and no other tracer is selected with TRACER_TYPE
 func IsOpentracingEnabled() bool {
+	if t := os.Getenv(tracerTypeEnv); t != "" && t != tracerType {
+		return false
+	}
 	val, err := readEnvBool(opentracingEnv, opentracingDefault)
 	if err == nil {
 		return val
@@ -0,0 +1,223 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package labelselector provides Kubernetes-style set-based label selectors for the Network Service matches.
+// A selector is a map of requirements, each key-value pair is parsed into a single requirement:
+//
+//	"key": "value"           - Equals: label is equal to the value
+//	"key": "== value"        - Equals: same as above, allows values starting with an operator
+//	"key": "!= value"        - NotEquals: label is absent or not equal to the value
+//	"key": "in (v1, v2)"     - In: label is present and equal to one of the values
+//	"key": "notin (v1, v2)"  - NotIn: label is absent or not equal to any of the values
+//	"key": "*"               - Exists: label is present
+//	"!key": ""               - DoesNotExist: label is absent
+//
+// Each value can be a Go template executed with the request labels.
+//
+// Note that "*" has been matching only the "*" label value before the set-based selectors were introduced, now it
+// means Exists. Use "== *" to match the "*" label value.
+package labelselector
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// Operator is a requirement operator
+type Operator string
+
+const (
+	// Equals - label is equal to the value
+	Equals Operator = "=="
+	// NotEquals - label is absent or not equal to the value
+	NotEquals Operator = "!="
+	// In - label is present and equal to one of the values
+	In Operator = "in"
+	// NotIn - label is absent or not equal to any of the values
+	NotIn Operator = "notin"
+	// Exists - label is present
+	Exists Operator = "exists"
+	// DoesNotExist - label is absent
+	DoesNotExist Operator = "!"
+)
+
+const (
+	existsValue          = "*"
+	doesNotExistPrefix   = "!"
+	equalsPrefix         = "=="
+	notEqualsPrefix      = "!="
+	setValuesSeparator   = ","
+	templateActionPrefix = "{{"
+)
+
+var setRegexp = regexp.MustCompile(`^(in|notin)\s*\((.*)\)$`)
+
+// Selector is a parsed label selector
+type Selector []*Requirement
+
+// Requirement is a single parsed selector requirement
+type Requirement struct {
+	Key      string
+	Operator Operator
+	values   []*value
+}
+
+type value struct {
+	raw  string
+	tmpl *template.Template
+}
+
+// Parse parses selector into the Selector
+func Parse(selector map[string]string) (Selector, error) {
+	keys := make([]string, 0, len(selector))
+	for key := range selector {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var rv Selector
+	for _, key := range keys {
+		r, err := parseRequirement(key, selector[key])
+		if err != nil {
+			return nil, err
+		}
+		rv = append(rv, r)
+	}
+	return rv, nil
+}
+
+func parseRequirement(key, expr string) (*Requirement, error) {
+	if strings.HasPrefix(key, doesNotExistPrefix) {
+		key = strings.TrimSpace(strings.TrimPrefix(key, doesNotExistPrefix))
+		if key == "" {
+			return nil, errors.New("empty key in DoesNotExist requirement")
+		}
+		if strings.TrimSpace(expr) != "" {
+			return nil, errors.Errorf("DoesNotExist requirement for %s should have empty value: %s", key, expr)
+		}
+		return &Requirement{Key: key, Operator: DoesNotExist}, nil
+	}
+
+	r := &Requirement{Key: key}
+
+	trimmed := strings.TrimSpace(expr)
+	var rawValues []string
+	switch {
+	case trimmed == existsValue:
+		r.Operator = Exists
+	case strings.HasPrefix(trimmed, notEqualsPrefix):
+		r.Operator = NotEquals
+		rawValues = []string{strings.TrimSpace(strings.TrimPrefix(trimmed, notEqualsPrefix))}
+	case strings.HasPrefix(trimmed, equalsPrefix):
+		r.Operator = Equals
+		rawValues = []string{strings.TrimSpace(strings.TrimPrefix(trimmed, equalsPrefix))}
+	case setRegexp.MatchString(trimmed):
+		submatches := setRegexp.FindStringSubmatch(trimmed)
+		r.Operator = Operator(submatches[1])
+		for _, v := range strings.Split(submatches[2], setValuesSeparator) {
+			if v = strings.TrimSpace(v); v != "" {
+				rawValues = append(rawValues, v)
+			}
+		}
+		if len(rawValues) == 0 {
+			return nil, errors.Errorf("empty set in %s requirement for %s: %s", r.Operator, key, expr)
+		}
+	default:
+		// Plain values are not trimmed to keep the old exact matching behaviour
+		r.Operator = Equals
+		rawValues = []string{expr}
+	}
+
+	for _, raw := range rawValues {
+		v, err := parseValue(raw)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse value for %s: %s", key, expr)
+		}
+		r.values = append(r.values, v)
+	}
+	return r, nil
+}
+
+func parseValue(raw string) (*value, error) {
+	v := &value{raw: raw}
+	if strings.Contains(raw, templateActionPrefix) {
+		tmpl, err := template.New("value").Parse(raw)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		v.tmpl = tmpl
+	}
+	return v, nil
+}
+
+// Matches returns true if labels match all the requirements of s. vars are used to execute value templates.
+func (s Selector) Matches(labels, vars map[string]string) bool {
+	for _, r := range s {
+		if !r.Matches(labels, vars) {
+			return false
+		}
+	}
+	return true
+}
+
+// Matches returns true if labels match r. vars are used to execute value templates.
+func (r *Requirement) Matches(labels, vars map[string]string) bool {
+	label, ok := labels[r.Key]
+	switch r.Operator {
+	case Exists:
+		return ok
+	case DoesNotExist:
+		return !ok
+	case Equals:
+		// Absent label is treated as an empty one to keep the old exact matching behaviour
+		return r.values[0].matches(label, vars)
+	case NotEquals:
+		return !ok || !r.values[0].matches(label, vars)
+	case In:
+		return ok && r.anyMatches(label, vars)
+	case NotIn:
+		return !ok || !r.anyMatches(label, vars)
+	}
+	return false
+}
+
+func (r *Requirement) anyMatches(label string, vars map[string]string) bool {
+	for _, v := range r.values {
+		if v.matches(label, vars) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *value) matches(label string, vars map[string]string) bool {
+	if label == v.raw {
+		return true
+	}
+	if v.tmpl == nil {
+		return false
+	}
+	var buf bytes.Buffer
+	if err := v.tmpl.Execute(&buf, vars); err != nil {
+		return false
+	}
+	return label == buf.String()
+}
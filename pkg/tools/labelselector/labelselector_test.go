@@ -0,0 +1,173 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package labelselector_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/labelselector"
+)
+
+func TestSelector_Matches(t *testing.T) {
+	samples := []struct {
+		name     string
+		selector map[string]string
+		labels   map[string]string
+		vars     map[string]string
+		want     bool
+	}{
+		{
+			name:     "Equals",
+			selector: map[string]string{"app": "firewall"},
+			labels:   map[string]string{"app": "firewall"},
+			want:     true,
+		},
+		{
+			name:     "Equals mismatch",
+			selector: map[string]string{"app": "firewall"},
+			labels:   map[string]string{"app": "vpn"},
+		},
+		{
+			name:     "Equals with template",
+			selector: map[string]string{"app": "{{.app}}"},
+			labels:   map[string]string{"app": "firewall"},
+			vars:     map[string]string{"app": "firewall"},
+			want:     true,
+		},
+		{
+			name:     "Explicit Equals",
+			selector: map[string]string{"app": "== firewall"},
+			labels:   map[string]string{"app": "firewall"},
+			want:     true,
+		},
+		{
+			name:     "NotEquals",
+			selector: map[string]string{"app": "!= firewall"},
+			labels:   map[string]string{"app": "vpn"},
+			want:     true,
+		},
+		{
+			name:     "NotEquals absent",
+			selector: map[string]string{"app": "!=firewall"},
+			labels:   map[string]string{},
+			want:     true,
+		},
+		{
+			name:     "NotEquals mismatch",
+			selector: map[string]string{"app": "!= firewall"},
+			labels:   map[string]string{"app": "firewall"},
+		},
+		{
+			name:     "In",
+			selector: map[string]string{"app": "in (firewall, vpn)"},
+			labels:   map[string]string{"app": "vpn"},
+			want:     true,
+		},
+		{
+			name:     "In with template",
+			selector: map[string]string{"app": "in (firewall, {{.app}})"},
+			labels:   map[string]string{"app": "vpn"},
+			vars:     map[string]string{"app": "vpn"},
+			want:     true,
+		},
+		{
+			name:     "In absent",
+			selector: map[string]string{"app": "in (firewall, vpn)"},
+			labels:   map[string]string{},
+		},
+		{
+			name:     "NotIn",
+			selector: map[string]string{"app": "notin(firewall,vpn)"},
+			labels:   map[string]string{"app": "nat"},
+			want:     true,
+		},
+		{
+			name:     "NotIn mismatch",
+			selector: map[string]string{"app": "notin (firewall, vpn)"},
+			labels:   map[string]string{"app": "firewall"},
+		},
+		{
+			name:     "Exists",
+			selector: map[string]string{"app": "*"},
+			labels:   map[string]string{"app": ""},
+			want:     true,
+		},
+		{
+			name:     "Exists absent",
+			selector: map[string]string{"app": "*"},
+			labels:   map[string]string{},
+		},
+		{
+			name:     "Exists any value",
+			selector: map[string]string{"app": "*"},
+			labels:   map[string]string{"app": "firewall"},
+			want:     true,
+		},
+		{
+			name:     "Equals star",
+			selector: map[string]string{"app": "== *"},
+			labels:   map[string]string{"app": "*"},
+			want:     true,
+		},
+		{
+			name:     "Equals star mismatch",
+			selector: map[string]string{"app": "== *"},
+			labels:   map[string]string{"app": "firewall"},
+		},
+		{
+			name:     "DoesNotExist",
+			selector: map[string]string{"!app": ""},
+			labels:   map[string]string{"version": "1"},
+			want:     true,
+		},
+		{
+			name:     "DoesNotExist mismatch",
+			selector: map[string]string{"!app": ""},
+			labels:   map[string]string{"app": "firewall"},
+		},
+		{
+			name:     "Multiple requirements",
+			selector: map[string]string{"app": "in (firewall, vpn)", "version": "!= 1", "!canary": ""},
+			labels:   map[string]string{"app": "firewall", "version": "2"},
+			want:     true,
+		},
+	}
+
+	for i := range samples {
+		sample := samples[i]
+		t.Run(sample.name, func(t *testing.T) {
+			selector, err := labelselector.Parse(sample.selector)
+			require.NoError(t, err)
+			require.Equal(t, sample.want, selector.Matches(sample.labels, sample.vars))
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, selector := range []map[string]string{
+		{"app": "in ()"},
+		{"app": "notin ( , )"},
+		{"!app": "firewall"},
+		{"!": ""},
+		{"app": "{{.app"},
+	} {
+		_, err := labelselector.Parse(selector)
+		require.Error(t, err, "%v", selector)
+	}
+}
@@ -26,11 +26,11 @@ import (
 	"sync"
 
 	"github.com/google/uuid"
-	"github.com/opentracing/opentracing-go"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc/metadata"
 
 	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/log/spanlogger"
 )
 
 type loggerKeyType string
@@ -93,7 +93,7 @@ func fromContext(ctx context.Context) *traceCtxInfo {
 
 type logrusLogger struct {
 	entry     *logrus.Entry
-	span      opentracing.Span
+	span      spanlogger.Span
 	info      *traceCtxInfo
 	operation string
 }
@@ -194,7 +194,7 @@ func New(ctx context.Context) log.Logger {
 
 // FromSpan - creates a new logruslogger from context, operation and span
 // and returns context with it, logger, and a function to defer
-func FromSpan(ctx context.Context, span opentracing.Span, operation string) (context.Context, log.Logger, func()) {
+func FromSpan(ctx context.Context, span spanlogger.Span, operation string) (context.Context, log.Logger, func()) {
 	entry := logrus.WithFields(log.Fields(ctx))
 
 	var info *traceCtxInfo
@@ -0,0 +1,71 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanlogger
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/networkservicemesh/sdk/pkg/tools/log/spanlogger"
+
+type opentelemetrySpan struct {
+	span trace.Span
+}
+
+func newOpentelemetrySpan(ctx context.Context, operation string) (context.Context, Span) {
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, operation)
+	return ctx, &opentelemetrySpan{span: span}
+}
+
+func (s *opentelemetrySpan) Log(level, msg string, entries map[interface{}]interface{}) {
+	if level == "error" || level == "fatal" {
+		s.span.SetStatus(codes.Error, msg)
+	}
+	attributes := append(toAttributes(entries), attribute.String("message", msg))
+	s.span.AddEvent(level, trace.WithAttributes(attributes...))
+}
+
+func (s *opentelemetrySpan) LogObject(k interface{}, msg string, entries map[interface{}]interface{}) {
+	attributes := append(toAttributes(entries), attribute.String(fmt.Sprint(k), msg))
+	s.span.AddEvent("object", trace.WithAttributes(attributes...))
+}
+
+func (s *opentelemetrySpan) Finish() {
+	s.span.End()
+}
+
+func (s *opentelemetrySpan) String() string {
+	spanContext := s.span.SpanContext()
+	if !spanContext.IsValid() {
+		return ""
+	}
+	return fmt.Sprintf("%s:%s", spanContext.TraceID(), spanContext.SpanID())
+}
+
+func toAttributes(entries map[interface{}]interface{}) []attribute.KeyValue {
+	attributes := make([]attribute.KeyValue, 0, len(entries)+1)
+	for k, v := range entries {
+		attributes = append(attributes, attribute.String(fmt.Sprint(k), fmt.Sprint(v)))
+	}
+	return attributes
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanlogger
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opentracing/opentracing-go"
+	opentracinglog "github.com/opentracing/opentracing-go/log"
+)
+
+type opentracingSpan struct {
+	span opentracing.Span
+}
+
+func newOpentracingSpan(ctx context.Context, operation string) (context.Context, Span) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, operation)
+	return ctx, &opentracingSpan{span: span}
+}
+
+// OpentracingSpan - returns the opentracing span underlying the span returned by FromContext, nil if the span is not
+// created with opentracing
+func OpentracingSpan(span Span) opentracing.Span {
+	if s, ok := span.(*opentracingSpan); ok {
+		return s.span
+	}
+	return nil
+}
+
+func (s *opentracingSpan) Log(level, msg string, entries map[interface{}]interface{}) {
+	s.span.LogFields(opentracinglog.String("event", level), opentracinglog.String("message", msg))
+	for k, v := range entries {
+		s.span.LogKV(k, v)
+	}
+}
+
+func (s *opentracingSpan) LogObject(k interface{}, msg string, entries map[interface{}]interface{}) {
+	s.span.LogFields(opentracinglog.Object(k.(string), msg))
+	for k, v := range entries {
+		s.span.LogKV(k, v)
+	}
+}
+
+func (s *opentracingSpan) Finish() {
+	s.span.Finish()
+}
+
+func (s *opentracingSpan) String() string {
+	return fmt.Sprintf("%v", s.span)
+}
@@ -14,7 +14,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package spanlogger provides a set of utilities to assist in working with opentracing spans
+// Package spanlogger provides a set of utilities to assist in working with opentracing and OpenTelemetry spans
 package spanlogger
 
 import (
@@ -24,11 +24,9 @@ import (
 	"runtime/debug"
 	"strings"
 
-	"github.com/opentracing/opentracing-go"
-	opentracinglog "github.com/opentracing/opentracing-go/log"
-
 	"github.com/networkservicemesh/sdk/pkg/tools/jaeger"
 	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/opentelemetry"
 )
 
 const (
@@ -36,9 +34,21 @@ const (
 	dotCount        int = 3
 )
 
-// spanlogger - provides a way to log via opentracing spans
+// Span - a tracing span the spanLogger logs to
+type Span interface {
+	// Log - logs the message with the level and the entries
+	Log(level, msg string, entries map[interface{}]interface{})
+	// LogObject - logs the object serialized to msg with the entries
+	LogObject(k interface{}, msg string, entries map[interface{}]interface{})
+	// Finish - finishes the span
+	Finish()
+	// String - returns the span identifiers for the other loggers
+	String() string
+}
+
+// spanlogger - provides a way to log via opentracing or OpenTelemetry spans
 type spanLogger struct {
-	span    opentracing.Span
+	span    Span
 	entries map[interface{}]interface{}
 }
 
@@ -101,10 +111,7 @@ func (s *spanLogger) Object(k, v interface{}) {
 				msg = fmt.Sprint(v)
 			}
 
-			s.span.LogFields(opentracinglog.Object(k.(string), limitString(msg)))
-			for k, v := range s.entries {
-				s.span.LogKV(k, v)
-			}
+			s.span.LogObject(k, limitString(msg), s.entries)
 		}
 	}
 }
@@ -129,19 +136,20 @@ func (s *spanLogger) log(level string, v ...interface{}) {
 func (s *spanLogger) logf(level, format string, v ...interface{}) {
 	if s.span != nil {
 		if v != nil {
-			s.span.LogFields(opentracinglog.String("event", level), opentracinglog.String("message", fmt.Sprintf(format, v...)))
-			for k, v := range s.entries {
-				s.span.LogKV(k, v)
-			}
+			s.span.Log(level, fmt.Sprintf(format, v...), s.entries)
 		}
 	}
 }
 
-// FromContext - creates a new spanLogger from context and operation
-func FromContext(ctx context.Context, operation string) (context.Context, log.Logger, opentracing.Span, func()) {
-	var span opentracing.Span
-	if jaeger.IsOpentracingEnabled() {
-		span, ctx = opentracing.StartSpanFromContext(ctx, operation)
+// FromContext - creates a new spanLogger from context and operation. The returned span used to be opentracing.Span,
+// the opentracing span is returned by OpentracingSpan now.
+func FromContext(ctx context.Context, operation string) (context.Context, log.Logger, Span, func()) {
+	var span Span
+	switch {
+	case opentelemetry.IsEnabled():
+		ctx, span = newOpentelemetrySpan(ctx, operation)
+	case jaeger.IsOpentracingEnabled():
+		ctx, span = newOpentracingSpan(ctx, operation)
 	}
 	newLog := &spanLogger{
 		span:    span,
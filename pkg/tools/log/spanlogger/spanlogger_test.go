@@ -0,0 +1,80 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanlogger_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log/spanlogger"
+	"github.com/networkservicemesh/sdk/pkg/tools/opentelemetry"
+)
+
+func TestSpanLogger_Opentelemetry(t *testing.T) {
+	require.NoError(t, os.Setenv(opentelemetry.TracerTypeEnv, opentelemetry.TracerType))
+	defer func() { _ = os.Unsetenv(opentelemetry.TracerTypeEnv) }()
+
+	exporter := opentelemetry.NewMemoryExporter()
+	closer := opentelemetry.Init(context.Background(), "test", exporter)
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	_, logger, span, finish := spanlogger.FromContext(context.Background(), "operation")
+	require.NotEmpty(t, span.String())
+	require.Nil(t, spanlogger.OpentracingSpan(span))
+
+	logger.WithField("key", "value").Infof("hello %s", "world")
+	logger.Object("request", map[string]string{"id": "1"})
+	logger.Errorf("failed: %s", "reason")
+	finish()
+
+	require.NoError(t, closer.Close())
+
+	spans := exporter.Spans()
+	require.Len(t, spans, 1)
+	require.Equal(t, "operation", spans[0].Name)
+	require.Equal(t, "Error", spans[0].StatusCode)
+
+	events := spans[0].Events
+	require.Len(t, events, 3)
+
+	require.Equal(t, "info", events[0].Name)
+	require.Equal(t, "hello world", events[0].Attributes["message"])
+	require.Equal(t, "value", events[0].Attributes["key"])
+
+	require.Equal(t, "object", events[1].Name)
+	require.Equal(t, `{"id":"1"}`, events[1].Attributes["request"])
+
+	require.Equal(t, "error", events[2].Name)
+	require.Contains(t, events[2].Attributes, "stacktrace")
+}
+
+func TestSpanLogger_Opentracing(t *testing.T) {
+	require.NoError(t, os.Setenv("TRACER_ENABLED", "true"))
+	defer func() { _ = os.Unsetenv("TRACER_ENABLED") }()
+
+	ctx, _, span, finish := spanlogger.FromContext(context.Background(), "operation")
+	defer finish()
+
+	require.NotNil(t, spanlogger.OpentracingSpan(span))
+	require.Equal(t, opentracing.SpanFromContext(ctx), spanlogger.OpentracingSpan(span))
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matchutils
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+)
+
+type modeKeyType struct{}
+
+const modeMDKey = "nsm-registry-match-mode"
+
+// WithMode returns a new context with the match mode for the registry Find queries. The mode is also added to the
+// outgoing gRPC metadata, so it is passed to the remote registries.
+func WithMode(parent context.Context, mode Mode) context.Context {
+	if parent == nil {
+		panic("cannot create context from nil parent")
+	}
+	md, _ := metadata.FromOutgoingContext(parent)
+	md = md.Copy()
+	md.Set(modeMDKey, mode.String())
+	return context.WithValue(metadata.NewOutgoingContext(parent, md), modeKeyType{}, mode)
+}
+
+// ModeFromContext returns the match mode set with WithMode or received in the incoming gRPC metadata. If no mode is
+// set, SubstringMode is returned.
+func ModeFromContext(ctx context.Context) Mode {
+	if mode, ok := ctx.Value(modeKeyType{}).(Mode); ok {
+		return mode
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(modeMDKey); len(values) > 0 {
+			mode, err := ParseMode(values[len(values)-1])
+			if err != nil {
+				// Unknown mode should fail the query rather than silently fall back to SubstringMode
+				return unknownMode
+			}
+			return mode
+		}
+	}
+	return SubstringMode
+}
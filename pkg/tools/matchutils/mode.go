@@ -0,0 +1,120 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matchutils
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Mode is a matching mode for the names, URLs and label values in the registry Find queries
+type Mode int
+
+const (
+	// SubstringMode matches if the value contains the pattern. It is the default mode.
+	SubstringMode Mode = iota
+	// ExactMode matches if the value is equal to the pattern
+	ExactMode
+	// PrefixMode matches if the value starts with the pattern
+	PrefixMode
+	// GlobMode matches if the whole value matches the glob pattern. '*' matches any sequence of characters, '?'
+	// matches any single character.
+	GlobMode
+	// RegexMode matches if the value contains a match of the RE2 regular expression pattern. Use '^' and '$' to
+	// anchor the pattern.
+	RegexMode
+
+	unknownMode Mode = -1
+)
+
+var modeNames = map[Mode]string{
+	SubstringMode: "substring",
+	ExactMode:     "exact",
+	PrefixMode:    "prefix",
+	GlobMode:      "glob",
+	RegexMode:     "regex",
+}
+
+func (m Mode) String() string {
+	if name, ok := modeNames[m]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseMode returns Mode by its name
+func ParseMode(name string) (Mode, error) {
+	for mode, modeName := range modeNames {
+		if modeName == name {
+			return mode, nil
+		}
+	}
+	return 0, errors.Errorf("unknown match mode: %s", name)
+}
+
+// stringMatcher returns true if the value matches the pattern it has been created for
+type stringMatcher func(value string) bool
+
+func newStringMatcher(mode Mode, pattern string) (stringMatcher, error) {
+	switch mode {
+	case SubstringMode:
+		return func(value string) bool {
+			return strings.Contains(value, pattern)
+		}, nil
+	case ExactMode:
+		return func(value string) bool {
+			return value == pattern
+		}, nil
+	case PrefixMode:
+		return func(value string) bool {
+			return strings.HasPrefix(value, pattern)
+		}, nil
+	case GlobMode:
+		return newRegexMatcher(globToRegex(pattern))
+	case RegexMode:
+		return newRegexMatcher(pattern)
+	default:
+		return nil, errors.Errorf("unknown match mode: %d", mode)
+	}
+}
+
+func newRegexMatcher(pattern string) (stringMatcher, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid pattern: %s", pattern)
+	}
+	return re.MatchString, nil
+}
+
+func globToRegex(pattern string) string {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+	return sb.String()
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2020 Doc.ai and/or its affiliates.
+// Copyright (c) 2020-2021 Doc.ai and/or its affiliates.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -18,44 +18,100 @@
 package matchutils
 
 import (
-	"strings"
-
 	"github.com/google/go-cmp/cmp"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/networkservicemesh/api/pkg/api/registry"
 )
 
-// MatchNetworkServices returns true if two network services are matched
+// MatchNetworkServices returns true if two network services are matched in SubstringMode
 func MatchNetworkServices(left, right *registry.NetworkService) bool {
-	return (left.Name == "" || strings.Contains(right.Name, left.Name)) &&
-		(left.Payload == "" || left.Payload == right.Payload) &&
-		(left.Matches == nil || cmp.Equal(left.Matches, right.Matches, cmp.Comparer(proto.Equal)))
+	match, _ := NewNetworkServiceMatcher(SubstringMode, left)
+	return match(right)
 }
 
-// MatchNetworkServiceEndpoints  returns true if two network service endpoints are matched
+// MatchNetworkServiceEndpoints  returns true if two network service endpoints are matched in SubstringMode
 func MatchNetworkServiceEndpoints(left, right *registry.NetworkServiceEndpoint) bool {
-	return (left.Name == "" || strings.Contains(right.Name, left.Name)) &&
-		(left.NetworkServiceLabels == nil || labelsContains(right.NetworkServiceLabels, left.NetworkServiceLabels)) &&
-		(left.ExpirationTime == nil || left.ExpirationTime.Seconds == right.ExpirationTime.Seconds) &&
-		(left.NetworkServiceNames == nil || contains(right.NetworkServiceNames, left.NetworkServiceNames)) &&
-		(left.Url == "" || strings.Contains(right.Url, left.Url))
+	match, _ := NewNetworkServiceEndpointMatcher(SubstringMode, left)
+	return match(right)
+}
+
+// NewNetworkServiceMatcher returns a function matching network services with the query. Name is matched with the
+// mode, all other fields are matched exactly.
+func NewNetworkServiceMatcher(mode Mode, query *registry.NetworkService) (func(ns *registry.NetworkService) bool, error) {
+	matchName, err := newStringMatcher(mode, query.Name)
+	if err != nil {
+		return nil, err
+	}
+	return func(ns *registry.NetworkService) bool {
+		return (query.Name == "" || matchName(ns.Name)) &&
+			(query.Payload == "" || query.Payload == ns.Payload) &&
+			(query.Matches == nil || cmp.Equal(query.Matches, ns.Matches, cmp.Comparer(proto.Equal)))
+	}, nil
+}
+
+// NewNetworkServiceEndpointMatcher returns a function matching network service endpoints with the query. Name, URL
+// and label values are matched with the mode, all other fields are matched exactly.
+func NewNetworkServiceEndpointMatcher(mode Mode, query *registry.NetworkServiceEndpoint) (func(nse *registry.NetworkServiceEndpoint) bool, error) {
+	matchName, err := newStringMatcher(mode, query.Name)
+	if err != nil {
+		return nil, err
+	}
+	matchURL, err := newStringMatcher(mode, query.Url)
+	if err != nil {
+		return nil, err
+	}
+	matchLabels, err := newLabelsMatcher(mode, query.NetworkServiceLabels)
+	if err != nil {
+		return nil, err
+	}
+	return func(nse *registry.NetworkServiceEndpoint) bool {
+		return (query.Name == "" || matchName(nse.Name)) &&
+			(query.NetworkServiceLabels == nil || matchLabels(nse.NetworkServiceLabels)) &&
+			(query.ExpirationTime == nil || query.ExpirationTime.Seconds == nse.ExpirationTime.GetSeconds()) &&
+			(query.NetworkServiceNames == nil || contains(nse.NetworkServiceNames, query.NetworkServiceNames)) &&
+			(query.Url == "" || matchURL(nse.Url))
+	}, nil
 }
 
-func labelsContains(where, what map[string]*registry.NetworkServiceLabels) bool {
-	for lService, lLabels := range what {
-		rService, ok := where[lService]
-		if !ok {
-			return false
+// newLabelsMatcher returns a function checking that all the labels from the query are present in the network
+// service labels. Values of the labels are matched with the mode.
+func newLabelsMatcher(mode Mode, what map[string]*registry.NetworkServiceLabels) (func(where map[string]*registry.NetworkServiceLabels) bool, error) {
+	matchers := make(map[string]map[string]stringMatcher, len(what))
+	for service, labels := range what {
+		matchers[service] = make(map[string]stringMatcher, len(labels.GetLabels()))
+		for key, value := range labels.GetLabels() {
+			match, err := newLabelValueMatcher(mode, value)
+			if err != nil {
+				return nil, err
+			}
+			matchers[service][key] = match
 		}
-		for lKey, lVal := range lLabels.Labels {
-			rVal, ok := rService.Labels[lKey]
-			if !ok || lVal != rVal {
+	}
+	return func(where map[string]*registry.NetworkServiceLabels) bool {
+		for service, labelMatchers := range matchers {
+			serviceLabels, ok := where[service]
+			if !ok {
 				return false
 			}
+			for key, match := range labelMatchers {
+				value, ok := serviceLabels.GetLabels()[key]
+				if !ok || !match(value) {
+					return false
+				}
+			}
 		}
+		return true
+	}, nil
+}
+
+// newLabelValueMatcher returns exact matcher for SubstringMode, because label values have always been matched
+// exactly
+func newLabelValueMatcher(mode Mode, pattern string) (stringMatcher, error) {
+	if mode == SubstringMode {
+		mode = ExactMode
 	}
-	return true
+	return newStringMatcher(mode, pattern)
 }
 
 func contains(where, what []string) bool {
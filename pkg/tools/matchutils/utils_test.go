@@ -0,0 +1,98 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matchutils_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/matchutils"
+)
+
+func labeledNSE(name, value string) *registry.NetworkServiceEndpoint {
+	return &registry.NetworkServiceEndpoint{
+		Name: name,
+		NetworkServiceLabels: map[string]*registry.NetworkServiceLabels{
+			"ns": {
+				Labels: map[string]string{"app": value},
+			},
+		},
+	}
+}
+
+func TestNetworkServiceEndpointMatcher_LabelValues(t *testing.T) {
+	nses := []*registry.NetworkServiceEndpoint{
+		labeledNSE("nse-1", "vpn"),
+		labeledNSE("nse-2", "vpn-gateway"),
+		labeledNSE("nse-3", "firewall"),
+	}
+
+	for _, sample := range []struct {
+		mode     matchutils.Mode
+		value    string
+		expected []string
+	}{
+		{matchutils.SubstringMode, "vpn", []string{"nse-1"}},
+		{matchutils.ExactMode, "vpn", []string{"nse-1"}},
+		{matchutils.PrefixMode, "vpn", []string{"nse-1", "nse-2"}},
+		{matchutils.GlobMode, "*w*", []string{"nse-2", "nse-3"}},
+		{matchutils.RegexMode, "^(vpn|firewall)$", []string{"nse-1", "nse-3"}},
+	} {
+		match, err := matchutils.NewNetworkServiceEndpointMatcher(sample.mode, labeledNSE("", sample.value))
+		require.NoError(t, err)
+
+		var names []string
+		for _, nse := range nses {
+			if match(nse) {
+				names = append(names, nse.Name)
+			}
+		}
+		require.Equal(t, sample.expected, names, sample.mode.String())
+	}
+}
+
+func TestNetworkServiceMatcher_InvalidPattern(t *testing.T) {
+	_, err := matchutils.NewNetworkServiceMatcher(matchutils.RegexMode, &registry.NetworkService{Name: "[a-"})
+	require.Error(t, err)
+
+	_, err = matchutils.NewNetworkServiceMatcher(matchutils.GlobMode, &registry.NetworkService{Name: "[a-"})
+	require.NoError(t, err)
+}
+
+func TestModeFromContext(t *testing.T) {
+	require.Equal(t, matchutils.SubstringMode, matchutils.ModeFromContext(context.Background()))
+
+	ctx := matchutils.WithMode(context.Background(), matchutils.PrefixMode)
+	ctx = matchutils.WithMode(ctx, matchutils.RegexMode)
+	require.Equal(t, matchutils.RegexMode, matchutils.ModeFromContext(ctx))
+
+	// Outgoing metadata should be received as incoming on the remote side
+	md, ok := metadata.FromOutgoingContext(ctx)
+	require.True(t, ok)
+	remoteCtx := metadata.NewIncomingContext(context.Background(), md)
+	require.Equal(t, matchutils.RegexMode, matchutils.ModeFromContext(remoteCtx))
+
+	md.Set("nsm-registry-match-mode", "unknown")
+	remoteCtx = metadata.NewIncomingContext(context.Background(), md)
+	_, err := matchutils.NewNetworkServiceMatcher(matchutils.ModeFromContext(remoteCtx), &registry.NetworkService{Name: "ns"})
+	require.Error(t, err)
+}
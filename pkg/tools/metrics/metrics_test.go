@@ -0,0 +1,116 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/metrics"
+)
+
+func TestRegistry_WriteText(t *testing.T) {
+	r := metrics.NewRegistry()
+
+	calls := r.Counter("calls_total", "Number of the calls", "method", "code")
+	calls.With("Request", "OK").Inc()
+	calls.With("Request", "OK").Add(2)
+	calls.With("Close", "Unavailable").Inc()
+
+	// Same metric is returned on the second call
+	r.Counter("calls_total", "Number of the calls", "method", "code").With("Close", "Unavailable").Inc()
+
+	active := r.Gauge("active", "Number of the \"active\"\nconnections", "ns")
+	active.With(`a"b\c`).Inc()
+	active.With(`a"b\c`).Inc()
+	active.With(`a"b\c`).Dec()
+	active.With("deleted").Inc()
+	active.Delete("deleted")
+
+	size := 0
+	r.Gauge("size", "Registry size").With().SetFunc(func() float64 {
+		return float64(size)
+	})
+	size = 5
+
+	duration := r.Histogram("duration_seconds", "Call duration", []float64{1, 0.1}, "method")
+	duration.With("Request").Observe(0.05)
+	duration.With("Request").Observe(0.5)
+	duration.With("Request").Observe(5)
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteText(&buf))
+
+	require.Equal(t, `# HELP active Number of the "active"\nconnections
+# TYPE active gauge
+active{ns="a\"b\\c"} 1
+# HELP calls_total Number of the calls
+# TYPE calls_total counter
+calls_total{method="Close",code="Unavailable"} 2
+calls_total{method="Request",code="OK"} 3
+# HELP duration_seconds Call duration
+# TYPE duration_seconds histogram
+duration_seconds_bucket{method="Request",le="0.1"} 1
+duration_seconds_bucket{method="Request",le="1"} 2
+duration_seconds_bucket{method="Request",le="+Inf"} 3
+duration_seconds_sum{method="Request"} 5.55
+duration_seconds_count{method="Request"} 3
+# HELP size Registry size
+# TYPE size gauge
+size 5
+`, buf.String())
+}
+
+func TestRegistry_Conflict(t *testing.T) {
+	r := metrics.NewRegistry()
+
+	r.Counter("calls_total", "Number of the calls", "method")
+
+	require.Panics(t, func() {
+		r.Gauge("calls_total", "Number of the calls", "method")
+	})
+	require.Panics(t, func() {
+		r.Counter("calls_total", "Number of the calls", "method", "code")
+	})
+	require.Panics(t, func() {
+		r.Counter("calls_total", "Number of the calls", "method").With("Request", "OK")
+	})
+}
+
+func TestHandler(t *testing.T) {
+	r := metrics.NewRegistry()
+	r.Counter("calls_total", "Number of the calls").With().Inc()
+
+	server := httptest.NewServer(metrics.Handler(r))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, metrics.ContentType, resp.Header.Get("Content-Type"))
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "# HELP calls_total Number of the calls\n# TYPE calls_total counter\ncalls_total 1\n", string(body))
+}
@@ -0,0 +1,284 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type kind int
+
+const (
+	counterKind kind = iota
+	gaugeKind
+	histogramKind
+)
+
+func (k kind) String() string {
+	switch k {
+	case counterKind:
+		return "counter"
+	case gaugeKind:
+		return "gauge"
+	default:
+		return "histogram"
+	}
+}
+
+// DefaultBuckets are the default latency histogram buckets in seconds
+var DefaultBuckets = []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30}
+
+// Registry is a set of the metrics. Metrics are created on the first use, so the same metric can be shared by several
+// chain elements distinguished by the label values.
+type Registry struct {
+	families map[string]*family
+	lock     sync.Mutex
+}
+
+var defaultRegistry = NewRegistry()
+
+// NewRegistry creates a new empty Registry
+func NewRegistry() *Registry {
+	return &Registry{
+		families: make(map[string]*family),
+	}
+}
+
+// Default returns the default Registry used by the metrics chain elements
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Counter returns the counter with the name creating it if needed. It panics if there is another metric with the
+// same name but different type or labels.
+func (r *Registry) Counter(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{f: r.family(name, help, counterKind, nil, labelNames)}
+}
+
+// Gauge returns the gauge with the name creating it if needed. It panics if there is another metric with the same
+// name but different type or labels.
+func (r *Registry) Gauge(name, help string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{f: r.family(name, help, gaugeKind, nil, labelNames)}
+}
+
+// Histogram returns the histogram with the name creating it if needed, DefaultBuckets are used if buckets are not
+// set. It panics if there is another metric with the same name but different type or labels.
+func (r *Registry) Histogram(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &HistogramVec{f: r.family(name, help, histogramKind, buckets, labelNames)}
+}
+
+func (r *Registry) family(name, help string, k kind, buckets []float64, labelNames []string) *family {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if f, ok := r.families[name]; ok {
+		if f.kind != k || strings.Join(f.labelNames, ",") != strings.Join(labelNames, ",") {
+			panic(fmt.Sprintf("metric %s is already registered as %s%v", name, f.kind, f.labelNames))
+		}
+		return f
+	}
+
+	f := &family{
+		name:       name,
+		help:       help,
+		kind:       k,
+		buckets:    buckets,
+		labelNames: append([]string(nil), labelNames...),
+		series:     make(map[string]*series),
+	}
+	r.families[name] = f
+	return f
+}
+
+type family struct {
+	name       string
+	help       string
+	kind       kind
+	buckets    []float64
+	labelNames []string
+	series     map[string]*series
+	lock       sync.Mutex
+}
+
+type series struct {
+	labelValues []string
+	value       float64
+	fn          func() float64
+	counts      []uint64
+	sum         float64
+	count       uint64
+}
+
+func (f *family) with(labelValues []string) *series {
+	if len(labelValues) != len(f.labelNames) {
+		panic(fmt.Sprintf("metric %s expects %d label values, got %d", f.name, len(f.labelNames), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{
+			labelValues: append([]string(nil), labelValues...),
+		}
+		if f.kind == histogramKind {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+func (f *family) delete(labelValues []string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	delete(f.series, strings.Join(labelValues, "\xff"))
+}
+
+func (f *family) update(s *series, update func(s *series)) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	update(s)
+}
+
+// CounterVec is a counter partitioned by the label values
+type CounterVec struct {
+	f *family
+}
+
+// With returns the counter with the label values
+func (v *CounterVec) With(labelValues ...string) *Counter {
+	return &Counter{f: v.f, s: v.f.with(labelValues)}
+}
+
+// Counter is a monotonically increasing value
+type Counter struct {
+	f *family
+	s *series
+}
+
+// Inc increments the counter by 1
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Add adds the non-negative value to the counter
+func (c *Counter) Add(value float64) {
+	if value < 0 {
+		return
+	}
+	c.f.update(c.s, func(s *series) {
+		s.value += value
+	})
+}
+
+// GaugeVec is a gauge partitioned by the label values
+type GaugeVec struct {
+	f *family
+}
+
+// With returns the gauge with the label values
+func (v *GaugeVec) With(labelValues ...string) *Gauge {
+	return &Gauge{f: v.f, s: v.f.with(labelValues)}
+}
+
+// Delete deletes the gauge with the label values
+func (v *GaugeVec) Delete(labelValues ...string) {
+	v.f.delete(labelValues)
+}
+
+// Gauge is a value that can go up and down
+type Gauge struct {
+	f *family
+	s *series
+}
+
+// Set sets the gauge to the value
+func (g *Gauge) Set(value float64) {
+	g.f.update(g.s, func(s *series) {
+		s.value = value
+	})
+}
+
+// SetFunc sets the function computing the gauge value on every collection
+func (g *Gauge) SetFunc(fn func() float64) {
+	g.f.update(g.s, func(s *series) {
+		s.fn = fn
+	})
+}
+
+// Add adds the value to the gauge
+func (g *Gauge) Add(value float64) {
+	g.f.update(g.s, func(s *series) {
+		s.value += value
+	})
+}
+
+// Inc increments the gauge by 1
+func (g *Gauge) Inc() {
+	g.Add(1)
+}
+
+// Dec decrements the gauge by 1
+func (g *Gauge) Dec() {
+	g.Add(-1)
+}
+
+// HistogramVec is a histogram partitioned by the label values
+type HistogramVec struct {
+	f *family
+}
+
+// With returns the histogram with the label values
+func (v *HistogramVec) With(labelValues ...string) *Histogram {
+	return &Histogram{f: v.f, s: v.f.with(labelValues)}
+}
+
+// Histogram counts the observed values in the buckets
+type Histogram struct {
+	f *family
+	s *series
+}
+
+// Observe adds the value to the histogram
+func (h *Histogram) Observe(value float64) {
+	if math.IsNaN(value) {
+		return
+	}
+	i := sort.SearchFloat64s(h.f.buckets, value)
+	h.f.update(h.s, func(s *series) {
+		if i < len(s.counts) {
+			s.counts[i]++
+		}
+		s.sum += value
+		s.count++
+	})
+}
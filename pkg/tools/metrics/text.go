@@ -0,0 +1,158 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the content type of the Prometheus text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// WriteText writes all the metrics to the w in the Prometheus text exposition format
+func (r *Registry) WriteText(w io.Writer) error {
+	r.lock.Lock()
+	families := make([]*family, 0, len(r.families))
+	for _, f := range r.families {
+		families = append(families, f)
+	}
+	r.lock.Unlock()
+
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].name < families[j].name
+	})
+
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		f.writeText(bw)
+	}
+	return bw.Flush()
+}
+
+func (f *family) snapshot() []*series {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	snapshot := make([]*series, 0, len(f.series))
+	for _, s := range f.series {
+		c := *s
+		c.counts = append([]uint64(nil), s.counts...)
+		snapshot = append(snapshot, &c)
+	}
+	sort.Slice(snapshot, func(i, j int) bool {
+		return strings.Join(snapshot[i].labelValues, "\xff") < strings.Join(snapshot[j].labelValues, "\xff")
+	})
+	return snapshot
+}
+
+func (f *family) writeText(w *bufio.Writer) {
+	// Gauge functions are called out of the lock, since they can use the other locks
+	snapshot := f.snapshot()
+	if len(snapshot) == 0 {
+		return
+	}
+
+	_, _ = w.WriteString("# HELP " + f.name + " " + escapeHelp(f.help) + "\n")
+	_, _ = w.WriteString("# TYPE " + f.name + " " + f.kind.String() + "\n")
+
+	for _, s := range snapshot {
+		labels := f.labels(s.labelValues)
+		switch f.kind {
+		case histogramKind:
+			var count uint64
+			for i, bucket := range f.buckets {
+				count += s.counts[i]
+				writeSample(w, f.name+"_bucket", labels+label("le", formatFloat(bucket)), float64(count))
+			}
+			writeSample(w, f.name+"_bucket", labels+label("le", "+Inf"), float64(s.count))
+			writeSample(w, f.name+"_sum", labels, s.sum)
+			writeSample(w, f.name+"_count", labels, float64(s.count))
+		default:
+			value := s.value
+			if s.fn != nil {
+				value = s.fn()
+			}
+			writeSample(w, f.name, labels, value)
+		}
+	}
+}
+
+func (f *family) labels(labelValues []string) string {
+	var labels string
+	for i, name := range f.labelNames {
+		labels += label(name, labelValues[i])
+	}
+	return labels
+}
+
+func label(name, value string) string {
+	return name + `="` + escapeLabelValue(value) + `",`
+}
+
+func writeSample(w *bufio.Writer, name, labels string, value float64) {
+	_, _ = w.WriteString(name)
+	if labels != "" {
+		_, _ = w.WriteString("{" + strings.TrimSuffix(labels, ",") + "}")
+	}
+	_, _ = w.WriteString(" " + formatFloat(value) + "\n")
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+var (
+	helpReplacer       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(help string) string {
+	return helpReplacer.Replace(help)
+}
+
+func escapeLabelValue(value string) string {
+	return labelValueReplacer.Replace(value)
+}
+
+// Handler returns the http.Handler serving the metrics of the registry in the Prometheus text exposition format
+func Handler(r *Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		var buf bytes.Buffer
+		if err := r.WriteText(&buf); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", ContentType)
+		_, _ = w.Write(buf.Bytes())
+	})
+}
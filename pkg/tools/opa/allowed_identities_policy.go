@@ -0,0 +1,53 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opa
+
+// #nosec
+const allowedIdentitiesPolicy = `
+package registry
+
+default identity_allowed = false
+
+identity_allowed {
+	input.operation == "find"
+}
+
+identity_allowed {
+	input.operation != "find"
+	not identity_denied
+}
+
+identity_denied {
+	ns := input.network_service_names[_]
+	input.allowed_identities[ns]
+	not identity_listed(ns)
+}
+
+identity_listed(ns) {
+	input.allowed_identities[ns][_] == input.spiffe_id
+}
+`
+
+// WithAllowedIdentitiesPolicy returns default policy for checking that only the allowed identities can register
+// under the Network Service. Network Services with no allowed identities set are open for everyone.
+func WithAllowedIdentitiesPolicy() *AuthorizationPolicy {
+	return &AuthorizationPolicy{
+		policySource: allowedIdentitiesPolicy,
+		query:        "identity_allowed",
+		checker:      True("identity_allowed"),
+	}
+}
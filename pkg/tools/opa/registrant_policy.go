@@ -0,0 +1,48 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opa
+
+// #nosec
+const registrantPolicy = `
+package registry
+
+default registrant_allowed = false
+
+registrant_allowed {
+	input.operation == "find"
+}
+
+registrant_allowed {
+	input.spiffe_id != ""
+	input.registrant == ""
+}
+
+registrant_allowed {
+	input.spiffe_id != ""
+	input.registrant == input.spiffe_id
+}
+`
+
+// WithRegistrantPolicy returns default policy for checking that only the original registrant can update or
+// unregister the registry entry. Register and unregister calls without the verified SPIFFE ID are denied.
+func WithRegistrantPolicy() *AuthorizationPolicy {
+	return &AuthorizationPolicy{
+		policySource: registrantPolicy,
+		query:        "registrant_allowed",
+		checker:      True("registrant_allowed"),
+	}
+}
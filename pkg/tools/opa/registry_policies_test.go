@@ -0,0 +1,130 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opa_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/opa"
+)
+
+func TestWithRegistrantPolicy(t *testing.T) {
+	p := opa.WithRegistrantPolicy()
+
+	suits := []struct {
+		name    string
+		input   map[string]interface{}
+		allowed bool
+	}{
+		{
+			name:    "new entry",
+			input:   map[string]interface{}{"operation": "register", "spiffe_id": "spiffe://test.com/a", "registrant": ""},
+			allowed: true,
+		},
+		{
+			name:    "same registrant",
+			input:   map[string]interface{}{"operation": "unregister", "spiffe_id": "spiffe://test.com/a", "registrant": "spiffe://test.com/a"},
+			allowed: true,
+		},
+		{
+			name:    "another registrant",
+			input:   map[string]interface{}{"operation": "register", "spiffe_id": "spiffe://test.com/b", "registrant": "spiffe://test.com/a"},
+			allowed: false,
+		},
+		{
+			name:    "new entry without identity",
+			input:   map[string]interface{}{"operation": "register", "spiffe_id": "", "registrant": ""},
+			allowed: false,
+		},
+		{
+			name:    "entry without registrant",
+			input:   map[string]interface{}{"operation": "unregister", "spiffe_id": "", "registrant": ""},
+			allowed: false,
+		},
+		{
+			name:    "find",
+			input:   map[string]interface{}{"operation": "find", "spiffe_id": "spiffe://test.com/b", "registrant": "spiffe://test.com/a"},
+			allowed: true,
+		},
+	}
+
+	for i := range suits {
+		s := suits[i]
+		t.Run(s.name, func(t *testing.T) {
+			err := p.Check(context.Background(), s.input)
+			if s.allowed {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestWithAllowedIdentitiesPolicy(t *testing.T) {
+	p := opa.WithAllowedIdentitiesPolicy()
+
+	allowedIdentities := map[string]interface{}{
+		"ns-1": []string{"spiffe://test.com/a"},
+	}
+
+	suits := []struct {
+		name     string
+		spiffeID string
+		nsNames  []string
+		allowed  bool
+	}{
+		{
+			name:     "allowed identity",
+			spiffeID: "spiffe://test.com/a",
+			nsNames:  []string{"ns-1", "ns-2"},
+			allowed:  true,
+		},
+		{
+			name:     "not allowed identity",
+			spiffeID: "spiffe://test.com/b",
+			nsNames:  []string{"ns-2", "ns-1"},
+			allowed:  false,
+		},
+		{
+			name:     "no allowed identities set",
+			spiffeID: "spiffe://test.com/b",
+			nsNames:  []string{"ns-2"},
+			allowed:  true,
+		},
+	}
+
+	for i := range suits {
+		s := suits[i]
+		t.Run(s.name, func(t *testing.T) {
+			err := p.Check(context.Background(), map[string]interface{}{
+				"operation":             "register",
+				"spiffe_id":             s.spiffeID,
+				"network_service_names": s.nsNames,
+				"allowed_identities":    allowedIdentities,
+			})
+			if s.allowed {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opentelemetry
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+)
+
+// SpanEvent is the exported span event
+type SpanEvent struct {
+	Name       string                 `json:"name"`
+	Time       time.Time              `json:"time"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// Span is the exported span, FileExporter writes them to the file as JSON lines
+type Span struct {
+	Service           string                 `json:"service,omitempty"`
+	Name              string                 `json:"name"`
+	Kind              string                 `json:"kind"`
+	TraceID           string                 `json:"trace_id"`
+	SpanID            string                 `json:"span_id"`
+	ParentSpanID      string                 `json:"parent_span_id,omitempty"`
+	StartTime         time.Time              `json:"start_time"`
+	EndTime           time.Time              `json:"end_time"`
+	StatusCode        string                 `json:"status_code"`
+	StatusDescription string                 `json:"status_description,omitempty"`
+	Attributes        map[string]interface{} `json:"attributes,omitempty"`
+	Events            []*SpanEvent           `json:"events,omitempty"`
+}
+
+// FileExporter is a sdktrace.SpanExporter writing the spans to the file as JSON lines
+type FileExporter struct {
+	file    *os.File
+	encoder *json.Encoder
+	lock    sync.Mutex
+}
+
+// NewFileExporter creates a FileExporter appending the spans to the file at the path
+func NewFileExporter(path string) (*FileExporter, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open spans file: %s", path)
+	}
+	return &FileExporter{
+		file:    file,
+		encoder: json.NewEncoder(file),
+	}, nil
+}
+
+// ExportSpans writes the spans to the file
+func (e *FileExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.file == nil {
+		return errors.New("exporter is shut down")
+	}
+	for _, s := range spans {
+		if err := e.encoder.Encode(NewSpan(s)); err != nil {
+			return errors.Wrap(err, "failed to write span")
+		}
+	}
+	return nil
+}
+
+// Shutdown closes the file
+func (e *FileExporter) Shutdown(_ context.Context) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.file == nil {
+		return nil
+	}
+	err := e.file.Close()
+	e.file = nil
+	return err
+}
+
+// MemoryExporter is a sdktrace.SpanExporter keeping the spans in memory, the spans are kept after the shutdown
+type MemoryExporter struct {
+	spans []*Span
+	lock  sync.Mutex
+}
+
+// NewMemoryExporter creates an empty MemoryExporter
+func NewMemoryExporter() *MemoryExporter {
+	return &MemoryExporter{}
+}
+
+// ExportSpans stores the spans
+func (e *MemoryExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	for _, s := range spans {
+		e.spans = append(e.spans, NewSpan(s))
+	}
+	return nil
+}
+
+// Shutdown does nothing
+func (e *MemoryExporter) Shutdown(_ context.Context) error {
+	return nil
+}
+
+// Spans returns the exported spans in the order they were exported
+func (e *MemoryExporter) Spans() []*Span {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	return append([]*Span(nil), e.spans...)
+}
+
+// NewSpan converts the sdktrace.ReadOnlySpan to the Span
+func NewSpan(s sdktrace.ReadOnlySpan) *Span {
+	span := &Span{
+		Name:              s.Name(),
+		Kind:              s.SpanKind().String(),
+		TraceID:           s.SpanContext().TraceID().String(),
+		SpanID:            s.SpanContext().SpanID().String(),
+		StartTime:         s.StartTime(),
+		EndTime:           s.EndTime(),
+		StatusCode:        s.Status().Code.String(),
+		StatusDescription: s.Status().Description,
+		Attributes:        make(map[string]interface{}),
+	}
+	if s.Parent().IsValid() {
+		span.ParentSpanID = s.Parent().SpanID().String()
+	}
+	if s.Resource() != nil {
+		if service, ok := s.Resource().Set().Value(semconv.ServiceNameKey); ok {
+			span.Service = service.AsString()
+		}
+	}
+	for _, kv := range s.Attributes() {
+		span.Attributes[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	for _, e := range s.Events() {
+		event := &SpanEvent{
+			Name:       e.Name,
+			Time:       e.Time,
+			Attributes: make(map[string]interface{}),
+		}
+		for _, kv := range e.Attributes {
+			event.Attributes[string(kv.Key)] = kv.Value.AsInterface()
+		}
+		span.Events = append(span.Events, event)
+	}
+	return span
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opentelemetry_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/opentelemetry"
+)
+
+func TestFileExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.json")
+
+	exporter, err := opentelemetry.NewFileExporter(path)
+	require.NoError(t, err)
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	tracer := tp.Tracer("test")
+
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	_, child := tracer.Start(ctx, "child", trace.WithSpanKind(trace.SpanKindServer))
+	child.AddEvent("info", trace.WithAttributes(attribute.String("message", "hello")))
+	child.SetAttributes(attribute.Int64("count", 1))
+	child.End()
+	parent.End()
+
+	require.NoError(t, tp.Shutdown(context.Background()))
+
+	file, err := os.Open(filepath.Clean(path))
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+
+	var spans []*opentelemetry.Span
+	for decoder := json.NewDecoder(file); decoder.More(); {
+		span := new(opentelemetry.Span)
+		require.NoError(t, decoder.Decode(span))
+		spans = append(spans, span)
+	}
+	require.Len(t, spans, 2)
+
+	require.Equal(t, "child", spans[0].Name)
+	require.Equal(t, "server", spans[0].Kind)
+	require.Equal(t, spans[1].TraceID, spans[0].TraceID)
+	require.Equal(t, spans[1].SpanID, spans[0].ParentSpanID)
+	require.Equal(t, float64(1), spans[0].Attributes["count"])
+	require.Len(t, spans[0].Events, 1)
+	require.Equal(t, "hello", spans[0].Events[0].Attributes["message"])
+
+	require.Equal(t, "parent", spans[1].Name)
+	require.Empty(t, spans[1].ParentSpanID)
+}
+
+func TestInit_Disabled(t *testing.T) {
+	require.NoError(t, os.Setenv(opentelemetry.TracerTypeEnv, "jaeger"))
+	defer func() { _ = os.Unsetenv(opentelemetry.TracerTypeEnv) }()
+
+	require.False(t, opentelemetry.IsEnabled())
+
+	exporter := opentelemetry.NewMemoryExporter()
+	require.NoError(t, opentelemetry.Init(context.Background(), "test", exporter).Close())
+	require.Empty(t, exporter.Spans())
+}
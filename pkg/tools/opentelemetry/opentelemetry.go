@@ -0,0 +1,103 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package opentelemetry provides a set of utilities for assisting with using OpenTelemetry tracing. OpenTelemetry is
+// selected instead of the Jaeger opentracing with the TRACER_TYPE=opentelemetry env variable, the spans are
+// propagated between the hops in the W3C trace context format.
+package opentelemetry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+const (
+	// TracerTypeEnv is the env variable selecting the tracer implementation
+	TracerTypeEnv = "TRACER_TYPE"
+	// TracerType is the TracerTypeEnv value selecting OpenTelemetry
+	TracerType = "opentelemetry"
+
+	tracerEnabledEnv     = "TRACER_ENABLED"
+	tracerEnabledDefault = true
+
+	instrumentationName = "github.com/networkservicemesh/sdk"
+)
+
+// IsEnabled returns true if tracing is enabled and OpenTelemetry is selected as the tracer
+func IsEnabled() bool {
+	if os.Getenv(TracerTypeEnv) != TracerType {
+		return false
+	}
+	str := os.Getenv(tracerEnabledEnv)
+	if str == "" {
+		return tracerEnabledDefault
+	}
+	val, err := strconv.ParseBool(str)
+	if err != nil {
+		return tracerEnabledDefault
+	}
+	return val
+}
+
+type emptyCloser struct {
+}
+
+func (*emptyCloser) Close() error {
+	// Ignore
+	return nil
+}
+
+type tracerProviderCloser struct {
+	tp *sdktrace.TracerProvider
+}
+
+func (c *tracerProviderCloser) Close() error {
+	return c.tp.Shutdown(context.Background())
+}
+
+// Init - sets the global OpenTelemetry tracer provider sampling 100% of traces and exporting all spans with the
+// exporter. Returned io.Closer flushes the not yet exported spans and shuts the provider down.
+func Init(ctx context.Context, service string, exporter sdktrace.SpanExporter) io.Closer {
+	if !IsEnabled() {
+		return &emptyCloser{}
+	}
+
+	if hostname, err := os.Hostname(); err == nil {
+		service = fmt.Sprintf("%s@%s", service, hostname)
+	}
+
+	log.FromContext(ctx).Infof("Creating OpenTelemetry tracer provider for %s", service)
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(service))),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return &tracerProviderCloser{tp: tp}
+}
@@ -28,10 +28,15 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	"github.com/networkservicemesh/sdk/pkg/tools/jaeger"
+	"github.com/networkservicemesh/sdk/pkg/tools/opentelemetry"
 )
 
-// WithTracing - returns array of grpc.ServerOption that should be passed to grpc.Dial to enable opentracing
+// WithTracing - returns array of grpc.ServerOption that should be passed to grpc.Dial to enable opentracing, or
+// OpenTelemetry tracing if it is selected
 func WithTracing() []grpc.ServerOption {
+	if opentelemetry.IsEnabled() {
+		return opentelemetry.WithTracing()
+	}
 	if jaeger.IsOpentracingEnabled() {
 		interceptor := func(
 			ctx context.Context,
@@ -53,8 +58,12 @@ func WithTracing() []grpc.ServerOption {
 	}
 }
 
-// WithTracingDial returns array of grpc.DialOption that should be passed to grpc.Dial to enable opentracing
+// WithTracingDial returns array of grpc.DialOption that should be passed to grpc.Dial to enable opentracing, or
+// OpenTelemetry tracing if it is selected
 func WithTracingDial() []grpc.DialOption {
+	if opentelemetry.IsEnabled() {
+		return opentelemetry.WithTracingDial()
+	}
 	if jaeger.IsOpentracingEnabled() {
 		interceptor := func(
 			ctx context.Context,
@@ -0,0 +1,32 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package profile provides HDR histograms of the chain elements latencies. Profiling is disabled by default, if
+// enabled with Enable before the chains are created, every element of the chains records the time spent in its own
+// Request and Close excluding the time spent in the next elements.
+package profile
+
+var isProfilingEnabled = false
+
+// IsEnabled - checks if the chains should be profiled
+func IsEnabled() bool {
+	return isProfilingEnabled
+}
+
+// Enable - enable/disable profiling of the chains created after the call
+func Enable(enable bool) {
+	isProfilingEnabled = enable
+}
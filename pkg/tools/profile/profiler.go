@@ -0,0 +1,201 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package profile
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/metrics"
+)
+
+const (
+	lowestDiscernibleValue = int64(time.Microsecond)
+	highestTrackableValue  = int64(time.Hour)
+	significantFigures     = 2
+
+	durationMetric = "nsm_chain_element_duration_seconds"
+	callsMetric    = "nsm_chain_element_calls"
+)
+
+// Quantiles are the quantiles exposed in the metrics
+var Quantiles = []float64{.5, .9, .99, 1}
+
+// Stats are the latency statistics of the chain element method
+type Stats struct {
+	Element string
+	Method  string
+	Count   int64
+	Min     time.Duration
+	Mean    time.Duration
+	P50     time.Duration
+	P90     time.Duration
+	P99     time.Duration
+	Max     time.Duration
+}
+
+type key struct {
+	element, method string
+}
+
+type histogram struct {
+	h    *hdrhistogram.Histogram
+	lock sync.Mutex
+}
+
+// Profiler is a set of the HDR histograms of the chain elements latencies
+type Profiler struct {
+	registry   *metrics.Registry
+	histograms map[key]*histogram
+	lock       sync.Mutex
+}
+
+var defaultProfiler = New(WithMetrics(metrics.Default()))
+
+// New creates a new empty Profiler
+func New(options ...Option) *Profiler {
+	p := &Profiler{
+		histograms: make(map[key]*histogram),
+	}
+	for _, opt := range options {
+		opt(p)
+	}
+	return p
+}
+
+// Default returns the default Profiler used by the profiled chains, it exposes the latencies in the default metrics
+// Registry
+func Default() *Profiler {
+	return defaultProfiler
+}
+
+// Record records the duration of the element method call. Durations out of [1us, 1h] are clamped.
+func (p *Profiler) Record(element, method string, d time.Duration) {
+	value := int64(d)
+	switch {
+	case value < lowestDiscernibleValue:
+		value = lowestDiscernibleValue
+	case value > highestTrackableValue:
+		value = highestTrackableValue
+	}
+
+	h := p.histogram(key{element: element, method: method})
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	_ = h.h.RecordValue(value)
+}
+
+// Stats returns the statistics of all the recorded element methods ordered by the element and method
+func (p *Profiler) Stats() []*Stats {
+	p.lock.Lock()
+	keys := make([]key, 0, len(p.histograms))
+	histograms := make([]*histogram, 0, len(p.histograms))
+	for k, h := range p.histograms {
+		keys = append(keys, k)
+		histograms = append(histograms, h)
+	}
+	p.lock.Unlock()
+
+	var rv []*Stats
+	for i, h := range histograms {
+		if stats := h.stats(keys[i]); stats.Count > 0 {
+			rv = append(rv, stats)
+		}
+	}
+	sort.Slice(rv, func(i, j int) bool {
+		if rv[i].Element != rv[j].Element {
+			return rv[i].Element < rv[j].Element
+		}
+		return rv[i].Method < rv[j].Method
+	})
+	return rv
+}
+
+// Reset deletes all the recorded values
+func (p *Profiler) Reset() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for _, h := range p.histograms {
+		h.lock.Lock()
+		h.h.Reset()
+		h.lock.Unlock()
+	}
+}
+
+func (p *Profiler) histogram(k key) *histogram {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if h, ok := p.histograms[k]; ok {
+		return h
+	}
+
+	h := &histogram{
+		h: hdrhistogram.New(lowestDiscernibleValue, highestTrackableValue, significantFigures),
+	}
+	p.histograms[k] = h
+
+	if p.registry != nil {
+		p.register(k, h)
+	}
+
+	return h
+}
+
+func (p *Profiler) register(k key, h *histogram) {
+	durations := p.registry.Gauge(durationMetric,
+		"Quantiles of the time spent in the chain element method excluding the next elements",
+		"element", "method", "quantile")
+	for _, q := range Quantiles {
+		quantile := q
+		durations.With(k.element, k.method, formatQuantile(quantile)).SetFunc(func() float64 {
+			h.lock.Lock()
+			defer h.lock.Unlock()
+			return time.Duration(h.h.ValueAtQuantile(quantile * 100)).Seconds()
+		})
+	}
+
+	p.registry.Gauge(callsMetric, "Number of the profiled chain element method calls", "element", "method").
+		With(k.element, k.method).SetFunc(func() float64 {
+		h.lock.Lock()
+		defer h.lock.Unlock()
+		return float64(h.h.TotalCount())
+	})
+}
+
+func (h *histogram) stats(k key) *Stats {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	return &Stats{
+		Element: k.element,
+		Method:  k.method,
+		Count:   h.h.TotalCount(),
+		Min:     time.Duration(h.h.Min()),
+		Mean:    time.Duration(h.h.Mean()),
+		P50:     time.Duration(h.h.ValueAtQuantile(50)),
+		P90:     time.Duration(h.h.ValueAtQuantile(90)),
+		P99:     time.Duration(h.h.ValueAtQuantile(99)),
+		Max:     time.Duration(h.h.Max()),
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package profile_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/metrics"
+	"github.com/networkservicemesh/sdk/pkg/tools/profile"
+)
+
+const precision = 0.01
+
+func requireDuration(t *testing.T, expected, actual time.Duration) {
+	require.InEpsilon(t, float64(expected), float64(actual), precision)
+}
+
+func TestProfiler_Stats(t *testing.T) {
+	p := profile.New()
+
+	for i := 1; i <= 100; i++ {
+		p.Record("a", "Request", time.Duration(i)*time.Millisecond)
+	}
+	p.Record("a", "Close", 2*time.Hour)
+	p.Record("b", "Request", 0)
+
+	stats := p.Stats()
+	require.Len(t, stats, 3)
+
+	require.Equal(t, "a", stats[0].Element)
+	require.Equal(t, "Close", stats[0].Method)
+	require.Equal(t, int64(1), stats[0].Count)
+	requireDuration(t, time.Hour, stats[0].Max)
+
+	require.Equal(t, "a", stats[1].Element)
+	require.Equal(t, "Request", stats[1].Method)
+	require.Equal(t, int64(100), stats[1].Count)
+	requireDuration(t, time.Millisecond, stats[1].Min)
+	requireDuration(t, 50*time.Millisecond, stats[1].P50)
+	requireDuration(t, 90*time.Millisecond, stats[1].P90)
+	requireDuration(t, 99*time.Millisecond, stats[1].P99)
+	requireDuration(t, 100*time.Millisecond, stats[1].Max)
+
+	// Too short durations are clamped to the lowest discernible value
+	require.Equal(t, "b", stats[2].Element)
+	require.GreaterOrEqual(t, int64(stats[2].Max), int64(time.Microsecond))
+	require.Less(t, int64(stats[2].Max), int64(2*time.Microsecond))
+
+	p.Reset()
+	require.Empty(t, p.Stats())
+}
+
+func TestProfiler_Metrics(t *testing.T) {
+	r := metrics.NewRegistry()
+	p := profile.New(profile.WithMetrics(r))
+
+	p.Record("a", "Request", time.Second)
+	p.Record("a", "Request", time.Second)
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteText(&buf))
+
+	text := buf.String()
+	require.Contains(t, text, "# TYPE nsm_chain_element_duration_seconds gauge\n")
+	require.Contains(t, text, `nsm_chain_element_calls{element="a",method="Request"} 2`+"\n")
+	for _, q := range []string{"0.5", "0.9", "0.99", "1"} {
+		require.Contains(t, text, `nsm_chain_element_duration_seconds{element="a",method="Request",quantile="`+q+`"} 1.00`)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	p := profile.New()
+	p.Record("fast", "Request", time.Millisecond)
+	p.Record("slow", "Request", 3*time.Second)
+
+	get := func(url string) string {
+		w := httptest.NewRecorder()
+		profile.Handler(p).ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+
+		resp := w.Result()
+		defer func() { _ = resp.Body.Close() }()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	lines := strings.Split(strings.TrimSpace(get("/")), "\n")
+	require.Len(t, lines, 3)
+	require.True(t, strings.HasPrefix(lines[0], "ELEMENT"))
+	require.True(t, strings.HasPrefix(lines[1], "slow"))
+	require.True(t, strings.HasPrefix(lines[2], "fast"))
+
+	require.Len(t, strings.Split(strings.TrimSpace(get("/?reset")), "\n"), 3)
+	require.Len(t, strings.Split(strings.TrimSpace(get("/")), "\n"), 1)
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package profile
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// WriteText writes the statistics as a text table, the slowest element methods by the 99th percentile go first
+func (p *Profiler) WriteText(w io.Writer) error {
+	stats := p.Stats()
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].P99 > stats[j].P99
+	})
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ELEMENT\tMETHOD\tCOUNT\tMIN\tMEAN\tP50\tP90\tP99\tMAX")
+	for _, s := range stats {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			s.Element, s.Method, s.Count,
+			formatDuration(s.Min), formatDuration(s.Mean),
+			formatDuration(s.P50), formatDuration(s.P90), formatDuration(s.P99),
+			formatDuration(s.Max))
+	}
+	return tw.Flush()
+}
+
+// Handler returns the http.Handler serving the statistics of the profiler as a text table. "reset" query parameter
+// resets the profiler after the statistics are written.
+func Handler(p *Profiler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		if err := p.WriteText(&buf); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if _, ok := r.URL.Query()["reset"]; ok {
+			p.Reset()
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write(buf.Bytes())
+	})
+}
+
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Microsecond).String()
+}
+
+func formatQuantile(q float64) string {
+	return strconv.FormatFloat(q, 'g', -1, 64)
+}
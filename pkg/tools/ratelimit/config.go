@@ -0,0 +1,66 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// Limit is a token bucket limit
+type Limit struct {
+	// Rate is the number of the calls allowed per second, zero rate means no limit
+	Rate float64 `json:"rate"`
+	// Burst is the number of the calls allowed at once, at least 1 call is always allowed
+	Burst int `json:"burst"`
+}
+
+// Config is a rate limiting configuration. Every call is limited both by the limit of the peer and by the limits of
+// the requested Network Services.
+type Config struct {
+	// Peer is the default limit for every peer
+	Peer Limit `json:"peer"`
+	// Peers are the limits for the peers by their identities
+	Peers map[string]Limit `json:"peers,omitempty"`
+	// NetworkService is the default limit for every Network Service
+	NetworkService Limit `json:"networkService"`
+	// NetworkServices are the limits for the Network Services by their names
+	NetworkServices map[string]Limit `json:"networkServices,omitempty"`
+}
+
+// ParseConfig parses YAML or JSON rate limiting configuration
+func ParseConfig(data []byte) (*Config, error) {
+	config := new(Config)
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, errors.Wrap(err, "failed to parse rate limiting config")
+	}
+	return config, nil
+}
+
+func (c *Config) peerLimit(peer string) Limit {
+	if limit, ok := c.Peers[peer]; ok {
+		return limit
+	}
+	return c.Peer
+}
+
+func (c *Config) networkServiceLimit(networkService string) Limit {
+	if limit, ok := c.NetworkServices[networkService]; ok {
+		return limit
+	}
+	return c.NetworkService
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/status"
+)
+
+// limitError is the codes.ResourceExhausted error having the stack trace, so it is passed as is by the trace chain
+// elements and keeps its status code on the wire
+type limitError struct {
+	error
+	status *status.Status
+}
+
+func newLimitError(s *status.Status) error {
+	return &limitError{
+		error:  errors.WithStack(s.Err()),
+		status: s,
+	}
+}
+
+func (e *limitError) GRPCStatus() *status.Status {
+	return e.status
+}
+
+func (e *limitError) Cause() error {
+	return errors.Cause(e.error)
+}
+
+func (e *limitError) StackTrace() errors.StackTrace {
+	return e.error.(interface{ StackTrace() errors.StackTrace }).StackTrace()
+}
+
+func (e *limitError) Format(s fmt.State, verb rune) {
+	e.error.(fmt.Formatter).Format(s, verb)
+}
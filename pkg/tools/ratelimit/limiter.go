@@ -0,0 +1,208 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/fs"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+// RetryAfterKey is the trailer metadata key of the rejected calls, its value is the number of seconds to wait before
+// the retry
+const RetryAfterKey = "retry-after"
+
+const cleanupInterval = time.Minute
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// refill adds the tokens accumulated since the last call and returns the time needed to get 1 token
+func (b *bucket) refill(limit Limit, now time.Time) time.Duration {
+	burst := math.Max(float64(limit.Burst), 1)
+	if b.last.IsZero() {
+		b.tokens = burst
+	} else {
+		b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+}
+
+// full returns true if the bucket is full or not limited anymore
+func (b *bucket) full(limit Limit, now time.Time) bool {
+	if limit.Rate <= 0 {
+		return true
+	}
+	b.refill(limit, now)
+	return b.tokens >= math.Max(float64(limit.Burst), 1)
+}
+
+// Limiter is a token bucket rate limiter keyed by the peer identity and by the Network Service
+type Limiter struct {
+	ctx   context.Context
+	clock clock.Clock
+
+	lock            sync.Mutex
+	config          *Config
+	peers           map[string]*bucket
+	networkServices map[string]*bucket
+}
+
+// NewLimiter creates a new Limiter. Without a configuration the calls are not limited.
+func NewLimiter(ctx context.Context, options ...Option) *Limiter {
+	o := &limiterOptions{
+		config: new(Config),
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+
+	l := &Limiter{
+		ctx:             ctx,
+		clock:           clock.FromContext(ctx),
+		config:          o.config,
+		peers:           make(map[string]*bucket),
+		networkServices: make(map[string]*bucket),
+	}
+
+	if o.configPath != "" {
+		updateCh := fs.WatchFile(ctx, o.configPath)
+		l.update(<-updateCh)
+		go func() {
+			for data := range updateCh {
+				l.update(data)
+			}
+		}()
+	}
+
+	go l.cleanup(l.clock.Ticker(cleanupInterval))
+
+	return l
+}
+
+func (l *Limiter) update(data []byte) {
+	config := new(Config)
+	if data != nil {
+		var err error
+		if config, err = ParseConfig(data); err != nil {
+			log.FromContext(l.ctx).Errorf("keeping the previous rate limiting config: %s", err.Error())
+			return
+		}
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.config = config
+}
+
+// Allow takes a token from the buckets of the caller peer and of the networkServices. If any bucket is empty, no tokens
+// are taken and codes.ResourceExhausted error is returned, RetryAfterKey trailer is also set for the gRPC calls.
+func (l *Limiter) Allow(ctx context.Context, networkServices ...string) error {
+	peer := PeerIdentity(ctx)
+
+	l.lock.Lock()
+
+	now := l.clock.Now()
+
+	var buckets []*bucket
+	var retryAfter time.Duration
+	take := func(buckets map[string]*bucket, key string, limit Limit) *bucket {
+		if limit.Rate <= 0 {
+			return nil
+		}
+		b, ok := buckets[key]
+		if !ok {
+			b = new(bucket)
+			buckets[key] = b
+		}
+		if d := b.refill(limit, now); d > retryAfter {
+			retryAfter = d
+		}
+		return b
+	}
+
+	if b := take(l.peers, peer, l.config.peerLimit(peer)); b != nil {
+		buckets = append(buckets, b)
+	}
+	for _, ns := range networkServices {
+		if b := take(l.networkServices, ns, l.config.networkServiceLimit(ns)); b != nil {
+			buckets = append(buckets, b)
+		}
+	}
+
+	if retryAfter == 0 {
+		for _, b := range buckets {
+			b.tokens--
+		}
+	}
+
+	l.lock.Unlock()
+
+	if retryAfter == 0 {
+		return nil
+	}
+
+	seconds := int64(math.Ceil(retryAfter.Seconds()))
+	_ = grpc.SetTrailer(ctx, metadata.Pairs(RetryAfterKey, strconv.FormatInt(seconds, 10)))
+
+	return newLimitError(status.Newf(codes.ResourceExhausted, "rate limit exceeded for %q %v, retry after %s", peer, networkServices, retryAfter))
+}
+
+// cleanup periodically removes the full buckets, since they are the same as the new ones
+func (l *Limiter) cleanup(ticker clock.Ticker) {
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.ctx.Done():
+			return
+		case <-ticker.C():
+			l.lock.Lock()
+			now := l.clock.Now()
+			for key, b := range l.peers {
+				if b.full(l.config.peerLimit(key), now) {
+					delete(l.peers, key)
+				}
+			}
+			for key, b := range l.networkServices {
+				if b.full(l.config.networkServiceLimit(key), now) {
+					delete(l.networkServices, key)
+				}
+			}
+			l.lock.Unlock()
+		}
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/clockmock"
+	"github.com/networkservicemesh/sdk/pkg/tools/ratelimit"
+	"github.com/networkservicemesh/sdk/pkg/tools/sandbox"
+)
+
+const (
+	spiffeIDA = "spiffe://test.com/a"
+	spiffeIDB = "spiffe://test.com/b"
+)
+
+func requireResourceExhausted(t *testing.T, err error) {
+	require.Error(t, err)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestLimiter_PeerLimit(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	clockMock.Set(time.Now())
+	ctx = clock.WithClock(ctx, clockMock)
+
+	limiter := ratelimit.NewLimiter(ctx, ratelimit.WithConfig(&ratelimit.Config{
+		Peer: ratelimit.Limit{Rate: 1, Burst: 2},
+		Peers: map[string]ratelimit.Limit{
+			spiffeIDB: {},
+		},
+	}))
+
+	ctxA, ctxB := sandbox.WithPeerSpiffeID(ctx, t, spiffeIDA), sandbox.WithPeerSpiffeID(ctx, t, spiffeIDA+"/other")
+
+	// 1. Burst is allowed
+	require.NoError(t, limiter.Allow(ctxA))
+	require.NoError(t, limiter.Allow(ctxA))
+	requireResourceExhausted(t, limiter.Allow(ctxA))
+
+	// 2. Other peer has its own bucket
+	require.NoError(t, limiter.Allow(ctxB))
+
+	// 3. Peer without limit is not limited
+	for i := 0; i < 10; i++ {
+		require.NoError(t, limiter.Allow(sandbox.WithPeerSpiffeID(ctx, t, spiffeIDB)))
+	}
+
+	// 4. Bucket is refilled with the rate
+	clockMock.Add(time.Second)
+
+	require.NoError(t, limiter.Allow(ctxA))
+	requireResourceExhausted(t, limiter.Allow(ctxA))
+}
+
+func TestLimiter_NetworkServiceLimit(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clockMock := clockmock.NewMock()
+	clockMock.Set(time.Now())
+	ctx = clock.WithClock(ctx, clockMock)
+
+	limiter := ratelimit.NewLimiter(ctx, ratelimit.WithConfig(&ratelimit.Config{
+		Peer: ratelimit.Limit{Rate: 1, Burst: 2},
+		NetworkServices: map[string]ratelimit.Limit{
+			"ns-1": {Rate: 1, Burst: 1},
+		},
+	}))
+
+	ctxA, ctxB := sandbox.WithPeerSpiffeID(ctx, t, spiffeIDA), sandbox.WithPeerSpiffeID(ctx, t, spiffeIDB)
+
+	// 1. Network Service limit is shared by all the peers
+	require.NoError(t, limiter.Allow(ctxA, "ns-1"))
+	requireResourceExhausted(t, limiter.Allow(ctxB, "ns-1"))
+
+	// 2. Rejected call doesn't take the peer tokens
+	require.NoError(t, limiter.Allow(ctxB, "ns-2"))
+	require.NoError(t, limiter.Allow(ctxB, "ns-2"))
+	requireResourceExhausted(t, limiter.Allow(ctxB, "ns-2"))
+}
+
+func TestLimiter_ConfigFile(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir, err := ioutil.TempDir("", "ratelimit")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	configPath := filepath.Join(dir, "config.yaml")
+	require.NoError(t, ioutil.WriteFile(configPath, []byte("peer:\n  rate: 0.001\n  burst: 1\n"), 0600))
+
+	limiter := ratelimit.NewLimiter(ctx, ratelimit.WithConfigPath(configPath))
+
+	ctxA := sandbox.WithPeerSpiffeID(ctx, t, spiffeIDA)
+
+	require.NoError(t, limiter.Allow(ctxA))
+	requireResourceExhausted(t, limiter.Allow(ctxA))
+
+	// Limits are removed on the file update
+	require.NoError(t, ioutil.WriteFile(configPath, []byte("peer:\n  rate: 0\n"), 0600))
+
+	require.Eventually(t, func() bool {
+		return limiter.Allow(ctxA) == nil
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestLimiter_ForgedToken(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	limiter := ratelimit.NewLimiter(ctx, ratelimit.WithConfig(&ratelimit.Config{
+		Peer: ratelimit.Limit{Rate: 1, Burst: 1},
+	}))
+
+	withForgedToken := func(spiffeID string) context.Context {
+		tok, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.StandardClaims{
+			Subject: spiffeID,
+		}).SignedString([]byte("super secret"))
+		require.NoError(t, err)
+
+		return metadata.NewIncomingContext(ctx, metadata.Pairs(
+			"nsm-client-token", tok,
+			"nsm-client-token-expires", time.Now().Add(time.Hour).Format(time.RFC3339Nano),
+		))
+	}
+
+	// Unverified tokens don't give the caller a new bucket
+	require.NoError(t, limiter.Allow(withForgedToken(spiffeIDA)))
+	requireResourceExhausted(t, limiter.Allow(withForgedToken(spiffeIDB)))
+}
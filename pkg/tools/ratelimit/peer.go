@@ -0,0 +1,45 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc/peer"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/spiffejwt"
+)
+
+// PeerIdentity returns the identity of the caller: the verified SPIFFE ID of the direct caller if any (see
+// spiffejwt.PeerSpiffeID), otherwise the peer address. All the peers connected to the same unix socket without a SPIFFE
+// ID share the same identity.
+func PeerIdentity(ctx context.Context) string {
+	if spiffeID, err := spiffejwt.PeerSpiffeID(ctx); err == nil {
+		return spiffeID.String()
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	if tcpAddr, ok := p.Addr.(*net.TCPAddr); ok {
+		// Port is different for every connection of the same peer
+		return "tcp:" + tcpAddr.IP.String()
+	}
+	return p.Addr.Network() + ":" + p.Addr.String()
+}
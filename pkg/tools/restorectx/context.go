@@ -0,0 +1,50 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package restorectx allows marking the Request restoring the connection loaded from the checkpoint after the restart.
+// Restoring Request is connected to the client URL already set in the context without the discovery and selection,
+// the client chain receives the stored connection back without requesting the next hop.
+package restorectx
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type restoreKeyType struct{}
+
+type restoreState struct {
+	done int32
+}
+
+// WithRestore returns a new context marking the Request as restoring the stored connection and the function ending
+// the restore. The mark is valid only until the function is called, so the contexts derived from the restoring Request
+// context and used later (e.g. by the refresh and heal) don't restore the connection again.
+func WithRestore(parent context.Context) (context.Context, func()) {
+	if parent == nil {
+		panic("cannot create context from nil parent")
+	}
+	state := new(restoreState)
+	return context.WithValue(parent, restoreKeyType{}, state), func() {
+		atomic.StoreInt32(&state.done, 1)
+	}
+}
+
+// IsRestore returns true if the Request is marked with WithRestore and the restore is not ended yet
+func IsRestore(ctx context.Context) bool {
+	state, ok := ctx.Value(restoreKeyType{}).(*restoreState)
+	return ok && atomic.LoadInt32(&state.done) == 0
+}
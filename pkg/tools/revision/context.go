@@ -0,0 +1,81 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revision
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	watchMDKey  = "nsm-registry-watch-revision"
+	relistValue = "relist"
+)
+
+type watchKeyType struct{}
+
+// Watch is a request for the watch with revisions
+type Watch struct {
+	// Start is the revision the watch starts from, the watch relists if it is nil
+	Start *Revision
+}
+
+// WithRelist returns a new context for the watch sending the current entities and then the events, all with the
+// revisions. The request is also set in the outgoing gRPC metadata, so it can be passed to the remote registry.
+func WithRelist(parent context.Context) context.Context {
+	return withWatch(parent, new(Watch), relistValue)
+}
+
+// WithStart returns a new context for the watch sending only the events with the revisions greater than the given
+// one. The request is also set in the outgoing gRPC metadata, so it can be passed to the remote registry.
+func WithStart(parent context.Context, revision Revision) context.Context {
+	return withWatch(parent, &Watch{Start: &revision}, revision.String())
+}
+
+func withWatch(parent context.Context, watch *Watch, value string) context.Context {
+	if parent == nil {
+		panic("cannot create context from nil parent")
+	}
+	ctx := context.WithValue(parent, watchKeyType{}, watch)
+	return metadata.AppendToOutgoingContext(ctx, watchMDKey, value)
+}
+
+// FromContext returns the watch request, nil if the watch has been requested with no revisions
+func FromContext(ctx context.Context) (*Watch, error) {
+	if watch, ok := ctx.Value(watchKeyType{}).(*Watch); ok {
+		return watch, nil
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+	values := md.Get(watchMDKey)
+	if len(values) == 0 {
+		return nil, nil
+	}
+	value := values[len(values)-1]
+	if value == relistValue {
+		return new(Watch), nil
+	}
+	revision, err := parseRevision(value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid watch revision: %s", value)
+	}
+	return &Watch{Start: &revision}, nil
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package revision provides utilities for the registry watch revisions. Registry API has no field for the revision,
+// so it is passed with the events as a reserved unknown protobuf field and with the watch requests as a gRPC
+// metadata. Events are stamped only for the watches requested with revisions, clients should Clear the revision
+// before passing the entities further.
+package revision
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	fieldNumber      protowire.Number = 2047
+	epochFieldNumber protowire.Number = 2046
+)
+
+// Revision is a position in the registry event history. Registry starts a new epoch on each start, so the revisions
+// counted from the beginning again are not confused with the ones from the previous run.
+type Revision struct {
+	// Epoch is the epoch of the registry history
+	Epoch uint64
+	// Index is the number of the event in the epoch
+	Index uint64
+}
+
+func (r Revision) String() string {
+	return strconv.FormatUint(r.Epoch, 10) + "." + strconv.FormatUint(r.Index, 10)
+}
+
+func parseRevision(s string) (Revision, error) {
+	split := strings.SplitN(s, ".", 2)
+	if len(split) != 2 {
+		// Revision without the epoch can't be resumed in any epoch
+		index, err := strconv.ParseUint(s, 10, 64)
+		return Revision{Index: index}, errors.WithStack(err)
+	}
+	epoch, err := strconv.ParseUint(split[0], 10, 64)
+	if err != nil {
+		return Revision{}, errors.WithStack(err)
+	}
+	index, err := strconv.ParseUint(split[1], 10, 64)
+	if err != nil {
+		return Revision{}, errors.WithStack(err)
+	}
+	return Revision{Epoch: epoch, Index: index}, nil
+}
+
+// ErrTooOld is returned for the watch requests starting from the revision already compacted from the history or from
+// the other epoch. Client should relist in such case.
+var ErrTooOld = status.Error(codes.OutOfRange, "revision is compacted, relist is required")
+
+// IsTooOld returns true if err is caused by ErrTooOld
+func IsTooOld(err error) bool {
+	s, ok := status.FromError(errors.Cause(err))
+	return ok && s.Code() == codes.OutOfRange
+}
+
+// Set sets the revision for the entity
+func Set(entity proto.Message, revision Revision) {
+	m := entity.ProtoReflect()
+	b := strip(m.GetUnknown())
+	b = protowire.AppendTag(b, epochFieldNumber, protowire.VarintType)
+	b = protowire.AppendVarint(b, revision.Epoch)
+	b = protowire.AppendTag(b, fieldNumber, protowire.VarintType)
+	b = protowire.AppendVarint(b, revision.Index)
+	m.SetUnknown(b)
+}
+
+// Clear removes the revision from the entity
+func Clear(entity proto.Message) {
+	m := entity.ProtoReflect()
+	if b := m.GetUnknown(); len(b) > 0 {
+		m.SetUnknown(strip(b))
+	}
+}
+
+// Get returns the revision of the entity, false if the entity has no revision
+func Get(entity proto.Message) (revision Revision, ok bool) {
+	if entity == nil {
+		return Revision{}, false
+	}
+	for b := entity.ProtoReflect().GetUnknown(); len(b) > 0; {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return Revision{}, false
+		}
+		if typ == protowire.VarintType {
+			if v, m := protowire.ConsumeVarint(b[n:]); m >= 0 {
+				switch num {
+				case fieldNumber:
+					revision.Index, ok = v, true
+				case epochFieldNumber:
+					revision.Epoch = v
+				}
+			}
+		}
+		m := protowire.ConsumeFieldValue(num, typ, b[n:])
+		if m < 0 {
+			return Revision{}, false
+		}
+		b = b[n+m:]
+	}
+	return revision, ok
+}
+
+func strip(b []byte) (result []byte) {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return result
+		}
+		m := protowire.ConsumeFieldValue(num, typ, b[n:])
+		if m < 0 {
+			return result
+		}
+		if num != fieldNumber && num != epochFieldNumber {
+			result = append(result, b[:n+m]...)
+		}
+		b = b[n+m:]
+	}
+	return result
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package revision_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/networkservicemesh/api/pkg/api/registry"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/revision"
+)
+
+func TestRevision_SetGetClear(t *testing.T) {
+	nse := &registry.NetworkServiceEndpoint{Name: "nse"}
+
+	_, ok := revision.Get(nse)
+	require.False(t, ok)
+
+	revision.Set(nse, revision.Revision{Epoch: 1, Index: 1})
+	revision.Set(nse, revision.Revision{Epoch: 2, Index: 42})
+
+	// Revision should survive the marshaling
+	b, err := proto.Marshal(nse)
+	require.NoError(t, err)
+
+	received := new(registry.NetworkServiceEndpoint)
+	require.NoError(t, proto.Unmarshal(b, received))
+
+	rev, ok := revision.Get(received)
+	require.True(t, ok)
+	require.Equal(t, revision.Revision{Epoch: 2, Index: 42}, rev)
+
+	revision.Clear(received)
+
+	_, ok = revision.Get(received)
+	require.False(t, ok)
+	require.True(t, proto.Equal(&registry.NetworkServiceEndpoint{Name: "nse"}, received))
+}
+
+func TestRevision_Context(t *testing.T) {
+	watch, err := revision.FromContext(context.Background())
+	require.NoError(t, err)
+	require.Nil(t, watch)
+
+	watch, err = revision.FromContext(revision.WithRelist(context.Background()))
+	require.NoError(t, err)
+	require.NotNil(t, watch)
+	require.Nil(t, watch.Start)
+
+	// Remote registry receives the watch request in the incoming metadata
+	md, _ := metadata.FromOutgoingContext(revision.WithStart(context.Background(), revision.Revision{Epoch: 7, Index: 5}))
+
+	watch, err = revision.FromContext(metadata.NewIncomingContext(context.Background(), md))
+	require.NoError(t, err)
+	require.NotNil(t, watch.Start)
+	require.Equal(t, revision.Revision{Epoch: 7, Index: 5}, *watch.Start)
+}
@@ -18,6 +18,7 @@ package sandbox
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"net/url"
 	"testing"
@@ -34,6 +35,7 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/authorize"
 	"github.com/networkservicemesh/sdk/pkg/registry/chains/client"
 	"github.com/networkservicemesh/sdk/pkg/registry/chains/memory"
+	"github.com/networkservicemesh/sdk/pkg/registry/common/replicate"
 	"github.com/networkservicemesh/sdk/pkg/registry/chains/proxydns"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/dnsresolve"
 	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
@@ -57,6 +59,7 @@ type Builder struct {
 	setupNode              SetupNodeFunc
 	generateTokenFunc      token.GeneratorFunc
 	registryExpiryDuration time.Duration
+	registryReplicasCount  int
 	ctx                    context.Context
 }
 
@@ -74,6 +77,7 @@ func NewBuilder(t *testing.T) *Builder {
 		setupNode:              defaultSetupNode(t),
 		generateTokenFunc:      GenerateTestToken,
 		registryExpiryDuration: time.Minute,
+		registryReplicasCount:  1,
 	}
 }
 
@@ -95,13 +99,18 @@ func (b *Builder) Build() *Domain {
 		domain.RegistryProxy = b.newRegistryProxy(ctx, domain.NSMgrProxy.URL)
 	}
 	if domain.RegistryProxy == nil {
-		domain.Registry = b.newRegistry(ctx, nil)
+		domain.RegistryReplicas = b.newRegistries(ctx, nil)
 	} else {
-		domain.Registry = b.newRegistry(ctx, domain.RegistryProxy.URL)
+		domain.RegistryReplicas = b.newRegistries(ctx, domain.RegistryProxy.URL)
+	}
+	if len(domain.RegistryReplicas) > 0 {
+		domain.Registry = domain.RegistryReplicas[0]
 	}
 
 	for i := 0; i < b.nodesCount; i++ {
-		domain.Nodes = append(domain.Nodes, b.newNode(ctx, domain.Registry.URL, b.nodesConfig[i]))
+		// Nodes are spread between the registry replicas
+		registry := domain.RegistryReplicas[i%len(domain.RegistryReplicas)]
+		domain.Nodes = append(domain.Nodes, b.newNode(ctx, registry.URL, b.nodesConfig[i]))
 	}
 
 	domain.resources, b.resources = b.resources, nil
@@ -186,7 +195,8 @@ func (b *Builder) SetRegistryProxySupplier(f SupplyRegistryProxyFunc) *Builder {
 	return b
 }
 
-// SetRegistrySupplier replaces default memory registry supplier to custom function
+// SetRegistrySupplier replaces default memory registry supplier to custom function, see SupplyRegistryFunc for the
+// migration of the suppliers taking the gRPC Dial Options
 func (b *Builder) SetRegistrySupplier(f SupplyRegistryFunc) *Builder {
 	b.supplyRegistry = f
 	return b
@@ -216,6 +226,12 @@ func (b *Builder) SetNodeSetup(f SetupNodeFunc) *Builder {
 	return b
 }
 
+// SetRegistryReplicasCount sets count of the registry replicas replicating the registry state to each other
+func (b *Builder) SetRegistryReplicasCount(registryReplicasCount int) *Builder {
+	b.registryReplicasCount = registryReplicasCount
+	return b
+}
+
 // SetRegistryExpiryDuration replaces registry expiry duration to custom
 func (b *Builder) SetRegistryExpiryDuration(registryExpiryDuration time.Duration) *Builder {
 	b.registryExpiryDuration = registryExpiryDuration
@@ -279,7 +295,8 @@ func (b *Builder) newNSMgr(ctx context.Context, address string, registryURL *url
 		Url:  serveURL.String(),
 	}
 
-	mgr := b.supplyNSMgr(ctx, nsmgrReg, authorize.NewServer(authorize.Any()), generateTokenFunc, registryCC, DefaultDialOptions(generateTokenFunc)...)
+	mgr := b.supplyNSMgr(ctx, nsmgrReg, authorize.NewServer(authorize.Any()), generateTokenFunc, registryCC,
+		nsmgr.WithDialOptions(DefaultDialOptions(generateTokenFunc)...))
 
 	serve(ctx, serveURL, mgr.Register)
 	log.FromContext(ctx).Infof("%v listen on: %v", nsmgrReg.Name, serveURL)
@@ -320,12 +337,42 @@ func (b *Builder) newRegistryProxy(ctx context.Context, nsmgrProxyURL *url.URL)
 	}
 }
 
-func (b *Builder) newRegistry(ctx context.Context, proxyRegistryURL *url.URL) *RegistryEntry {
+func (b *Builder) newRegistries(ctx context.Context, proxyRegistryURL *url.URL) []*RegistryEntry {
 	if b.supplyRegistry == nil {
 		return nil
 	}
-	result := b.supplyRegistry(ctx, b.registryExpiryDuration, proxyRegistryURL, DefaultDialOptions(b.generateTokenFunc)...)
-	serveURL := &url.URL{Scheme: "tcp", Host: "127.0.0.1:0"}
+	if b.registryReplicasCount == 1 {
+		return []*RegistryEntry{
+			b.newRegistry(ctx, proxyRegistryURL, &url.URL{Scheme: "tcp", Host: "127.0.0.1:0"}),
+		}
+	}
+
+	// Replicas should know URLs of each other before start
+	var serveURLs []*url.URL
+	for i := 0; i < b.registryReplicasCount; i++ {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		b.require.NoError(err)
+		serveURLs = append(serveURLs, grpcutils.AddressToURL(listener.Addr()))
+		b.require.NoError(listener.Close())
+	}
+
+	var registries []*RegistryEntry
+	for i, serveURL := range serveURLs {
+		var peers []*url.URL
+		for j, peerURL := range serveURLs {
+			if j != i {
+				peers = append(peers, peerURL)
+			}
+		}
+		registries = append(registries, b.newRegistry(ctx, proxyRegistryURL, serveURL,
+			memory.WithReplication(fmt.Sprintf("registry-%d", i), peers, replicate.WithInsecurePeers())))
+	}
+	return registries
+}
+
+func (b *Builder) newRegistry(ctx context.Context, proxyRegistryURL, serveURL *url.URL, options ...memory.Option) *RegistryEntry {
+	result := b.supplyRegistry(ctx, b.registryExpiryDuration, proxyRegistryURL,
+		append([]memory.Option{memory.WithDialOptions(DefaultDialOptions(b.generateTokenFunc)...)}, options...)...)
 	serve(ctx, serveURL, result.Register)
 	log.FromContext(ctx).Infof("Registry listen on: %v", serveURL)
 	return &RegistryEntry{
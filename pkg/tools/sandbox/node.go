@@ -31,11 +31,13 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/authorize"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/clienturl"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/connect"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/common/drain"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/common/heal"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/adapters"
 	"github.com/networkservicemesh/sdk/pkg/tools/addressof"
 	"github.com/networkservicemesh/sdk/pkg/tools/grpcutils"
 	"github.com/networkservicemesh/sdk/pkg/tools/log"
+	"github.com/networkservicemesh/sdk/pkg/tools/opentracing"
 	"github.com/networkservicemesh/sdk/pkg/tools/token"
 )
 
@@ -97,10 +99,16 @@ func (n *Node) newEndpoint(
 	// 1. Create endpoint server
 	ep := endpoint.NewServer(ctx, generatorFunc,
 		endpoint.WithName(nse.Name),
+		endpoint.WithDrainOptions(drain.WithRegistryClient(registryClient, nse)),
 		endpoint.WithAdditionalFunctionality(additionalFunctionality...),
 	)
 
-	// 2. Start listening on URL
+	// 2. Register the Network Services
+	if err = n.registerNetworkServices(ctx, nse); err != nil {
+		return nil, err
+	}
+
+	// 3. Start listening on URL and register with the node registry client
 	u := &url.URL{Scheme: "tcp", Host: "127.0.0.1:0"}
 	if nse.Url != "" {
 		u, err = url.Parse(nse.Url)
@@ -109,16 +117,20 @@ func (n *Node) newEndpoint(
 		}
 	}
 
+	// Endpoint ctx cancel emulates the endpoint death, so the endpoint shouldn't be unregistered
 	ctx = log.Join(ctx, log.Empty())
-	serve(ctx, u, ep.Register)
-
-	nse.Url = u.String()
-
-	// 3. Register with the node registry client
-	err = n.registerEndpoint(ctx, nse, registryClient)
+	errCh, err := endpoint.ServeAndRegister(ctx, u, ep, nse, registryClient,
+		endpoint.WithServerOptions(opentracing.WithTracing()...),
+		endpoint.WithoutUnregister(),
+	)
 	if err != nil {
 		return nil, err
 	}
+	go func() {
+		if err := <-errCh; err != nil {
+			log.FromContext(ctx).Fatalf("An error during serve: %v", err.Error())
+		}
+	}()
 
 	log.FromContext(ctx).Infof("Started listen endpoint %s on %s.", nse.Name, u.String())
 
@@ -131,18 +143,12 @@ func (n *Node) RegisterEndpoint(ctx context.Context, nse *registryapi.NetworkSer
 }
 
 func (n *Node) registerEndpoint(ctx context.Context, nse *registryapi.NetworkServiceEndpoint, registryClient registryapi.NetworkServiceEndpointRegistryClient) error {
-	var err error
-	for _, nsName := range nse.NetworkServiceNames {
-		if _, err = n.NSRegistryClient.Register(ctx, &registryapi.NetworkService{
-			Name:    nsName,
-			Payload: payload.IP,
-		}); err != nil {
-			return err
-		}
+	if err := n.registerNetworkServices(ctx, nse); err != nil {
+		return err
 	}
 
-	var reg *registryapi.NetworkServiceEndpoint
-	if reg, err = registryClient.Register(ctx, nse); err != nil {
+	reg, err := registryClient.Register(ctx, nse)
+	if err != nil {
 		return err
 	}
 
@@ -152,6 +158,18 @@ func (n *Node) registerEndpoint(ctx context.Context, nse *registryapi.NetworkSer
 	return nil
 }
 
+func (n *Node) registerNetworkServices(ctx context.Context, nse *registryapi.NetworkServiceEndpoint) error {
+	for _, nsName := range nse.NetworkServiceNames {
+		if _, err := n.NSRegistryClient.Register(ctx, &registryapi.NetworkService{
+			Name:    nsName,
+			Payload: payload.IP,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // NewClient starts a new client and connects it to the node NSMgr
 func (n *Node) NewClient(
 	ctx context.Context,
@@ -0,0 +1,108 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sandbox
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/spiffejwt"
+)
+
+// NewTestSVID creates a self-signed X509-SVID with the spiffeID for testing
+func NewTestSVID(spiffeID string) (*x509svid.SVID, error) {
+	id, err := spiffeid.FromString(spiffeID)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		URIs:         []*url.URL{id.URL()},
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	cert, err := x509.ParseCertificate(certBytes)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &x509svid.SVID{
+		ID:           id,
+		Certificates: []*x509.Certificate{cert},
+		PrivateKey:   key,
+	}, nil
+}
+
+// WithPeerSVID returns the server side ctx of the gRPC call made by the svid owner: the peer has the svid TLS
+// certificate and the incoming metadata has the token signed by the svid
+func WithPeerSVID(ctx context.Context, svid *x509svid.SVID) (context.Context, error) {
+	tok, expireTime, err := spiffejwt.TokenGeneratorFunc(svid, time.Hour)(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = peer.NewContext(ctx, &peer.Peer{
+		AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{
+				PeerCertificates: svid.Certificates,
+			},
+		},
+	})
+	return metadata.NewIncomingContext(ctx, metadata.Pairs(
+		"nsm-client-token", tok,
+		"nsm-client-token-expires", expireTime.Format(time.RFC3339Nano),
+	)), nil
+}
+
+// WithPeerSpiffeID returns the server side ctx of the gRPC call made by the owner of a new test SVID with the spiffeID,
+// see WithPeerSVID
+func WithPeerSpiffeID(ctx context.Context, t *testing.T, spiffeID string) context.Context {
+	svid, err := NewTestSVID(spiffeID)
+	require.NoError(t, err)
+
+	ctx, err = WithPeerSVID(ctx, svid)
+	require.NoError(t, err)
+
+	return ctx
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/networkservicemesh/sdk/pkg/networkservice/chains/nsmgr"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/chains/nsmgrproxy"
 	"github.com/networkservicemesh/sdk/pkg/registry"
+	"github.com/networkservicemesh/sdk/pkg/registry/chains/memory"
 	"github.com/networkservicemesh/sdk/pkg/registry/common/dnsresolve"
 	"github.com/networkservicemesh/sdk/pkg/tools/token"
 )
@@ -37,10 +38,11 @@ import (
 type SupplyNSMgrProxyFunc func(context.Context, token.GeneratorFunc, ...nsmgrproxy.Option) endpoint.Endpoint
 
 // SupplyNSMgrFunc supplies NSMGR
-type SupplyNSMgrFunc func(context.Context, *registryapi.NetworkServiceEndpoint, networkservice.NetworkServiceServer, token.GeneratorFunc, grpc.ClientConnInterface, ...grpc.DialOption) nsmgr.Nsmgr
+type SupplyNSMgrFunc func(context.Context, *registryapi.NetworkServiceEndpoint, networkservice.NetworkServiceServer, token.GeneratorFunc, grpc.ClientConnInterface, ...nsmgr.Option) nsmgr.Nsmgr
 
-// SupplyRegistryFunc supplies Registry
-type SupplyRegistryFunc func(ctx context.Context, expiryDuration time.Duration, proxyRegistryURL *url.URL, options ...grpc.DialOption) registry.Registry
+// SupplyRegistryFunc supplies Registry. It used to take the gRPC Dial Options as the variadic argument, the suppliers
+// set with SetRegistrySupplier should pass them to memory.NewServer with memory.WithDialOptions(dialOptions...) now.
+type SupplyRegistryFunc func(ctx context.Context, expiryDuration time.Duration, proxyRegistryURL *url.URL, options ...memory.Option) registry.Registry
 
 // SupplyRegistryProxyFunc supplies registry proxy
 type SupplyRegistryProxyFunc func(ctx context.Context, dnsResolver dnsresolve.Resolver, handlingDNSDomain string, proxyNSMgrURL *url.URL, options ...grpc.DialOption) registry.Registry
@@ -68,13 +70,14 @@ type EndpointEntry struct {
 
 // Domain contains attached to domain nodes, registry
 type Domain struct {
-	Nodes         []*Node
-	NSMgrProxy    *EndpointEntry
-	Registry      *RegistryEntry
-	RegistryProxy *RegistryEntry
-	DNSResolver   dnsresolve.Resolver
-	Name          string
-	resources     []context.CancelFunc
+	Nodes            []*Node
+	NSMgrProxy       *EndpointEntry
+	Registry         *RegistryEntry
+	RegistryProxy    *RegistryEntry
+	RegistryReplicas []*RegistryEntry
+	DNSResolver      dnsresolve.Resolver
+	Name             string
+	resources        []context.CancelFunc
 }
 
 // NodeConfig keeps custom node configuration parameters
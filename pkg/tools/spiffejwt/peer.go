@@ -0,0 +1,85 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spiffejwt
+
+import (
+	"context"
+	"crypto/x509"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/token"
+)
+
+// PeerSpiffeID - returns the verified spiffe ID of the direct caller. It is taken from the peer TLS certificate
+// verified by the gRPC transport. If the caller has sent a token, the token should be signed by the certificate key,
+// not expired and have the same subject, otherwise an error is returned.
+func PeerSpiffeID(ctx context.Context) (spiffeid.ID, error) {
+	cert := peerCertificate(ctx)
+	if cert == nil {
+		return spiffeid.ID{}, errors.New("no peer TLS certificate found")
+	}
+
+	spiffeID, err := x509svid.IDFromCert(cert)
+	if err != nil {
+		return spiffeid.ID{}, errors.Wrap(err, "failed to get spiffe ID from the peer certificate")
+	}
+
+	tok, _, err := token.FromContext(ctx)
+	if err != nil {
+		return spiffeID, nil
+	}
+
+	claims := new(jwt.StandardClaims)
+	if _, err := new(jwt.Parser).ParseWithClaims(tok, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodECDSA); !ok {
+			return nil, errors.Errorf("unexpected signing method: %s", t.Method.Alg())
+		}
+		return cert.PublicKey, nil
+	}); err != nil {
+		return spiffeid.ID{}, errors.Wrap(err, "token is not signed by the peer certificate")
+	}
+	if claims.Subject != spiffeID.String() {
+		return spiffeid.ID{}, errors.Errorf("token subject %s doesn't match the peer spiffe ID %s", claims.Subject, spiffeID)
+	}
+
+	return spiffeID, nil
+}
+
+func peerCertificate(ctx context.Context) *x509.Certificate {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	var tlsInfo *credentials.TLSInfo
+	switch authInfo := p.AuthInfo.(type) {
+	case credentials.TLSInfo:
+		tlsInfo = &authInfo
+	case *credentials.TLSInfo:
+		tlsInfo = authInfo
+	}
+	if tlsInfo == nil || len(tlsInfo.State.PeerCertificates) == 0 {
+		return nil
+	}
+	return tlsInfo.State.PeerCertificates[0]
+}
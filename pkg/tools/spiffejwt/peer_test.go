@@ -0,0 +1,67 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spiffejwt_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/sandbox"
+	"github.com/networkservicemesh/sdk/pkg/tools/spiffejwt"
+)
+
+const (
+	spiffeIDA = "spiffe://test.com/a"
+	spiffeIDB = "spiffe://test.com/b"
+)
+
+func TestPeerSpiffeID(t *testing.T) {
+	svidA, err := sandbox.NewTestSVID(spiffeIDA)
+	require.NoError(t, err)
+	svidB, err := sandbox.NewTestSVID(spiffeIDB)
+	require.NoError(t, err)
+
+	// Token signed by the peer certificate
+	ctx, err := sandbox.WithPeerSVID(context.Background(), svidA)
+	require.NoError(t, err)
+
+	spiffeID, err := spiffejwt.PeerSpiffeID(ctx)
+	require.NoError(t, err)
+	require.Equal(t, spiffeIDA, spiffeID.String())
+
+	// Token signed by the other key
+	tok, expireTime, err := spiffejwt.TokenGeneratorFunc(svidB, time.Hour)(nil)
+	require.NoError(t, err)
+
+	forgedCtx := metadata.NewIncomingContext(ctx, metadata.Pairs(
+		"nsm-client-token", tok,
+		"nsm-client-token-expires", expireTime.Format(time.RFC3339Nano),
+	))
+	_, err = spiffejwt.PeerSpiffeID(forgedCtx)
+	require.Error(t, err)
+
+	// No peer certificate
+	_, err = spiffejwt.PeerSpiffeID(metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"nsm-client-token", tok,
+		"nsm-client-token-expires", expireTime.Format(time.RFC3339Nano),
+	)))
+	require.Error(t, err)
+}
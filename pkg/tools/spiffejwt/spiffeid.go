@@ -0,0 +1,33 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spiffejwt
+
+import (
+	"github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+)
+
+// SpiffeIDFromToken - returns the spiffe ID from the subject of the JWT token. Token signature is not verified, so
+// the result should be used only for the tokens already validated by the authorization chain elements.
+func SpiffeIDFromToken(token string) (spiffeid.ID, error) {
+	claims := new(jwt.StandardClaims)
+	if _, _, err := new(jwt.Parser).ParseUnverified(token, claims); err != nil {
+		return spiffeid.ID{}, errors.Wrap(err, "failed to parse token")
+	}
+	return spiffeid.FromString(claims.Subject)
+}
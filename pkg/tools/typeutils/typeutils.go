@@ -1,5 +1,7 @@
 // Copyright (c) 2020 Cisco Systems, Inc.
 //
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
 // SPDX-License-Identifier: Apache-2.0
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
@@ -25,10 +27,15 @@ import (
 
 // GetFuncName - returns the function name from the passed value (interface) and method name
 func GetFuncName(value interface{}, methodName string) string {
+	return fmt.Sprintf("%s.%s", GetTypeName(value), methodName)
+}
+
+// GetTypeName - returns the type name with the package path from the passed value (interface)
+func GetTypeName(value interface{}) string {
 	v := reflect.ValueOf(value)
 	if v.Kind() == reflect.Ptr {
 		v = v.Elem()
 	}
 	pkgPath := strings.TrimPrefix(v.Type().PkgPath(), "github.com/networkservicemesh/")
-	return fmt.Sprintf("%s/%s.%s", pkgPath, v.Type().Name(), methodName)
+	return fmt.Sprintf("%s/%s", pkgPath, v.Type().Name())
 }
@@ -0,0 +1,33 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package walstore
+
+import "time"
+
+type storeOptions struct {
+	snapshotInterval time.Duration
+}
+
+// Option is an option pattern for New
+type Option func(o *storeOptions)
+
+// WithSnapshotInterval sets how often the write-ahead log is compacted into the snapshot
+func WithSnapshotInterval(snapshotInterval time.Duration) Option {
+	return func(o *storeOptions) {
+		o.snapshotInterval = snapshotInterval
+	}
+}
@@ -0,0 +1,327 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package walstore provides a store of the proto messages persisted on the local disk with the write-ahead log and
+// the periodic snapshots
+package walstore
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/clock"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+const defaultSnapshotInterval = time.Minute
+
+const (
+	snapshotFileName = "snapshot"
+	walFileName      = "wal"
+	tmpSuffix        = ".tmp"
+)
+
+const (
+	putOp    = "put"
+	deleteOp = "delete"
+)
+
+// record is a single line of the write-ahead log and of the snapshot
+type record struct {
+	Op     string          `json:"op"`
+	Name   string          `json:"name"`
+	Entity json.RawMessage `json:"entity,omitempty"`
+}
+
+// Store keeps the entities in memory and persists every change to the append-only write-ahead log. The log is
+// periodically compacted into the snapshot.
+type Store struct {
+	ctx       context.Context
+	dir       string
+	newEntity func() proto.Message
+
+	lock     sync.Mutex
+	entities map[string]proto.Message
+	wal      *os.File
+	walSize  int
+}
+
+// New creates a new Store in the dir loading the entities stored there before. newEntity should return an empty
+// entity to unmarshal the stored one. Store is closed when ctx is done.
+func New(ctx context.Context, dir string, newEntity func() proto.Message, options ...Option) (*Store, error) {
+	o := &storeOptions{
+		snapshotInterval: defaultSnapshotInterval,
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrapf(err, "failed to create store directory: %s", dir)
+	}
+
+	s := &Store{
+		ctx:       ctx,
+		dir:       dir,
+		newEntity: newEntity,
+		entities:  make(map[string]proto.Message),
+	}
+
+	if err := s.load(snapshotFileName); err != nil {
+		return nil, err
+	}
+	if err := s.load(walFileName); err != nil {
+		return nil, err
+	}
+
+	wal, err := os.OpenFile(filepath.Join(dir, walFileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open write-ahead log: %s", dir)
+	}
+	s.wal = wal
+
+	if err := s.Snapshot(); err != nil {
+		_ = wal.Close()
+		return nil, err
+	}
+
+	go s.snapshotLoop(clock.FromContext(ctx), o.snapshotInterval)
+
+	return s, nil
+}
+
+// load applies all the records from the file. Log can be cut at any point on crash, so a broken record is treated
+// as the end of the file.
+func (s *Store) load(fileName string) error {
+	file, err := os.Open(filepath.Join(s.dir, fileName))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to open %s", fileName)
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 16*1024*1024)
+	for scanner.Scan() {
+		r := new(record)
+		if err := json.Unmarshal(scanner.Bytes(), r); err != nil {
+			log.FromContext(s.ctx).Warnf("broken record in %s, skipping the rest of the file: %s", fileName, err.Error())
+			return nil
+		}
+		if err := s.apply(r); err != nil {
+			log.FromContext(s.ctx).Warnf("broken record in %s, skipping the rest of the file: %s", fileName, err.Error())
+			return nil
+		}
+	}
+	return errors.Wrapf(scanner.Err(), "failed to read %s", fileName)
+}
+
+func (s *Store) apply(r *record) error {
+	switch r.Op {
+	case putOp:
+		entity := s.newEntity()
+		if err := protojson.Unmarshal(r.Entity, entity); err != nil {
+			return errors.Wrapf(err, "failed to unmarshal entity: %s", r.Name)
+		}
+		s.entities[r.Name] = entity
+	case deleteOp:
+		delete(s.entities, r.Name)
+	default:
+		return errors.Errorf("unknown operation: %s", r.Op)
+	}
+	return nil
+}
+
+// List returns all the stored entities
+func (s *Store) List() []proto.Message {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	entities := make([]proto.Message, 0, len(s.entities))
+	for _, entity := range s.entities {
+		entities = append(entities, proto.Clone(entity))
+	}
+	return entities
+}
+
+// Get returns the entity stored by the name
+func (s *Store) Get(name string) (proto.Message, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	entity, ok := s.entities[name]
+	if !ok {
+		return nil, false
+	}
+	return proto.Clone(entity), true
+}
+
+// Put stores the entity by the name and persists it
+func (s *Store) Put(name string, entity proto.Message) error {
+	data, err := protojson.Marshal(entity)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal entity: %s", name)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.write(&record{Op: putOp, Name: name, Entity: data}); err != nil {
+		return err
+	}
+	s.entities[name] = proto.Clone(entity)
+	return nil
+}
+
+// Delete deletes the entity by the name
+func (s *Store) Delete(name string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.entities[name]; !ok {
+		return nil
+	}
+	if err := s.write(&record{Op: deleteOp, Name: name}); err != nil {
+		return err
+	}
+	delete(s.entities, name)
+	return nil
+}
+
+func (s *Store) write(r *record) error {
+	if s.ctx.Err() != nil {
+		return errors.New("store is closed")
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal record: %s", r.Name)
+	}
+	if _, err := s.wal.Write(append(data, '\n')); err != nil {
+		return errors.Wrap(err, "failed to write to write-ahead log")
+	}
+	if err := s.wal.Sync(); err != nil {
+		return errors.Wrap(err, "failed to sync write-ahead log")
+	}
+	s.walSize++
+	return nil
+}
+
+// Snapshot writes all the entities to the snapshot file and truncates the write-ahead log. Snapshot is replaced
+// atomically, so a crash at any point leaves either the old snapshot with the full log or the new one.
+func (s *Store) Snapshot() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	tmpName := filepath.Join(s.dir, snapshotFileName+tmpSuffix)
+	file, err := os.OpenFile(tmpName, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to create snapshot")
+	}
+
+	writer := bufio.NewWriter(file)
+	for name, entity := range s.entities {
+		if err = writeRecord(writer, name, entity); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmpName)
+		return errors.Wrap(err, "failed to write snapshot")
+	}
+
+	if err := os.Rename(tmpName, filepath.Join(s.dir, snapshotFileName)); err != nil {
+		return errors.Wrap(err, "failed to replace snapshot")
+	}
+	if err := syncDir(s.dir); err != nil {
+		return err
+	}
+	if err := s.wal.Truncate(0); err != nil {
+		return errors.Wrap(err, "failed to truncate write-ahead log")
+	}
+	s.walSize = 0
+	return nil
+}
+
+func writeRecord(writer *bufio.Writer, name string, entity proto.Message) error {
+	data, err := protojson.Marshal(entity)
+	if err != nil {
+		return err
+	}
+	if data, err = json.Marshal(&record{Op: putOp, Name: name, Entity: data}); err != nil {
+		return err
+	}
+	if _, err = writer.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return nil
+}
+
+func syncDir(dir string) error {
+	file, err := os.Open(filepath.Clean(dir))
+	if err != nil {
+		return errors.Wrapf(err, "failed to open store directory: %s", dir)
+	}
+	defer func() { _ = file.Close() }()
+
+	return errors.Wrapf(file.Sync(), "failed to sync store directory: %s", dir)
+}
+
+func (s *Store) snapshotLoop(clk clock.Clock, interval time.Duration) {
+	ticker := clk.Ticker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			s.lock.Lock()
+			_ = s.wal.Close()
+			s.lock.Unlock()
+			return
+		case <-ticker.C():
+			s.lock.Lock()
+			walSize := s.walSize
+			s.lock.Unlock()
+
+			if walSize == 0 {
+				continue
+			}
+			if err := s.Snapshot(); err != nil {
+				log.FromContext(s.ctx).Errorf("failed to snapshot %s: %s", s.dir, err.Error())
+			}
+		}
+	}
+}
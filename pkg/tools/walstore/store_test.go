@@ -0,0 +1,184 @@
+// Copyright (c) 2021 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package walstore_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/walstore"
+)
+
+func newStore(ctx context.Context, t *testing.T, dir string) *walstore.Store {
+	s, err := walstore.New(ctx, dir, func() proto.Message { return new(wrapperspb.StringValue) },
+		walstore.WithSnapshotInterval(time.Hour))
+	require.NoError(t, err)
+	return s
+}
+
+// reopen closes the store and opens it again from the same dir
+func reopen(t *testing.T, cancel context.CancelFunc, dir string) (*walstore.Store, context.CancelFunc) {
+	cancel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return newStore(ctx, t, dir), cancel
+}
+
+func requireEntities(t *testing.T, s *walstore.Store, expected map[string]string) {
+	actual := make(map[string]string)
+	for name := range expected {
+		if entity, ok := s.Get(name); ok {
+			actual[name] = entity.(*wrapperspb.StringValue).GetValue()
+		}
+	}
+	require.Equal(t, expected, actual)
+	require.Len(t, s.List(), len(expected))
+}
+
+func appendToFile(t *testing.T, fileName, data string) {
+	file, err := os.OpenFile(filepath.Clean(fileName), os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err)
+	_, err = file.WriteString(data)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+}
+
+func TestStore_SnapshotAndWALReplay(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	dir := t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := newStore(ctx, t, dir)
+
+	require.NoError(t, s.Put("a", wrapperspb.String("a-1")))
+	require.NoError(t, s.Put("b", wrapperspb.String("b-1")))
+	require.NoError(t, s.Snapshot())
+
+	walInfo, err := os.Stat(filepath.Join(dir, "wal"))
+	require.NoError(t, err)
+	require.Zero(t, walInfo.Size())
+
+	// Changes after the snapshot are only in the write-ahead log
+	require.NoError(t, s.Put("b", wrapperspb.String("b-2")))
+	require.NoError(t, s.Put("c", wrapperspb.String("c-1")))
+	require.NoError(t, s.Delete("a"))
+
+	s, cancel = reopen(t, cancel, dir)
+	defer cancel()
+
+	requireEntities(t, s, map[string]string{"b": "b-2", "c": "c-1"})
+}
+
+func TestStore_BrokenWALTail(t *testing.T) {
+	samples := []struct {
+		name string
+		tail string
+	}{
+		{
+			name: "truncated record",
+			tail: `{"op":"put","name":"c","ent`,
+		},
+		{
+			name: "corrupt record",
+			tail: "\x00\x00\x00\n" + `{"op":"put","name":"c","entity":"c-1"}` + "\n",
+		},
+		{
+			name: "unknown operation",
+			tail: `{"op":"move","name":"a"}` + "\n" + `{"op":"put","name":"c","entity":"c-1"}` + "\n",
+		},
+		{
+			name: "broken entity",
+			tail: `{"op":"put","name":"c","entity":{"value":1}}` + "\n",
+		},
+	}
+
+	for i := range samples {
+		sample := samples[i]
+		t.Run(sample.name, func(t *testing.T) {
+			defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+			dir := t.TempDir()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			s := newStore(ctx, t, dir)
+
+			require.NoError(t, s.Put("a", wrapperspb.String("a-1")))
+			require.NoError(t, s.Put("b", wrapperspb.String("b-1")))
+
+			// Crash while writing the next record
+			cancel()
+			appendToFile(t, filepath.Join(dir, "wal"), sample.tail)
+
+			// Records before the broken one are restored, the rest of the log is dropped
+			ctx, cancel = context.WithCancel(context.Background())
+			s = newStore(ctx, t, dir)
+			requireEntities(t, s, map[string]string{"a": "a-1", "b": "b-1"})
+
+			// Broken tail doesn't hide the new records
+			require.NoError(t, s.Put("d", wrapperspb.String("d-1")))
+
+			s, cancel = reopen(t, cancel, dir)
+			defer cancel()
+
+			requireEntities(t, s, map[string]string{"a": "a-1", "b": "b-1", "d": "d-1"})
+		})
+	}
+}
+
+func TestStore_SnapshotAtomicity(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	dir := t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := newStore(ctx, t, dir)
+
+	require.NoError(t, s.Put("a", wrapperspb.String("a-1")))
+	require.NoError(t, s.Snapshot())
+	require.NoError(t, s.Put("b", wrapperspb.String("b-1")))
+
+	// Snapshot fails before replacing the old one
+	tmpName := filepath.Join(dir, "snapshot.tmp")
+	require.NoError(t, os.Mkdir(tmpName, 0700))
+	require.Error(t, s.Snapshot())
+
+	// Write-ahead log is kept, so the old snapshot with the log has all the entities
+	walInfo, err := os.Stat(filepath.Join(dir, "wal"))
+	require.NoError(t, err)
+	require.NotZero(t, walInfo.Size())
+
+	// Crash leaves the partially written snapshot
+	cancel()
+	require.NoError(t, os.Remove(tmpName))
+	require.NoError(t, ioutil.WriteFile(tmpName, []byte(`{"op":"delete","name":"a"}`), 0600))
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	s = newStore(ctx, t, dir)
+
+	requireEntities(t, s, map[string]string{"a": "a-1", "b": "b-1"})
+}